@@ -0,0 +1,190 @@
+package coordconv
+
+import (
+	"errors"
+	"math"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// AlbersEqualAreaConic provides conversions between geodetic coordinates
+// (latitude and longitude) and Albers Equal Area Conic projection coordinates
+// (easting and northing).  The formulas are those of EPSG Guidance Note 7-2.
+type AlbersEqualAreaConic struct {
+	semiMajorAxis float64
+	flattening    float64
+	authalic      authalic
+
+	// Albers Equal Area Conic projection Parameters
+	albersOriginLat     float64 // Latitude of origin in radians
+	albersOriginLong    float64 // Longitude of origin in radians
+	albersStdParallel1  float64 // First standard parallel in radians
+	albersStdParallel2  float64 // Second standard parallel in radians
+	albersFalseEasting  float64 // False easting in meters
+	albersFalseNorthing float64 // False northing in meters
+	albersN             float64 // Cone constant
+	albersC             float64 // Snyder's C
+	albersRho0          float64 // Radius to the origin
+	albersAOverN        float64 // Semi-major axis / n
+}
+
+// NewAlbersEqualAreaConic constructs an Albers Equal Area Conic converter with
+// true scale along two standard parallels.  The false easting and northing
+// are at the latitude of origin on the central meridian.
+func NewAlbersEqualAreaConic(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+	originLatitude, standardParallel1, standardParallel2,
+	falseEasting, falseNorthing float64) (*AlbersEqualAreaConic, error) {
+	invF := 1 / ellipsoidFlattening
+	if ellipsoidSemiMajorAxis <= 0.0 {
+		return nil, errors.New("Semi-major axis must be greater than zero")
+	}
+	if (invF < 250) || (invF > 350) {
+		return nil, errors.New("Inverse flattening must be between 250 and 350")
+	}
+	if (originLatitude < -math.Pi/2) || (originLatitude > math.Pi/2) {
+		return nil, errors.New("Origin Latitude out of range")
+	}
+	if (centralMeridian < -math.Pi) || (centralMeridian > 2*math.Pi) {
+		return nil, errors.New("Origin Longitude out of range")
+	}
+	if (standardParallel1 <= -math.Pi/2) || (standardParallel1 >= math.Pi/2) {
+		return nil, errors.New("Standard Parallel 1 out of range")
+	}
+	if (standardParallel2 <= -math.Pi/2) || (standardParallel2 >= math.Pi/2) {
+		return nil, errors.New("Standard Parallel 2 out of range")
+	}
+	if (standardParallel1 == 0) && (standardParallel2 == 0) {
+		return nil, errors.New("Standard Parallels cannot both be zero")
+	}
+	if standardParallel1 == -standardParallel2 {
+		return nil, errors.New("Standard Parallels cannot be opposite latitudes")
+	}
+
+	if centralMeridian > math.Pi {
+		centralMeridian -= 2 * math.Pi
+	}
+	a := &AlbersEqualAreaConic{
+		semiMajorAxis:       ellipsoidSemiMajorAxis,
+		flattening:          ellipsoidFlattening,
+		authalic:            newAuthalic(ellipsoidFlattening),
+		albersOriginLat:     originLatitude,
+		albersOriginLong:    centralMeridian,
+		albersStdParallel1:  standardParallel1,
+		albersStdParallel2:  standardParallel2,
+		albersFalseEasting:  falseEasting,
+		albersFalseNorthing: falseNorthing,
+	}
+
+	m1 := a.authalic.m(standardParallel1)
+	q1 := a.authalic.q(standardParallel1)
+	if standardParallel1 == standardParallel2 {
+		a.albersN = math.Sin(standardParallel1)
+	} else {
+		m2 := a.authalic.m(standardParallel2)
+		q2 := a.authalic.q(standardParallel2)
+		a.albersN = (m1*m1 - m2*m2) / (q2 - q1)
+	}
+	a.albersC = m1*m1 + a.albersN*q1
+	a.albersAOverN = a.semiMajorAxis / a.albersN
+	a.albersRho0 = a.albersRho(originLatitude)
+	return a, nil
+}
+
+// NewAlbersEqualAreaConicEllipsoid constructs an Albers Equal Area Conic
+// converter for the given ellipsoid.
+func NewAlbersEqualAreaConicEllipsoid(ellipsoid Ellipsoid, centralMeridian,
+	originLatitude, standardParallel1, standardParallel2,
+	falseEasting, falseNorthing float64) (*AlbersEqualAreaConic, error) {
+	return NewAlbersEqualAreaConic(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, centralMeridian,
+		originLatitude, standardParallel1, standardParallel2, falseEasting, falseNorthing)
+}
+
+// ConvertFromGeodetic converts geodetic coordinates (latitude and longitude) to
+// Albers Equal Area Conic coordinates (easting and northing), according to the
+// current ellipsoid and Albers Equal Area Conic projection parameters.
+func (a *AlbersEqualAreaConic) ConvertFromGeodetic(geodeticCoordinates s2.LatLng) (MapCoords, error) {
+	longitude := geodeticCoordinates.Lng.Radians()
+	latitude := geodeticCoordinates.Lat.Radians()
+
+	if (latitude < -math.Pi/2) || (latitude > math.Pi/2) {
+		return MapCoords{}, errors.New("latitude out of range")
+	}
+	if (longitude < -math.Pi) || (longitude > 2*math.Pi) {
+		return MapCoords{}, errors.New("longitude out of range")
+	}
+
+	dlam := longitude - a.albersOriginLong
+	if dlam > math.Pi {
+		dlam -= 2 * math.Pi
+	}
+	if dlam < -math.Pi {
+		dlam += 2 * math.Pi
+	}
+	rho := a.albersRho(latitude)
+	theta := a.albersN * dlam
+	return MapCoords{
+		Easting:  a.albersFalseEasting + rho*math.Sin(theta),
+		Northing: a.albersFalseNorthing + a.albersRho0 - rho*math.Cos(theta),
+	}, nil
+}
+
+// ConvertToGeodetic converts Albers Equal Area Conic coordinates (easting and
+// northing) to geodetic coordinates (latitude and longitude) according to the
+// current ellipsoid and Albers Equal Area Conic projection parameters.
+func (a *AlbersEqualAreaConic) ConvertToGeodetic(mapProjectionCoordinates MapCoords) (s2.LatLng, error) {
+	dx := mapProjectionCoordinates.Easting - a.albersFalseEasting
+	dy := a.albersRho0 - (mapProjectionCoordinates.Northing - a.albersFalseNorthing)
+	if a.albersN < 0 {
+		dx = -dx
+		dy = -dy
+	}
+	rho := math.Hypot(dx, dy)
+
+	rhoOverA := rho / a.semiMajorAxis
+	q := (a.albersC - rhoOverA*rhoOverA*a.albersN*a.albersN) / a.albersN
+	if math.IsNaN(q) || (math.Abs(q) > a.authalic.qp*(1+1.0e-12)) {
+		return s2.LatLng{}, errors.New("Point is outside of projection area")
+	}
+	beta := math.Asin(math.Max(-1, math.Min(1, q/a.authalic.qp)))
+	latitude := a.authalic.latitude(beta)
+
+	var longitude float64
+	if rho == 0 {
+		longitude = a.albersOriginLong
+	} else {
+		theta := math.Atan2(dx, dy)
+		if math.Abs(theta) > math.Abs(a.albersN)*math.Pi*(1+1.0e-12) {
+			return s2.LatLng{}, errors.New("Point is outside of projection area")
+		}
+		longitude = a.albersOriginLong + theta/a.albersN
+	}
+	if longitude > math.Pi {
+		longitude -= 2 * math.Pi
+	} else if longitude < -math.Pi {
+		longitude += 2 * math.Pi
+	}
+	return s2.LatLng{Lat: s1.Angle(latitude), Lng: s1.Angle(longitude)}, nil
+}
+
+// albersRho returns the distance on the grid from the apex of the cone to a
+// point at the given latitude.
+func (a *AlbersEqualAreaConic) albersRho(latitude float64) float64 {
+	return a.albersAOverN * math.Sqrt(math.Max(0, a.albersC-a.albersN*a.authalic.q(latitude)))
+}
+
+// Parameters returns the ellipsoid and Albers Equal Area Conic projection
+// parameters.
+func (a *AlbersEqualAreaConic) Parameters() ProjectionParameters {
+	return ProjectionParameters{
+		SemiMajorAxis:     a.semiMajorAxis,
+		Flattening:        a.flattening,
+		CentralMeridian:   a.albersOriginLong,
+		OriginLatitude:    a.albersOriginLat,
+		StandardParallel:  a.albersStdParallel1,
+		StandardParallel2: a.albersStdParallel2,
+		FalseEasting:      a.albersFalseEasting,
+		FalseNorthing:     a.albersFalseNorthing,
+		ScaleFactor:       1.0,
+	}
+}
@@ -0,0 +1,90 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestAlbersEqualAreaConic(t *testing.T) {
+	clarke1866, _ := coordconv.EllipsoidByCode("CC")
+
+	// Snyder, Map Projections: A Working Manual, the example for the ellipsoid
+	// cited by EPSG Guidance Note 7-2
+	albers, err := coordconv.NewAlbersEqualAreaConicEllipsoid(clarke1866,
+		-96*math.Pi/180, 23*math.Pi/180, 29.5*math.Pi/180, 45.5*math.Pi/180, 0, 0)
+	if err != nil {
+		t.Fatalf("error creating albers equal area conic converter: %s", err)
+	}
+	geo := s2.LatLngFromDegrees(35, -75)
+	expected := coordconv.MapCoords{Easting: 1885472.7, Northing: 1535925.0}
+
+	const epsilon = 0.1
+	mc, err := albers.ConvertFromGeodetic(geo)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(mc.Easting-expected.Easting) > epsilon ||
+		math.Abs(mc.Northing-expected.Northing) > epsilon {
+		t.Errorf("expected %v, got %v", expected, mc)
+	}
+	geo2, err := albers.ConvertToGeodetic(expected)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > epsilon {
+		t.Errorf("expected %s, got %s", geo, geo2)
+	}
+}
+
+func TestAlbersEqualAreaConicRoundTrip(t *testing.T) {
+	for _, sign := range []float64{-1, 1} {
+		albers, err := coordconv.NewAlbersEqualAreaConic(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+			-96*math.Pi/180, sign*23*math.Pi/180, sign*29.5*math.Pi/180, sign*45.5*math.Pi/180, 0, 0)
+		if err != nil {
+			t.Fatalf("error creating albers equal area conic converter: %s", err)
+		}
+		for lng := -180.0; lng < 180; lng += 10 {
+			for lat := -90.0; lat <= 90; lat += 10 {
+				geo := s2.LatLngFromDegrees(lat, lng)
+				mc, err := albers.ConvertFromGeodetic(geo)
+				if err != nil {
+					t.Fatalf("expected no error at %s, got %s", geo, err)
+				}
+				geo2, err := albers.ConvertToGeodetic(mc)
+				if err != nil {
+					t.Fatalf("expected no error in round trip at %s, got %s", geo, err)
+				}
+				if math.Abs(lat) < 90 && geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > 1e-3 {
+					t.Errorf("expected %s, got %s", geo, geo2)
+				}
+				if math.Abs(lat) < 90 && math.Abs(lng) < 170 {
+					checkEqualArea(t, "albers equal area conic", albers.ConvertFromGeodetic,
+						ellipsoidSemiMajorAxis, ellipsoidFlattening, geo)
+				}
+			}
+		}
+
+		params := albers.Parameters()
+		if params.StandardParallel != sign*29.5*math.Pi/180 || params.StandardParallel2 != sign*45.5*math.Pi/180 {
+			t.Errorf("expected the standard parallels in the parameters, got %+v", params)
+		}
+	}
+}
+
+func TestAlbersEqualAreaConicInvalid(t *testing.T) {
+	if _, err := coordconv.NewAlbersEqualAreaConic(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		0, 0, math.Pi/6, -math.Pi/6, 0, 0); err == nil {
+		t.Errorf("expected an error for opposite standard parallels")
+	}
+	if _, err := coordconv.NewAlbersEqualAreaConic(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		0, 0, math.Pi/2, math.Pi/6, 0, 0); err == nil {
+		t.Errorf("expected an error for a standard parallel at the pole")
+	}
+	if _, err := coordconv.NewAlbersEqualAreaConic(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		0, 0, math.Pi/6, math.Pi/6, 0, 0); err != nil {
+		t.Errorf("expected no error for a single standard parallel, got %s", err)
+	}
+}
@@ -0,0 +1,67 @@
+package coordconv
+
+import "math"
+
+// authalic converts between geodetic and authalic latitudes, the latitudes of
+// the sphere with the same surface area as the ellipsoid, for the equal-area
+// projections.
+type authalic struct {
+	es     float64    // Eccentricity of ellipsoid
+	es2    float64    // Eccentricity squared
+	qp     float64    // q at the pole
+	coeffs [6]float64 // Series for the geodetic latitude in the authalic latitude
+}
+
+func newAuthalic(flattening float64) authalic {
+	au := authalic{}
+	au.es2 = 2*flattening - flattening*flattening
+	au.es = math.Sqrt(au.es2)
+	au.qp = au.q(math.Pi / 2)
+
+	// The series is to sixth order in the third flattening (Karney 2023, "On
+	// auxiliary latitudes"), accurate to a micrometer for the earth.
+	n := flattening / (2 - flattening)
+	n2 := n * n
+	n3 := n2 * n
+	n4 := n3 * n
+	n5 := n4 * n
+	n6 := n5 * n
+	au.coeffs[0] = 4.0/3*n + 4.0/45*n2 - 16.0/35*n3 - 2582.0/14175*n4 +
+		60136.0/467775*n5 + 28112932.0/212837625*n6
+	au.coeffs[1] = 46.0/45*n2 + 152.0/945*n3 - 11966.0/14175*n4 - 21016.0/51975*n5 +
+		251310128.0/638512875*n6
+	au.coeffs[2] = 3044.0/2835*n3 + 3802.0/14175*n4 - 94388.0/66825*n5 - 8797648.0/10945935*n6
+	au.coeffs[3] = 6059.0/4725*n4 + 41072.0/93555*n5 - 1472637812.0/638512875*n6
+	au.coeffs[4] = 768272.0/467775*n5 + 455935736.0/638512875*n6
+	au.coeffs[5] = 4210684958.0 / 1915538625 * n6
+	return au
+}
+
+// q returns Snyder's q, proportional to the area between the equator and the
+// parallel at the given latitude.
+func (au *authalic) q(latitude float64) float64 {
+	sinLat := math.Sin(latitude)
+	esSin := au.es * sinLat
+	return (1 - au.es2) * (sinLat/(1-esSin*esSin) + aTanH(esSin)/au.es)
+}
+
+// beta returns the authalic latitude of the given latitude.
+func (au *authalic) beta(latitude float64) float64 {
+	return math.Asin(math.Max(-1, math.Min(1, au.q(latitude)/au.qp)))
+}
+
+// latitude returns the geodetic latitude of the given authalic latitude.
+func (au *authalic) latitude(beta float64) float64 {
+	latitude := beta
+	for k, c := range au.coeffs {
+		latitude += c * math.Sin(2*float64(k+1)*beta)
+	}
+	return latitude
+}
+
+// m returns the radius of the parallel at the given latitude, divided by the
+// semi-major axis.
+func (au *authalic) m(latitude float64) float64 {
+	esSin := au.es * math.Sin(latitude)
+	return math.Cos(latitude) / math.Sqrt(1-esSin*esSin)
+}
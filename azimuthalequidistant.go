@@ -0,0 +1,180 @@
+package coordconv
+
+import (
+	"errors"
+	"math"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// AzimuthalEquidistant provides conversions between geodetic coordinates
+// (latitude and longitude) and ellipsoidal Azimuthal Equidistant projection
+// coordinates (easting and northing).  The distance and azimuth of every point
+// from the centre are those of the geodesic from the centre to the point, so
+// circles about the centre are range rings.
+type AzimuthalEquidistant struct {
+	semiMajorAxis float64
+	flattening    float64
+	geodesic      *geodesic
+
+	// Azimuthal Equidistant projection Parameters
+	azeqOriginLat     float64 // Latitude of the centre in radians
+	azeqOriginLong    float64 // Longitude of the centre in radians
+	azeqFalseEasting  float64 // False easting in meters
+	azeqFalseNorthing float64 // False northing in meters
+	azeqMaxDistance   float64 // Length of half a meridian in meters
+}
+
+// NewAzimuthalEquidistant constructs an Azimuthal Equidistant converter
+// centred on the given point.  The false easting and northing are at the
+// centre.
+func NewAzimuthalEquidistant(ellipsoidSemiMajorAxis, ellipsoidFlattening float64, center s2.LatLng,
+	falseEasting, falseNorthing float64) (*AzimuthalEquidistant, error) {
+	if err := checkAzimuthalCenter(ellipsoidSemiMajorAxis, ellipsoidFlattening, center); err != nil {
+		return nil, err
+	}
+	a := &AzimuthalEquidistant{
+		semiMajorAxis:     ellipsoidSemiMajorAxis,
+		flattening:        ellipsoidFlattening,
+		geodesic:          newGeodesic(ellipsoidSemiMajorAxis, ellipsoidFlattening),
+		azeqOriginLat:     center.Lat.Radians(),
+		azeqOriginLong:    normalizeOriginLongitude(center.Lng.Radians()),
+		azeqFalseEasting:  falseEasting,
+		azeqFalseNorthing: falseNorthing,
+	}
+	_, a.azeqMaxDistance, _, _, _, _, _ = a.geodesic.inverse(90, 0, -90, 0)
+	return a, nil
+}
+
+// NewAzimuthalEquidistantEllipsoid constructs an Azimuthal Equidistant
+// converter for the given ellipsoid.
+func NewAzimuthalEquidistantEllipsoid(ellipsoid Ellipsoid, center s2.LatLng,
+	falseEasting, falseNorthing float64) (*AzimuthalEquidistant, error) {
+	return NewAzimuthalEquidistant(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, center,
+		falseEasting, falseNorthing)
+}
+
+// NewAzimuthalEquidistantUTM constructs an Azimuthal Equidistant converter
+// centred on a UTM coordinate on the given ellipsoid.
+func NewAzimuthalEquidistantUTM(ellipsoid Ellipsoid, center UTMCoord,
+	falseEasting, falseNorthing float64) (*AzimuthalEquidistant, error) {
+	geodeticCenter, err := utmAzimuthalCenter(ellipsoid, center)
+	if err != nil {
+		return nil, err
+	}
+	return NewAzimuthalEquidistantEllipsoid(ellipsoid, geodeticCenter, falseEasting, falseNorthing)
+}
+
+// NewAzimuthalEquidistantMGRS constructs an Azimuthal Equidistant converter
+// centred on an MGRS coordinate string on the given ellipsoid.
+func NewAzimuthalEquidistantMGRS(ellipsoid Ellipsoid, center string,
+	falseEasting, falseNorthing float64) (*AzimuthalEquidistant, error) {
+	geodeticCenter, err := mgrsAzimuthalCenter(ellipsoid, center)
+	if err != nil {
+		return nil, err
+	}
+	return NewAzimuthalEquidistantEllipsoid(ellipsoid, geodeticCenter, falseEasting, falseNorthing)
+}
+
+// ConvertFromGeodetic converts geodetic coordinates (latitude and longitude) to
+// Azimuthal Equidistant coordinates (easting and northing), according to the
+// current ellipsoid and Azimuthal Equidistant projection parameters.
+func (a *AzimuthalEquidistant) ConvertFromGeodetic(geodeticCoordinates s2.LatLng) (MapCoords, error) {
+	longitude := geodeticCoordinates.Lng.Radians()
+	latitude := geodeticCoordinates.Lat.Radians()
+
+	if (latitude < -math.Pi/2) || (latitude > math.Pi/2) {
+		return MapCoords{}, errors.New("latitude out of range")
+	}
+	if (longitude < -math.Pi) || (longitude > 2*math.Pi) {
+		return MapCoords{}, errors.New("longitude out of range")
+	}
+
+	_, s12, azi1, _, _, _, _ := a.geodesic.inverse(a.azeqOriginLat/degree, a.azeqOriginLong/degree,
+		latitude/degree, longitude/degree)
+	sinAzi, cosAzi := sincosd(azi1)
+	return MapCoords{
+		Easting:  a.azeqFalseEasting + s12*sinAzi,
+		Northing: a.azeqFalseNorthing + s12*cosAzi,
+	}, nil
+}
+
+// ConvertToGeodetic converts Azimuthal Equidistant coordinates (easting and
+// northing) to geodetic coordinates (latitude and longitude) according to the
+// current ellipsoid and Azimuthal Equidistant projection parameters.  Points
+// further from the centre than half a meridian are out of range.
+func (a *AzimuthalEquidistant) ConvertToGeodetic(mapProjectionCoordinates MapCoords) (s2.LatLng, error) {
+	dx := mapProjectionCoordinates.Easting - a.azeqFalseEasting
+	dy := mapProjectionCoordinates.Northing - a.azeqFalseNorthing
+
+	s12 := math.Hypot(dx, dy)
+	if math.IsNaN(s12) || (s12 > a.azeqMaxDistance*(1+1.0e-12)) {
+		return s2.LatLng{}, errors.New("Point is outside of projection area")
+	}
+	lat2, lon2, _, _, _, _ := a.geodesic.direct(a.azeqOriginLat/degree, a.azeqOriginLong/degree,
+		atan2d(dx, dy), s12)
+	return s2.LatLng{Lat: s1.Angle(lat2 * degree), Lng: s1.Angle(lon2 * degree)}, nil
+}
+
+// Parameters returns the ellipsoid and Azimuthal Equidistant projection
+// parameters.
+func (a *AzimuthalEquidistant) Parameters() ProjectionParameters {
+	return ProjectionParameters{
+		SemiMajorAxis:   a.semiMajorAxis,
+		Flattening:      a.flattening,
+		CentralMeridian: a.azeqOriginLong,
+		OriginLatitude:  a.azeqOriginLat,
+		FalseEasting:    a.azeqFalseEasting,
+		FalseNorthing:   a.azeqFalseNorthing,
+		ScaleFactor:     1.0,
+	}
+}
+
+// checkAzimuthalCenter validates the ellipsoid and the centre of an azimuthal
+// projection.
+func checkAzimuthalCenter(ellipsoidSemiMajorAxis, ellipsoidFlattening float64, center s2.LatLng) error {
+	invF := 1 / ellipsoidFlattening
+	if ellipsoidSemiMajorAxis <= 0.0 {
+		return errors.New("Semi-major axis must be greater than zero")
+	}
+	if (invF < 250) || (invF > 350) {
+		return errors.New("Inverse flattening must be between 250 and 350")
+	}
+	if (center.Lat.Radians() < -math.Pi/2) || (center.Lat.Radians() > math.Pi/2) {
+		return errors.New("Origin Latitude out of range")
+	}
+	if (center.Lng.Radians() < -math.Pi) || (center.Lng.Radians() > 2*math.Pi) {
+		return errors.New("Origin Longitude out of range")
+	}
+	return nil
+}
+
+// normalizeOriginLongitude maps a longitude of origin in [-Pi, 2*Pi] to
+// [-Pi, Pi].
+func normalizeOriginLongitude(longitude float64) float64 {
+	if longitude > math.Pi {
+		longitude -= 2 * math.Pi
+	}
+	return longitude
+}
+
+// utmAzimuthalCenter converts the UTM centre of an azimuthal projection to
+// geodetic coordinates.
+func utmAzimuthalCenter(ellipsoid Ellipsoid, center UTMCoord) (s2.LatLng, error) {
+	utm, err := NewUTMEllipsoid(ellipsoid, 0)
+	if err != nil {
+		return s2.LatLng{}, err
+	}
+	return utm.ConvertToGeodetic(center)
+}
+
+// mgrsAzimuthalCenter converts the MGRS centre of an azimuthal projection to
+// geodetic coordinates.
+func mgrsAzimuthalCenter(ellipsoid Ellipsoid, center string) (s2.LatLng, error) {
+	mgrs, err := NewMGRSEllipsoid(ellipsoid)
+	if err != nil {
+		return s2.LatLng{}, err
+	}
+	return mgrs.ConvertToGeodetic(center)
+}
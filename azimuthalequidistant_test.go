@@ -0,0 +1,185 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestAzimuthalEquidistant(t *testing.T) {
+	grs80, _ := coordconv.EllipsoidByCode("RF")
+	wgs84, _ := coordconv.EllipsoidByCode("WE")
+
+	testCases := []struct {
+		name      string
+		ellipsoid coordconv.Ellipsoid
+		center    s2.LatLng
+		geo       s2.LatLng
+		distance  float64
+		azimuth   float64 // degrees
+		tolerance float64 // degrees
+	}{
+		{
+			// Vincenty's example, Flinders Peak to Buninyong
+			name:      "flinders peak",
+			ellipsoid: grs80,
+			center:    s2.LatLngFromDegrees(-(37 + 57.0/60 + 3.72030/3600), 144+25.0/60+29.52440/3600),
+			geo:       s2.LatLngFromDegrees(-(37 + 39.0/60 + 10.15610/3600), 143+55.0/60+35.38390/3600),
+			distance:  54972.271,
+			azimuth:   306 + 52.0/60 + 5.37/3600,
+			tolerance: 0.005 / 3600,
+		},
+		{
+			// GeographicLib, JFK to 49d01'N 2d33'E
+			name:      "jfk",
+			ellipsoid: wgs84,
+			center:    s2.LatLngFromDegrees(40.6, -73.8),
+			geo:       s2.LatLngFromDegrees(49+1.0/60, 2+33.0/60),
+			distance:  5853226.256,
+			azimuth:   53.47022,
+			tolerance: 0.5e-5,
+		},
+		{
+			// GeographicLib, Wellington to Salamanca, nearly antipodal
+			name:      "wellington",
+			ellipsoid: wgs84,
+			center:    s2.LatLngFromDegrees(-41.32, 174.81),
+			geo:       s2.LatLngFromDegrees(40.96, -5.50),
+			distance:  19959679.267,
+			azimuth:   161.067669986,
+			tolerance: 0.5e-9,
+		},
+	}
+
+	for _, tc := range testCases {
+		azeq, err := coordconv.NewAzimuthalEquidistantEllipsoid(tc.ellipsoid, tc.center, 1000, 2000)
+		if err != nil {
+			t.Fatalf("%s: error creating azimuthal equidistant converter: %s", tc.name, err)
+		}
+		mc, err := azeq.ConvertFromGeodetic(tc.geo)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		dx := mc.Easting - 1000
+		dy := mc.Northing - 2000
+		if distance := math.Hypot(dx, dy); math.Abs(distance-tc.distance) > 1e-3 {
+			t.Errorf("%s: expected distance %.3f, got %.3f", tc.name, tc.distance, distance)
+		}
+		azimuth := math.Atan2(dx, dy) * 180 / math.Pi
+		if math.Abs(math.Remainder(azimuth-tc.azimuth, 360)) > tc.tolerance {
+			t.Errorf("%s: expected azimuth %.9f, got %.9f", tc.name, tc.azimuth, azimuth)
+		}
+
+		geo, err := azeq.ConvertToGeodetic(mc)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if geo.Distance(tc.geo).Radians()*ellipsoidSemiMajorAxis > 1e-6 {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.geo, geo)
+		}
+	}
+}
+
+func TestAzimuthalEquidistantRoundTrip(t *testing.T) {
+	for _, center := range []s2.LatLng{
+		s2.LatLngFromDegrees(38.8895, -77.0352),
+		s2.LatLngFromDegrees(-33.9, 151.2),
+		s2.LatLngFromDegrees(0, 0),
+		s2.LatLngFromDegrees(90, 0),
+	} {
+		azeq, err := coordconv.NewAzimuthalEquidistant(ellipsoidSemiMajorAxis, ellipsoidFlattening, center, 0, 0)
+		if err != nil {
+			t.Fatalf("error creating azimuthal equidistant converter: %s", err)
+		}
+		for lng := -180.0; lng < 180; lng += 15 {
+			for lat := -85.0; lat <= 85; lat += 10 {
+				geo := s2.LatLngFromDegrees(lat, lng)
+				mc, err := azeq.ConvertFromGeodetic(geo)
+				if err != nil {
+					t.Fatalf("%s: expected no error at %s, got %s", center, geo, err)
+				}
+				geo2, err := azeq.ConvertToGeodetic(mc)
+				if err != nil {
+					t.Fatalf("%s: expected no error in round trip at %s, got %s", center, geo, err)
+				}
+				if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > 1e-6 {
+					t.Errorf("%s: expected %s, got %s", center, geo, geo2)
+				}
+			}
+		}
+
+		mc, err := azeq.ConvertFromGeodetic(center)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", center, err)
+		}
+		if mc.Easting != 0 || mc.Northing != 0 {
+			t.Errorf("%s: expected the centre at the false easting and northing, got %v", center, mc)
+		}
+		if _, err := azeq.ConvertToGeodetic(coordconv.MapCoords{Easting: 2.1e7}); err == nil {
+			t.Errorf("%s: expected an error beyond the antipode", center)
+		}
+	}
+}
+
+func TestAzimuthalCenters(t *testing.T) {
+	wgs84, _ := coordconv.EllipsoidByCode("WE")
+	utmConv, _ := coordconv.NewUTMEllipsoid(wgs84, 0)
+	mgrsConv, _ := coordconv.NewMGRSEllipsoid(wgs84)
+
+	center := s2.LatLngFromDegrees(38.8895, -77.0352)
+	utmCenter, err := utmConv.ConvertFromGeodetic(center, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	mgrsCenter, err := mgrsConv.ConvertFromGeodetic(center, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	// the MGRS string names the south west corner of a one meter square
+	mgrsGeo, err := mgrsConv.ConvertToGeodetic(mgrsCenter)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	var projections []struct {
+		name       string
+		projection coordconv.Projection
+		center     s2.LatLng
+	}
+	add := func(name string, projection coordconv.Projection, center s2.LatLng, err error) {
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", name, err)
+		}
+		projections = append(projections, struct {
+			name       string
+			projection coordconv.Projection
+			center     s2.LatLng
+		}{name, projection, center})
+	}
+	azeqUTM, err := coordconv.NewAzimuthalEquidistantUTM(wgs84, utmCenter, 0, 0)
+	add("azimuthal equidistant utm", azeqUTM, center, err)
+	azeqMGRS, err := coordconv.NewAzimuthalEquidistantMGRS(wgs84, mgrsCenter, 0, 0)
+	add("azimuthal equidistant mgrs", azeqMGRS, mgrsGeo, err)
+	gnomUTM, err := coordconv.NewGnomonicUTM(wgs84, utmCenter, 0, 0)
+	add("gnomonic utm", gnomUTM, center, err)
+	gnomMGRS, err := coordconv.NewGnomonicMGRS(wgs84, mgrsCenter, 0, 0)
+	add("gnomonic mgrs", gnomMGRS, mgrsGeo, err)
+
+	for _, p := range projections {
+		params := p.projection.Parameters()
+		if math.Abs(params.OriginLatitude-p.center.Lat.Radians())*ellipsoidSemiMajorAxis > 1e-3 ||
+			math.Abs(params.CentralMeridian-p.center.Lng.Radians())*ellipsoidSemiMajorAxis > 1e-3 {
+			t.Errorf("%s: expected centre %s, got %f %f", p.name, p.center,
+				params.OriginLatitude*180/math.Pi, params.CentralMeridian*180/math.Pi)
+		}
+	}
+
+	if _, err := coordconv.NewAzimuthalEquidistantMGRS(wgs84, "18SUJ234", 0, 0); err == nil {
+		t.Errorf("expected an error for an invalid MGRS centre")
+	}
+	if _, err := coordconv.NewGnomonicUTM(wgs84, coordconv.UTMCoord{Zone: 61}, 0, 0); err == nil {
+		t.Errorf("expected an error for an invalid UTM centre")
+	}
+}
@@ -0,0 +1,240 @@
+package coordconv
+
+import (
+	"errors"
+	"math"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// CassiniSoldner provides conversions between geodetic coordinates (latitude
+// and longitude) and Cassini-Soldner projection coordinates (easting and
+// northing).  The formulas are the series of EPSG Guidance Note 7-2, which
+// round trip to a few millimeters within 2 degrees of the central meridian,
+// the extent of the cadastral grids that use the projection.  The
+// hyperbolic variant, used in Fiji, corrects the northing for the curvature of
+// the ellipsoid.
+type CassiniSoldner struct {
+	semiMajorAxis float64
+	flattening    float64
+	es2           float64 // Eccentricity squared
+	rectifying    rectifying
+
+	// Cassini-Soldner projection Parameters
+	cassOriginLat     float64 // Latitude of origin in radians
+	cassOriginLong    float64 // Longitude of origin in radians
+	cassFalseEasting  float64 // False easting in meters
+	cassFalseNorthing float64 // False northing in meters
+	cassM0            float64 // Meridian distance to the latitude of origin
+	cassHyperbolic    bool    // Hyperbolic variant
+}
+
+// NewCassiniSoldner constructs a Cassini-Soldner converter.
+func NewCassiniSoldner(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+	originLatitude, falseEasting, falseNorthing float64) (*CassiniSoldner, error) {
+	return newCassiniSoldner(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+		originLatitude, falseEasting, falseNorthing, false)
+}
+
+// NewCassiniSoldnerEllipsoid constructs a Cassini-Soldner converter for the
+// given ellipsoid.
+func NewCassiniSoldnerEllipsoid(ellipsoid Ellipsoid, centralMeridian,
+	originLatitude, falseEasting, falseNorthing float64) (*CassiniSoldner, error) {
+	return NewCassiniSoldner(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, centralMeridian,
+		originLatitude, falseEasting, falseNorthing)
+}
+
+// NewCassiniSoldnerHyperbolic constructs a Hyperbolic Cassini-Soldner
+// converter.
+func NewCassiniSoldnerHyperbolic(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+	originLatitude, falseEasting, falseNorthing float64) (*CassiniSoldner, error) {
+	return newCassiniSoldner(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+		originLatitude, falseEasting, falseNorthing, true)
+}
+
+// NewCassiniSoldnerHyperbolicEllipsoid constructs a Hyperbolic
+// Cassini-Soldner converter for the given ellipsoid.
+func NewCassiniSoldnerHyperbolicEllipsoid(ellipsoid Ellipsoid, centralMeridian,
+	originLatitude, falseEasting, falseNorthing float64) (*CassiniSoldner, error) {
+	return NewCassiniSoldnerHyperbolic(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, centralMeridian,
+		originLatitude, falseEasting, falseNorthing)
+}
+
+func newCassiniSoldner(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+	originLatitude, falseEasting, falseNorthing float64, hyperbolic bool) (*CassiniSoldner, error) {
+	if err := checkCylindricalParameters(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		centralMeridian); err != nil {
+		return nil, err
+	}
+	if (originLatitude < -math.Pi/2) || (originLatitude > math.Pi/2) {
+		return nil, errors.New("originLatitude out of range")
+	}
+
+	if centralMeridian > math.Pi {
+		centralMeridian -= 2 * math.Pi
+	}
+	c := &CassiniSoldner{
+		semiMajorAxis:     ellipsoidSemiMajorAxis,
+		flattening:        ellipsoidFlattening,
+		es2:               2*ellipsoidFlattening - ellipsoidFlattening*ellipsoidFlattening,
+		rectifying:        newRectifying(ellipsoidSemiMajorAxis, ellipsoidFlattening),
+		cassOriginLat:     originLatitude,
+		cassOriginLong:    centralMeridian,
+		cassFalseEasting:  falseEasting,
+		cassFalseNorthing: falseNorthing,
+		cassHyperbolic:    hyperbolic,
+	}
+	c.cassM0 = c.rectifying.distance(originLatitude)
+	return c, nil
+}
+
+// ConvertFromGeodetic converts geodetic coordinates (latitude and longitude) to
+// Cassini-Soldner coordinates (easting and northing), according to the current
+// ellipsoid and Cassini-Soldner projection parameters.
+func (c *CassiniSoldner) ConvertFromGeodetic(geodeticCoordinates s2.LatLng) (MapCoords, error) {
+	longitude := geodeticCoordinates.Lng.Radians()
+	latitude := geodeticCoordinates.Lat.Radians()
+
+	if (latitude < -math.Pi/2) || (latitude > math.Pi/2) {
+		return MapCoords{}, errors.New("latitude out of range")
+	}
+	if (longitude < -math.Pi) || (longitude > 2*math.Pi) {
+		return MapCoords{}, errors.New("longitude out of range")
+	}
+
+	dlam := longitude - c.cassOriginLong
+	if dlam > math.Pi {
+		dlam -= 2 * math.Pi
+	}
+	if dlam < -math.Pi {
+		dlam += 2 * math.Pi
+	}
+
+	sinLat := math.Sin(latitude)
+	cosLat := math.Cos(latitude)
+	tanLat := math.Tan(latitude)
+	nu := c.semiMajorAxis / math.Sqrt(1-c.es2*sinLat*sinLat)
+	A := dlam * cosLat
+	A2 := A * A
+	T := tanLat * tanLat
+	C := c.es2 * cosLat * cosLat / (1 - c.es2)
+
+	x := c.rectifying.distance(latitude) - c.cassM0 + nu*tanLat*(A2/2+(5-T+6*C)*A2*A2/24)
+	if c.cassHyperbolic {
+		x -= x * x * x / (6 * c.rho(sinLat) * nu)
+	}
+	return MapCoords{
+		Easting:  c.cassFalseEasting + nu*(A-T*A2*A/6-(8-T+8*C)*T*A2*A2*A/120),
+		Northing: c.cassFalseNorthing + x,
+	}, nil
+}
+
+// ConvertToGeodetic converts Cassini-Soldner coordinates (easting and
+// northing) to geodetic coordinates (latitude and longitude) according to the
+// current ellipsoid and Cassini-Soldner projection parameters.
+func (c *CassiniSoldner) ConvertToGeodetic(mapProjectionCoordinates MapCoords) (s2.LatLng, error) {
+	dx := mapProjectionCoordinates.Easting - c.cassFalseEasting
+	dy := mapProjectionCoordinates.Northing - c.cassFalseNorthing
+
+	latitude, longitude, err := c.reverse(dx, dy)
+	if err != nil {
+		return s2.LatLng{}, err
+	}
+	if c.cassHyperbolic {
+		// Solve dy = x - x^3/(6 rho nu), with the radii at the point
+		x := dy
+		for count := 0; ; count++ {
+			if count == 10 {
+				return s2.LatLng{}, errors.New("Point is outside of projection area")
+			}
+			sinLat := math.Sin(latitude)
+			nu := c.semiMajorAxis / math.Sqrt(1-c.es2*sinLat*sinLat)
+			xNext := dy + x*x*x/(6*c.rho(sinLat)*nu)
+			done := math.Abs(xNext-x) < 1.0e-12*c.semiMajorAxis
+			x = xNext
+			if latitude, longitude, err = c.reverse(dx, x); err != nil {
+				return s2.LatLng{}, err
+			}
+			if done {
+				break
+			}
+		}
+	}
+	return s2.LatLng{Lat: s1.Angle(latitude), Lng: s1.Angle(longitude)}, nil
+}
+
+// reverse returns the latitude and longitude of the point at the given
+// distance east of the central meridian and the given northing x from the
+// latitude of origin, before any hyperbolic correction.
+func (c *CassiniSoldner) reverse(dx, x float64) (latitude, longitude float64, err error) {
+	m1 := c.cassM0 + x
+	if !(math.Abs(m1) <= c.rectifying.a*math.Pi/2*(1+1.0e-12)) {
+		return 0, 0, errors.New("northing out of range")
+	}
+	lat1 := math.Max(-math.Pi/2, math.Min(math.Pi/2, c.rectifying.latitude(m1)))
+	if math.Abs(lat1) == math.Pi/2 {
+		return lat1, c.cassOriginLong, nil
+	}
+
+	sinLat1 := math.Sin(lat1)
+	tanLat1 := math.Tan(lat1)
+	nu1 := c.semiMajorAxis / math.Sqrt(1-c.es2*sinLat1*sinLat1)
+	rho1 := c.rho(sinLat1)
+	T1 := tanLat1 * tanLat1
+	D := dx / nu1
+	D2 := D * D
+
+	latitude = lat1 - (nu1*tanLat1/rho1)*(D2/2-(1+3*T1)*D2*D2/24)
+	longitude = c.cassOriginLong + (D-T1*D2*D/3+(1+3*T1)*T1*D2*D2*D/15)/math.Cos(lat1)
+	if (math.Abs(latitude) > math.Pi/2) || math.IsNaN(longitude) {
+		return 0, 0, errors.New("Point is outside of projection area")
+	}
+	if longitude > math.Pi {
+		longitude -= 2 * math.Pi
+	} else if longitude < -math.Pi {
+		longitude += 2 * math.Pi
+	}
+	if math.Abs(longitude) > math.Pi {
+		return 0, 0, errors.New("easting out of range")
+	}
+	return latitude, longitude, nil
+}
+
+// rho returns the radius of curvature of the meridian at the latitude with
+// the given sine.
+func (c *CassiniSoldner) rho(sinLat float64) float64 {
+	return c.semiMajorAxis * (1 - c.es2) / math.Pow(1-c.es2*sinLat*sinLat, 1.5)
+}
+
+// Parameters returns the ellipsoid and Cassini-Soldner projection
+// parameters.
+func (c *CassiniSoldner) Parameters() ProjectionParameters {
+	return ProjectionParameters{
+		SemiMajorAxis:   c.semiMajorAxis,
+		Flattening:      c.flattening,
+		CentralMeridian: c.cassOriginLong,
+		OriginLatitude:  c.cassOriginLat,
+		FalseEasting:    c.cassFalseEasting,
+		FalseNorthing:   c.cassFalseNorthing,
+		ScaleFactor:     1.0,
+	}
+}
+
+// checkCylindricalParameters validates the ellipsoid and central meridian of
+// the Cassini-Soldner and Equidistant Cylindrical projections as
+// NewTransverseMercator does.
+func checkCylindricalParameters(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+	centralMeridian float64) error {
+	invFlattening := 1.0 / ellipsoidFlattening
+	if ellipsoidSemiMajorAxis <= 0.0 {
+		return errors.New("Semi-major axis must be greater than zero")
+	}
+	if invFlattening < 150 {
+		return errors.New("inverse ellipsoid flattening out of range")
+	}
+	if (centralMeridian < -math.Pi) || (centralMeridian > (2 * math.Pi)) {
+		return errors.New("centralMeridian out of range")
+	}
+	return nil
+}
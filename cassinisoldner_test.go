@@ -0,0 +1,112 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestCassiniSoldner(t *testing.T) {
+	// EPSG Guidance Note 7-2 examples, in Clarke's links and links
+	trinidad, err := coordconv.NewCassiniSoldner(31706587.88, 1/294.2606764,
+		-(61+20.0/60)*math.Pi/180, (10+26.0/60+30.0/3600)*math.Pi/180, 430000, 325000)
+	if err != nil {
+		t.Fatalf("error creating cassini soldner converter: %s", err)
+	}
+	vanuaLevu, err := coordconv.NewCassiniSoldnerHyperbolic(20926202/0.66, 1/293.465,
+		(179+20.0/60)*math.Pi/180, -(16+15.0/60)*math.Pi/180, 1251331.8, 1662888.5)
+	if err != nil {
+		t.Fatalf("error creating cassini soldner converter: %s", err)
+	}
+
+	testCases := []struct {
+		name       string
+		projection *coordconv.CassiniSoldner
+		geo        s2.LatLng
+		expected   coordconv.MapCoords
+	}{
+		{"trinidad", trinidad, s2.LatLngFromDegrees(10, -62),
+			coordconv.MapCoords{Easting: 66644.94, Northing: 82536.22}},
+		{"vanua levu", vanuaLevu, s2.LatLngFromDegrees(-(16 + 50.0/60 + 29.2435/3600), 179+59.0/60+39.6115/3600),
+			coordconv.MapCoords{Easting: 1601528.90, Northing: 1336966.01}},
+	}
+
+	const epsilon = 0.01
+	for _, tc := range testCases {
+		mc, err := tc.projection.ConvertFromGeodetic(tc.geo)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if math.Abs(mc.Easting-tc.expected.Easting) > epsilon ||
+			math.Abs(mc.Northing-tc.expected.Northing) > epsilon {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, mc)
+		}
+		geo, err := tc.projection.ConvertToGeodetic(tc.expected)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if geo.Distance(tc.geo).Radians()*tc.projection.Parameters().SemiMajorAxis > epsilon {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.geo, geo)
+		}
+	}
+}
+
+func TestCassiniSoldnerRoundTrip(t *testing.T) {
+	for _, hyperbolic := range []bool{false, true} {
+		constructor := coordconv.NewCassiniSoldner
+		if hyperbolic {
+			constructor = coordconv.NewCassiniSoldnerHyperbolic
+		}
+		cass, err := constructor(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+			10*math.Pi/180, 45*math.Pi/180, 100000, 200000)
+		if err != nil {
+			t.Fatalf("error creating cassini soldner converter: %s", err)
+		}
+		for lng := 8.0; lng <= 12; lng += 0.5 {
+			for lat := -60.0; lat <= 80; lat += 10 {
+				// the hyperbolic correction is for grids near the origin
+				if hyperbolic && math.Abs(lat-45) > 10 {
+					continue
+				}
+				geo := s2.LatLngFromDegrees(lat, lng)
+				mc, err := cass.ConvertFromGeodetic(geo)
+				if err != nil {
+					t.Fatalf("expected no error at %s, got %s", geo, err)
+				}
+				geo2, err := cass.ConvertToGeodetic(mc)
+				if err != nil {
+					t.Fatalf("expected no error in round trip at %s, got %s", geo, err)
+				}
+				if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > 5e-3 {
+					t.Errorf("hyperbolic %t: expected %s, got %s", hyperbolic, geo, geo2)
+				}
+			}
+		}
+
+		// the central meridian is true to scale from the origin
+		mc, err := cass.ConvertFromGeodetic(s2.LatLngFromDegrees(45, 10))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if math.Abs(mc.Easting-100000) > 1e-6 || math.Abs(mc.Northing-200000) > 1e-6 {
+			t.Errorf("expected the origin at the false easting and northing, got %v", mc)
+		}
+	}
+}
+
+func TestCassiniSoldnerInvalid(t *testing.T) {
+	if _, err := coordconv.NewCassiniSoldner(0, ellipsoidFlattening, 0, 0, 0, 0); err == nil {
+		t.Errorf("expected an error for a zero semi-major axis")
+	}
+	if _, err := coordconv.NewCassiniSoldner(ellipsoidSemiMajorAxis, 1.0/100, 0, 0, 0, 0); err == nil {
+		t.Errorf("expected an error for an inverse flattening of 100")
+	}
+	if _, err := coordconv.NewCassiniSoldner(ellipsoidSemiMajorAxis, ellipsoidFlattening, 0, 2, 0, 0); err == nil {
+		t.Errorf("expected an error for an origin latitude out of range")
+	}
+	if _, err := coordconv.NewCassiniSoldnerHyperbolic(ellipsoidSemiMajorAxis, ellipsoidFlattening, 7, 0, 0, 0); err == nil {
+		t.Errorf("expected an error for a central meridian out of range")
+	}
+}
@@ -0,0 +1,263 @@
+package coordconv
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// DatumType identifies how a datum is transformed to WGS84.
+type DatumType byte
+
+// DatumType constants
+const (
+	// DatumThreeParameter datums are shifted to WGS84 with the Molodensky
+	// transform using DX, DY and DZ.
+	DatumThreeParameter DatumType = iota
+	// DatumSevenParameter datums are shifted to WGS84 with the Helmert
+	// (Bursa-Wolf) transform using all of the datum parameters.
+	DatumSevenParameter
+)
+
+// Datum is a geodetic datum, a reference ellipsoid along with the parameters
+// that shift it to WGS84.  Rotations follow the position vector convention
+// (EPSG method 9606).
+type Datum struct {
+	Code       string
+	Name       string
+	Ellipsoid  Ellipsoid
+	Type       DatumType
+	DX, DY, DZ float64 // Translation to WGS84 in meters
+	RX, RY, RZ float64 // Rotation to WGS84 in radians
+	Scale      float64 // Scale difference to WGS84, e.g. 1e-6 for 1 ppm
+}
+
+// DatumWGS84 is the WGS84 datum.
+var DatumWGS84 = Datum{Code: "WGE", Name: "World Geodetic System 1984", Ellipsoid: EllipsoidWGS84}
+
+const arcSecond = math.Pi / (180 * 3600)
+
+// molodenskyMaxLat is the latitude beyond which three parameter datums are
+// shifted geocentrically, as the Molodensky transform breaks down at the poles.
+const molodenskyMaxLat = 89.75 * (math.Pi / 180.0)
+
+// datums are the built-in datums.
+var datums = []Datum{
+	DatumWGS84,
+	{Code: "WGC", Name: "World Geodetic System 1972", Ellipsoid: ellipsoidByCode("WD"),
+		Type: DatumSevenParameter, DZ: 4.5, RZ: 0.554 * arcSecond, Scale: 0.219e-6},
+	{Code: "NAR", Name: "North American 1983", Ellipsoid: ellipsoidByCode("RF")},
+	{Code: "NAS-C", Name: "North American 1927 (CONUS)", Ellipsoid: ellipsoidByCode("CC"),
+		DX: -8, DY: 160, DZ: 176},
+	{Code: "NAS-D", Name: "North American 1927 (Alaska)", Ellipsoid: ellipsoidByCode("CC"),
+		DX: -5, DY: 135, DZ: 172},
+	{Code: "NAS-E", Name: "North American 1927 (Canada)", Ellipsoid: ellipsoidByCode("CC"),
+		DX: -10, DY: 158, DZ: 187},
+	{Code: "EUR-M", Name: "European 1950 (Mean)", Ellipsoid: ellipsoidByCode("IN"),
+		DX: -87, DY: -98, DZ: -121},
+	{Code: "EUR-A", Name: "European 1950 (Western Europe)", Ellipsoid: ellipsoidByCode("IN"),
+		DX: -87, DY: -96, DZ: -120},
+	{Code: "TOY-M", Name: "Tokyo (Mean)", Ellipsoid: ellipsoidByCode("BR"),
+		DX: -148, DY: 507, DZ: 685},
+	{Code: "OGB-M", Name: "Ordnance Survey Great Britain 1936 (Mean)", Ellipsoid: ellipsoidByCode("AA"),
+		DX: 375, DY: -111, DZ: 431},
+	{Code: "OGB-7", Name: "Ordnance Survey Great Britain 1936 (Helmert)", Ellipsoid: ellipsoidByCode("AA"),
+		Type: DatumSevenParameter, DX: 446.448, DY: -125.157, DZ: 542.060,
+		RX: 0.1502 * arcSecond, RY: 0.2470 * arcSecond, RZ: 0.8421 * arcSecond, Scale: -20.4894e-6},
+	{Code: "PDM-7", Name: "Deutsches Hauptdreiecksnetz (Helmert)", Ellipsoid: ellipsoidByCode("BR"),
+		Type: DatumSevenParameter, DX: 598.1, DY: 73.7, DZ: 418.2,
+		RX: 0.202 * arcSecond, RY: 0.045 * arcSecond, RZ: -2.455 * arcSecond, Scale: 6.7e-6},
+	{Code: "ADI-M", Name: "Adindan (Mean)", Ellipsoid: ellipsoidByCode("CD"),
+		DX: -166, DY: -15, DZ: 204},
+	{Code: "ARF-M", Name: "Arc 1950 (Mean)", Ellipsoid: ellipsoidByCode("CD"),
+		DX: -143, DY: -90, DZ: -294},
+	{Code: "AUA", Name: "Australian Geodetic 1966", Ellipsoid: ellipsoidByCode("AN"),
+		DX: -133, DY: -48, DZ: 148},
+	{Code: "AUG", Name: "Australian Geodetic 1984", Ellipsoid: ellipsoidByCode("AN"),
+		DX: -134, DY: -48, DZ: 149},
+	{Code: "SAN-M", Name: "South American 1969 (Mean)", Ellipsoid: ellipsoidByCode("SA"),
+		DX: -57, DY: 1, DZ: -41},
+	{Code: "PRP-M", Name: "Provisional South American 1956 (Mean)", Ellipsoid: ellipsoidByCode("IN"),
+		DX: -288, DY: 175, DZ: -376},
+	{Code: "HJO", Name: "Hjorsey 1955", Ellipsoid: ellipsoidByCode("IN"),
+		DX: -73, DY: 46, DZ: -86},
+	{Code: "GEO", Name: "Geodetic Datum 1949", Ellipsoid: ellipsoidByCode("IN"),
+		DX: 84, DY: -22, DZ: 209},
+}
+
+// ellipsoidByCode returns a built-in ellipsoid, panicking if it doesn't exist.
+func ellipsoidByCode(code string) Ellipsoid {
+	e, err := EllipsoidByCode(code)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// ellipsoidDatum returns a datum for an ellipsoid without known datum
+// parameters.  Its origin is taken to be at the centre of WGS84, so only the
+// difference in ellipsoid size and shape applies when transforming datums.
+func ellipsoidDatum(ellipsoidSemiMajorAxis, ellipsoidFlattening float64, ellipsoidCode string) Datum {
+	return Datum{Ellipsoid: Ellipsoid{
+		Code:          ellipsoidCode,
+		SemiMajorAxis: ellipsoidSemiMajorAxis,
+		Flattening:    ellipsoidFlattening,
+	}}
+}
+
+// Datums returns the built-in datums.
+func Datums() []Datum {
+	return append([]Datum(nil), datums...)
+}
+
+// DatumByCode looks up a built-in datum by its code, ignoring case.
+func DatumByCode(code string) (Datum, error) {
+	for _, d := range datums {
+		if strings.EqualFold(d.Code, code) {
+			return d, nil
+		}
+	}
+	return Datum{}, errors.New("unknown datum code")
+}
+
+// TransformDatum converts geodetic coordinates and ellipsoid height from one
+// datum to another, shifting through WGS84.
+func TransformDatum(geodeticCoordinates s2.LatLng, height float64, from, to Datum) (s2.LatLng, float64, error) {
+	latitude := geodeticCoordinates.Lat.Radians()
+	longitude := geodeticCoordinates.Lng.Radians()
+
+	if (latitude < -math.Pi/2) || (latitude > math.Pi/2) {
+		return s2.LatLng{}, 0, errors.New("latitude out of range")
+	}
+	if (longitude < -math.Pi) || (longitude > 2*math.Pi) {
+		return s2.LatLng{}, 0, errors.New("longitude out of range")
+	}
+	if from == to {
+		return geodeticCoordinates, height, nil
+	}
+
+	latitude, longitude, height = from.toWGS84(latitude, longitude, height)
+	latitude, longitude, height = to.fromWGS84(latitude, longitude, height)
+
+	if longitude > math.Pi {
+		longitude -= 2 * math.Pi
+	} else if longitude < -math.Pi {
+		longitude += 2 * math.Pi
+	}
+	return s2.LatLng{Lat: s1.Angle(latitude), Lng: s1.Angle(longitude)}, height, nil
+}
+
+// shiftDatum converts geodetic coordinates on the ellipsoid surface from one
+// datum to another, discarding the resulting height.
+func shiftDatum(geodeticCoordinates s2.LatLng, from, to Datum) (s2.LatLng, error) {
+	geo, _, err := TransformDatum(geodeticCoordinates, 0, from, to)
+	return geo, err
+}
+
+// isWGS84 reports whether the datum has no shift to WGS84 and lies on the
+// WGS84 ellipsoid.
+func (d Datum) isWGS84() bool {
+	return d.DX == 0 && d.DY == 0 && d.DZ == 0 &&
+		d.RX == 0 && d.RY == 0 && d.RZ == 0 && d.Scale == 0 &&
+		d.Ellipsoid.SemiMajorAxis == EllipsoidWGS84.SemiMajorAxis &&
+		d.Ellipsoid.Flattening == EllipsoidWGS84.Flattening
+}
+
+// toWGS84 shifts a point on the datum to WGS84.
+func (d Datum) toWGS84(latitude, longitude, height float64) (float64, float64, float64) {
+	if d.isWGS84() {
+		return latitude, longitude, height
+	}
+	wgs84 := EllipsoidWGS84
+	if d.Type == DatumSevenParameter || math.Abs(latitude) > molodenskyMaxLat {
+		x, y, z := newGeocentric(d.Ellipsoid.SemiMajorAxis, d.Ellipsoid.Flattening).forward(latitude, longitude, height)
+		x, y, z = d.helmert(x, y, z)
+		return newGeocentric(wgs84.SemiMajorAxis, wgs84.Flattening).inverse(x, y, z)
+	}
+	return molodenskyShift(d.Ellipsoid, wgs84, d.DX, d.DY, d.DZ, latitude, longitude, height)
+}
+
+// fromWGS84 shifts a WGS84 point to the datum.
+func (d Datum) fromWGS84(latitude, longitude, height float64) (float64, float64, float64) {
+	if d.isWGS84() {
+		return latitude, longitude, height
+	}
+	wgs84 := EllipsoidWGS84
+	if d.Type == DatumSevenParameter || math.Abs(latitude) > molodenskyMaxLat {
+		x, y, z := newGeocentric(wgs84.SemiMajorAxis, wgs84.Flattening).forward(latitude, longitude, height)
+		x, y, z = d.inverseHelmert(x, y, z)
+		return newGeocentric(d.Ellipsoid.SemiMajorAxis, d.Ellipsoid.Flattening).inverse(x, y, z)
+	}
+	// invert the forward Molodensky shift so that round trips are consistent
+	lat, lon, h := molodenskyShift(wgs84, d.Ellipsoid, -d.DX, -d.DY, -d.DZ, latitude, longitude, height)
+	for i := 0; i < 4; i++ {
+		fLat, fLon, fH := molodenskyShift(d.Ellipsoid, wgs84, d.DX, d.DY, d.DZ, lat, lon, h)
+		lat -= fLat - latitude
+		lon -= math.Remainder(fLon-longitude, 2*math.Pi)
+		h -= fH - height
+	}
+	return lat, lon, h
+}
+
+// helmert applies the datum's shift to WGS84 to geocentric coordinates.  A
+// three parameter datum only translates.
+func (d Datum) helmert(x, y, z float64) (float64, float64, float64) {
+	s := 1 + d.Scale
+	return d.DX + s*(x-d.RZ*y+d.RY*z),
+		d.DY + s*(d.RZ*x+y-d.RX*z),
+		d.DZ + s*(-d.RY*x+d.RX*y+z)
+}
+
+// inverseHelmert applies the datum's shift from WGS84 to geocentric
+// coordinates.
+func (d Datum) inverseHelmert(x, y, z float64) (float64, float64, float64) {
+	s := 1 + d.Scale
+	x = (x - d.DX) / s
+	y = (y - d.DY) / s
+	z = (z - d.DZ) / s
+	// the transpose of the rotation matrix is its inverse to second order
+	return x + d.RZ*y - d.RY*z,
+		-d.RZ*x + y + d.RX*z,
+		d.RY*x - d.RX*y + z
+}
+
+// molodenskyShift shifts geodetic coordinates between ellipsoids with the
+// standard Molodensky transform, given the geocentric translation between
+// them.
+func molodenskyShift(from, to Ellipsoid, dx, dy, dz, latitude, longitude, height float64) (float64, float64, float64) {
+	a := from.SemiMajorAxis
+	f := from.Flattening
+	da := to.SemiMajorAxis - a
+	df := to.Flattening - f
+	es2 := 2*f - f*f
+	bOverA := 1 - f
+
+	sinLat := math.Sin(latitude)
+	cosLat := math.Cos(latitude)
+	sinLon := math.Sin(longitude)
+	cosLon := math.Cos(longitude)
+	w2 := 1 - es2*sinLat*sinLat
+	w := math.Sqrt(w2)
+	rn := a / w                    // Radius of curvature in the prime vertical
+	rm := a * (1 - es2) / (w2 * w) // Radius of curvature in the meridian
+
+	dLat := (-dx*sinLat*cosLon - dy*sinLat*sinLon + dz*cosLat +
+		da*rn*es2*sinLat*cosLat/a +
+		df*(rm/bOverA+rn*bOverA)*sinLat*cosLat) / (rm + height)
+	dLon := (-dx*sinLon + dy*cosLon) / ((rn + height) * cosLat)
+	dHeight := dx*cosLat*cosLon + dy*cosLat*sinLon + dz*sinLat -
+		da*a/rn + df*bOverA*rn*sinLat*sinLat
+
+	latitude += dLat
+	if latitude > math.Pi/2 {
+		latitude = math.Pi - latitude
+		longitude += math.Pi
+	} else if latitude < -math.Pi/2 {
+		latitude = -math.Pi - latitude
+		longitude += math.Pi
+	}
+	return latitude, longitude + dLon, height + dHeight
+}
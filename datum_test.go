@@ -0,0 +1,229 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func mustDatum(t *testing.T, code string) coordconv.Datum {
+	t.Helper()
+	d, err := coordconv.DatumByCode(code)
+	if err != nil {
+		t.Fatalf("%s: expected no error, got %s", code, err)
+	}
+	return d
+}
+
+// geocentricShift applies a three parameter datum shift to WGS84 through
+// earth centered, earth fixed coordinates.
+func geocentricShift(d coordconv.Datum, geo s2.LatLng) s2.LatLng {
+	toXYZ := func(a, f, lat, lng float64) (float64, float64, float64) {
+		es2 := 2*f - f*f
+		rn := a / math.Sqrt(1-es2*math.Sin(lat)*math.Sin(lat))
+		return rn * math.Cos(lat) * math.Cos(lng), rn * math.Cos(lat) * math.Sin(lng), rn * (1 - es2) * math.Sin(lat)
+	}
+	x, y, z := toXYZ(d.Ellipsoid.SemiMajorAxis, d.Ellipsoid.Flattening, geo.Lat.Radians(), geo.Lng.Radians())
+	x, y, z = x+d.DX, y+d.DY, z+d.DZ
+
+	a := coordconv.EllipsoidWGS84.SemiMajorAxis
+	f := coordconv.EllipsoidWGS84.Flattening
+	es2 := 2*f - f*f
+	p := math.Hypot(x, y)
+	lat := math.Atan2(z, p*(1-es2))
+	for i := 0; i < 10; i++ {
+		rn := a / math.Sqrt(1-es2*math.Sin(lat)*math.Sin(lat))
+		h := p/math.Cos(lat) - rn
+		lat = math.Atan2(z, p*(1-es2*rn/(rn+h)))
+	}
+	return s2.LatLngFromDegrees(lat*180/math.Pi, math.Atan2(y, x)*180/math.Pi)
+}
+
+func TestTransformDatum(t *testing.T) {
+	wgs84 := coordconv.DatumWGS84
+	testCases := []struct {
+		code string
+		geo  s2.LatLng
+	}{
+		{"NAS-C", s2.LatLngFromDegrees(38.8895, -77.0352)},
+		{"NAS-D", s2.LatLngFromDegrees(61.2181, -149.9003)},
+		{"EUR-M", s2.LatLngFromDegrees(48.8584, 2.2945)},
+		{"TOY-M", s2.LatLngFromDegrees(35.6586, 139.7454)},
+		{"OGB-M", s2.LatLngFromDegrees(51.5007, -0.1246)},
+		{"ARF-M", s2.LatLngFromDegrees(-15.4167, 28.2833)},
+		{"NAS-C", s2.LatLngFromDegrees(89.9, 45)},
+	}
+	for _, tc := range testCases {
+		d := mustDatum(t, tc.code)
+		geo, _, err := coordconv.TransformDatum(tc.geo, 0, d, wgs84)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.code, err)
+		}
+		// Molodensky approximates the geocentric shift to well under a meter
+		expected := geocentricShift(d, tc.geo)
+		if dist := geo.Distance(expected).Radians() * wgs84.Ellipsoid.SemiMajorAxis; dist > 0.5 {
+			t.Errorf("%s: at %s expected %s, got %s (%fm)", tc.code, tc.geo, expected, geo, dist)
+		}
+
+		back, _, err := coordconv.TransformDatum(geo, 0, wgs84, d)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.code, err)
+		}
+		if dist := back.Distance(tc.geo).Radians() * wgs84.Ellipsoid.SemiMajorAxis; dist > 0.05 {
+			t.Errorf("%s: round trip expected %s, got %s (%fm)", tc.code, tc.geo, back, dist)
+		}
+	}
+}
+
+func TestTransformDatumHelmert(t *testing.T) {
+	// Caister Water Tower, from the Ordnance Survey guide to coordinate
+	// systems in Great Britain.  The Helmert transform is good to a few
+	// meters of the published OSGB36 position.
+	etrs89 := s2.LatLngFromDegrees(52+39.0/60+28.8282/3600, 1+42.0/60+57.8663/3600)
+	osgb36 := s2.LatLngFromDegrees(52+39.0/60+27.2531/3600, 1+43.0/60+4.5177/3600)
+
+	d := mustDatum(t, "OGB-7")
+	geo, height, err := coordconv.TransformDatum(etrs89, 108.05, coordconv.DatumWGS84, d)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if dist := geo.Distance(osgb36).Radians() * d.Ellipsoid.SemiMajorAxis; dist > 5 {
+		t.Errorf("expected %s, got %s (%fm)", osgb36, geo, dist)
+	}
+
+	back, backHeight, err := coordconv.TransformDatum(geo, height, d, coordconv.DatumWGS84)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if dist := back.Distance(etrs89).Radians() * d.Ellipsoid.SemiMajorAxis; dist > 1e-3 {
+		t.Errorf("round trip expected %s, got %s (%fm)", etrs89, back, dist)
+	}
+	if math.Abs(backHeight-108.05) > 1e-3 {
+		t.Errorf("round trip expected height %f, got %f", 108.05, backHeight)
+	}
+
+	// shifting between two datums goes through WGS84
+	ed50 := mustDatum(t, "EUR-M")
+	viaWGS84, _, err := coordconv.TransformDatum(osgb36, 0, d, coordconv.DatumWGS84)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	expected, _, err := coordconv.TransformDatum(viaWGS84, 0, coordconv.DatumWGS84, ed50)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	direct, _, err := coordconv.TransformDatum(osgb36, 0, d, ed50)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if dist := direct.Distance(expected).Radians() * d.Ellipsoid.SemiMajorAxis; dist > 0.05 {
+		t.Errorf("expected %s, got %s (%fm)", expected, direct, dist)
+	}
+}
+
+func TestDatumLookup(t *testing.T) {
+	d := mustDatum(t, "nas-c")
+	if d.Ellipsoid.Code != "CC" {
+		t.Errorf("expected Clarke 1866 ellipsoid, got %s", d.Ellipsoid.Code)
+	}
+	if _, err := coordconv.DatumByCode("XYZ"); err == nil {
+		t.Errorf("expected error for unknown datum code")
+	}
+	for _, d := range coordconv.Datums() {
+		if d.Ellipsoid.SemiMajorAxis == 0 {
+			t.Errorf("%s: expected an ellipsoid", d.Code)
+		}
+	}
+
+	geo := s2.LatLngFromDegrees(10, 20)
+	same, height, err := coordconv.TransformDatum(geo, 5, d, d)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if same != geo || height != 5 {
+		t.Errorf("expected %s at height 5, got %s at height %f", geo, same, height)
+	}
+	if _, _, err := coordconv.TransformDatum(s2.LatLngFromDegrees(91, 0), 0, d, coordconv.DatumWGS84); err == nil {
+		t.Errorf("expected error for latitude out of range")
+	}
+}
+
+func TestMGRSFromUTMDatum(t *testing.T) {
+	nad27 := mustDatum(t, "NAS-C")
+	nad27UTM, err := coordconv.NewUTMDatum(nad27, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	wgs84MGRS, err := coordconv.NewMGRSDatum(coordconv.DatumWGS84)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	nad27Geo := s2.LatLngFromDegrees(38.8895, -77.0352)
+	utm, err := nad27UTM.ConvertFromGeodetic(nad27Geo, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	got, err := wgs84MGRS.ConvertFromUTMDatum(utm, nad27UTM, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	wgs84Geo, _, err := coordconv.TransformDatum(nad27Geo, 0, nad27, coordconv.DatumWGS84)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	expected, err := coordconv.DefaultMGRSConverter.ConvertFromGeodetic(wgs84Geo, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+	unshifted, err := wgs84MGRS.ConvertFromGeodetic(nad27Geo, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if unshifted == got {
+		t.Errorf("expected the datum shift to move %s", got)
+	}
+
+	geo, err := wgs84MGRS.ConvertToGeodeticDatum(got, nad27)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if dist := geo.Distance(nad27Geo).Radians() * nad27.Ellipsoid.SemiMajorAxis; dist > 2 {
+		t.Errorf("expected %s, got %s (%fm)", nad27Geo, geo, dist)
+	}
+
+	back, err := nad27UTM.ConvertToGeodeticDatum(utm, coordconv.DatumWGS84)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if dist := back.Distance(wgs84Geo).Radians() * nad27.Ellipsoid.SemiMajorAxis; dist > 1e-3 {
+		t.Errorf("expected %s, got %s (%fm)", wgs84Geo, back, dist)
+	}
+
+	tokyo := mustDatum(t, "TOY-M")
+	tokyoUPS, err := coordconv.NewUPSDatum(tokyo)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	polar := s2.LatLngFromDegrees(86, 139)
+	ups, err := tokyoUPS.ConvertFromGeodeticDatum(polar, coordconv.DatumWGS84)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if _, err := wgs84MGRS.ConvertFromUPSDatum(ups, tokyoUPS, 5); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	}
+	geo, err = tokyoUPS.ConvertToGeodeticDatum(ups, coordconv.DatumWGS84)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if dist := geo.Distance(polar).Radians() * tokyo.Ellipsoid.SemiMajorAxis; dist > 0.05 {
+		t.Errorf("expected %s, got %s (%fm)", polar, geo, dist)
+	}
+}
@@ -0,0 +1,82 @@
+package coordconv
+
+import (
+	"errors"
+	"strings"
+)
+
+// Ellipsoid is a reference ellipsoid.  Code is the two letter GeoTrans
+// ellipsoid code, which also selects the MGRS lettering scheme.
+type Ellipsoid struct {
+	Code          string
+	Name          string
+	SemiMajorAxis float64 // Semi-major axis in meters
+	Flattening    float64
+}
+
+// EllipsoidWGS84 is the WGS84 ellipsoid.
+var EllipsoidWGS84 = Ellipsoid{"WE", "WGS 84", 6378137.0, 1 / 298.257223563}
+
+// ellipsoids are the standard GeoTrans ellipsoids.
+var ellipsoids = []Ellipsoid{
+	{"AA", "Airy 1830", 6377563.396, 1 / 299.3249646},
+	{"AM", "Modified Airy", 6377340.189, 1 / 299.3249646},
+	{"AN", "Australian National", 6378160.0, 1 / 298.25},
+	{"BN", "Bessel 1841 (Namibia)", 6377483.865, 1 / 299.1528128},
+	{"BR", "Bessel 1841", 6377397.155, 1 / 299.1528128},
+	{"CC", "Clarke 1866", 6378206.4, 1 / 294.9786982},
+	{"CD", "Clarke 1880", 6378249.145, 1 / 293.465},
+	{"CG", "Clarke 1880 (IGN)", 6378249.2, 1 / 293.466021294},
+	{"EA", "Everest (India 1830)", 6377276.345, 1 / 300.8017},
+	{"EB", "Everest (Sabah & Sarawak)", 6377298.556, 1 / 300.8017},
+	{"EC", "Everest (India 1956)", 6377301.243, 1 / 300.8017},
+	{"ED", "Everest (W. Malaysia 1969)", 6377295.664, 1 / 300.8017},
+	{"EE", "Everest (W. Malaysia & Singapore 1948)", 6377304.063, 1 / 300.8017},
+	{"EF", "Everest (Pakistan)", 6377309.613, 1 / 300.8017},
+	{"FA", "Modified Fischer 1960", 6378155.0, 1 / 298.3},
+	{"HE", "Helmert 1906", 6378200.0, 1 / 298.3},
+	{"HO", "Hough 1960", 6378270.0, 1 / 297.0},
+	{"ID", "Indonesian 1974", 6378160.0, 1 / 298.247},
+	{"IN", "International 1924", 6378388.0, 1 / 297.0},
+	{"KA", "Krassovsky 1940", 6378245.0, 1 / 298.3},
+	{"RF", "GRS 80", 6378137.0, 1 / 298.257222101},
+	{"SA", "South American 1969", 6378160.0, 1 / 298.25},
+	{"WD", "WGS 72", 6378135.0, 1 / 298.26},
+	EllipsoidWGS84,
+	{"WO", "War Office", 6378300.58, 1 / 296.0},
+}
+
+// Ellipsoids returns the built-in ellipsoids.
+func Ellipsoids() []Ellipsoid {
+	return append([]Ellipsoid(nil), ellipsoids...)
+}
+
+// EllipsoidByCode looks up a built-in ellipsoid by its two letter code.
+func EllipsoidByCode(code string) (Ellipsoid, error) {
+	for _, e := range ellipsoids {
+		if strings.EqualFold(e.Code, code) {
+			return e, nil
+		}
+	}
+	return Ellipsoid{}, errors.New("unknown ellipsoid code")
+}
+
+// EllipsoidByName looks up a built-in ellipsoid by its name, ignoring case.
+func EllipsoidByName(name string) (Ellipsoid, error) {
+	for _, e := range ellipsoids {
+		if strings.EqualFold(e.Name, name) {
+			return e, nil
+		}
+	}
+	return Ellipsoid{}, errors.New("unknown ellipsoid name")
+}
+
+// InverseFlattening returns the reciprocal of the ellipsoid flattening.
+func (e Ellipsoid) InverseFlattening() float64 {
+	return 1 / e.Flattening
+}
+
+// SemiMinorAxis returns the ellipsoid semi-minor axis in meters.
+func (e Ellipsoid) SemiMinorAxis() float64 {
+	return e.SemiMajorAxis * (1 - e.Flattening)
+}
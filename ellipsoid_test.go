@@ -0,0 +1,122 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestEllipsoidLookup(t *testing.T) {
+	testCases := []struct {
+		code          string
+		name          string
+		semiMajorAxis float64
+		invFlattening float64
+	}{
+		{"WE", "WGS 84", 6378137.0, 298.257223563},
+		{"CC", "Clarke 1866", 6378206.4, 294.9786982},
+		{"BR", "Bessel 1841", 6377397.155, 299.1528128},
+		{"IN", "International 1924", 6378388.0, 297.0},
+		{"RF", "GRS 80", 6378137.0, 298.257222101},
+	}
+	for _, tc := range testCases {
+		byCode, err := coordconv.EllipsoidByCode(tc.code)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.code, err)
+		}
+		byName, err := coordconv.EllipsoidByName(tc.name)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if byCode != byName {
+			t.Errorf("%s: expected %+v, got %+v", tc.code, byCode, byName)
+		}
+		if byCode.SemiMajorAxis != tc.semiMajorAxis {
+			t.Errorf("%s: expected semi-major axis %f, got %f", tc.code, tc.semiMajorAxis, byCode.SemiMajorAxis)
+		}
+		if math.Abs(byCode.InverseFlattening()-tc.invFlattening) > 1e-9 {
+			t.Errorf("%s: expected inverse flattening %f, got %f", tc.code, tc.invFlattening, byCode.InverseFlattening())
+		}
+	}
+
+	if _, err := coordconv.EllipsoidByCode("we"); err != nil {
+		t.Errorf("expected case insensitive code lookup, got %s", err)
+	}
+	if _, err := coordconv.EllipsoidByCode("ZZ"); err == nil {
+		t.Errorf("expected error for unknown ellipsoid code")
+	}
+	if _, err := coordconv.EllipsoidByName("Nowhere 1900"); err == nil {
+		t.Errorf("expected error for unknown ellipsoid name")
+	}
+
+	seen := map[string]bool{}
+	for _, e := range coordconv.Ellipsoids() {
+		if seen[e.Code] {
+			t.Errorf("duplicate ellipsoid code %s", e.Code)
+		}
+		seen[e.Code] = true
+	}
+	if !seen[coordconv.EllipsoidWGS84.Code] {
+		t.Errorf("expected WGS 84 in the built-in ellipsoids")
+	}
+}
+
+func TestEllipsoidConstructors(t *testing.T) {
+	clarke, err := coordconv.EllipsoidByCode("CC")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	fromEllipsoid, err := coordconv.NewMGRSEllipsoid(clarke)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	fromParameters, err := coordconv.NewMGRS(6378206.4, 1/294.9786982, "CC")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	wgs84, err := coordconv.NewMGRSEllipsoid(coordconv.EllipsoidWGS84)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	geo := s2.LatLngFromDegrees(38.8895, -77.0352)
+	expected, err := fromParameters.ConvertFromGeodetic(geo, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	got, err := fromEllipsoid.ConvertFromGeodetic(geo, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+	// Clarke 1866 uses the AL lettering scheme, so the 100,000m square
+	// letters differ from WGS 84.
+	other, err := wgs84.ConvertFromGeodetic(geo, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if other[3:5] == got[3:5] {
+		t.Errorf("expected different square letters, got %s and %s", other, got)
+	}
+
+	if _, err := coordconv.NewUTMEllipsoid(clarke, 0); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	}
+	if _, err := coordconv.NewUPSEllipsoid(clarke); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	}
+	if _, err := coordconv.NewTransverseMercatorEllipsoid(clarke, 0, 0, 500000, 0, 0.9996); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	}
+	if _, err := coordconv.NewPolarStereographicEllipsoid(clarke, 0, math.Pi/2, 0, 0); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	}
+	if _, err := coordconv.NewPolarStereographicScaleFactorEllipsoid(clarke, 0, 0.994,
+		coordconv.HemisphereNorth, 2000000, 2000000); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	}
+}
@@ -0,0 +1,183 @@
+// This file is a port of EllipticFunction.cpp from GeographicLib, and unlike
+// the rest of this package is not in the public domain but under the MIT
+// license:
+//
+// Copyright (c) Charles Karney (2008-2023) <karney@alum.mit.edu>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package coordconv
+
+import "math"
+
+// ellipticFunction evaluates the elliptic integrals and Jacobi elliptic
+// functions of a fixed parameter k^2 in [0, 1], as needed by the exact
+// Transverse Mercator projection.  The algorithms follow Carlson (1995) and
+// Bulirsch (1965).
+type ellipticFunction struct {
+	k2  float64 // Parameter, k^2
+	kp2 float64 // Complementary parameter, 1 - k^2
+	k   float64 // Complete integral of the first kind, K(k)
+	e   float64 // Complete integral of the second kind, E(k)
+	ke  float64 // K(k) - E(k)
+}
+
+// ellipticMaxTerms bounds the descending Landen transformations in sncndn.
+const ellipticMaxTerms = 13
+
+func newEllipticFunction(k2 float64) ellipticFunction {
+	kp2 := 1 - k2
+	ef := ellipticFunction{k2: k2, kp2: kp2}
+	ef.k = carlsonRF(0, kp2, 1)
+	ef.ke = k2 * carlsonRD(0, kp2, 1) / 3
+	ef.e = ef.k - ef.ke
+	return ef
+}
+
+// sncndn returns the Jacobi elliptic functions sn(x), cn(x) and dn(x).
+func (ef *ellipticFunction) sncndn(x float64) (sn, cn, dn float64) {
+	if ef.kp2 == 0 {
+		sn = math.Tanh(x)
+		cn = 1 / math.Cosh(x)
+		return sn, cn, cn
+	}
+	tolJAC := math.Sqrt(epsilon64 * 0.01)
+	var m, n [ellipticMaxTerms]float64
+	mc := ef.kp2
+	c := 0.0
+	l := 0
+	for a := 1.0; l < ellipticMaxTerms; {
+		m[l] = a
+		mc = math.Sqrt(mc)
+		n[l] = mc
+		c = (a + mc) / 2
+		if !(math.Abs(a-mc) > tolJAC*a) {
+			l++
+			break
+		}
+		mc *= a
+		a = c
+		l++
+	}
+	x *= c
+	sn = math.Sin(x)
+	cn = math.Cos(x)
+	dn = 1
+	if sn != 0 {
+		a := cn / sn
+		c *= a
+		for l--; l >= 0; l-- {
+			b := m[l]
+			a *= c
+			c *= dn
+			dn = (n[l] + a) / (b + a)
+			a = c / b
+		}
+		a = 1 / math.Sqrt(c*c+1)
+		if math.Signbit(sn) {
+			sn = -a
+		} else {
+			sn = a
+		}
+		cn = c * sn
+	}
+	return sn, cn, dn
+}
+
+// incompleteE returns the incomplete integral of the second kind, E(phi, k),
+// with the amplitude given by sn, cn and dn.
+func (ef *ellipticFunction) incompleteE(sn, cn, dn float64) float64 {
+	cn2 := cn * cn
+	dn2 := dn * dn
+	sn2 := sn * sn
+	ei := ef.e
+	if cn2 != 0 {
+		// DLMF 19.25.10
+		ei = math.Abs(sn) * (ef.kp2*carlsonRF(cn2, dn2, 1) +
+			ef.k2*ef.kp2*sn2*carlsonRD(cn2, 1, dn2)/3 +
+			ef.k2*math.Abs(cn)/dn)
+	}
+	if math.Signbit(cn) {
+		ei = 2*ef.e - ei
+	}
+	return math.Copysign(ei, sn)
+}
+
+const epsilon64 = 2.220446049250313e-16 // Machine epsilon for float64
+
+// carlsonRF is Carlson's symmetric integral of the first kind, RF(x, y, z).
+func carlsonRF(x, y, z float64) float64 {
+	tolRF := math.Pow(3*epsilon64*0.01, 1/8.0)
+	a0 := (x + y + z) / 3
+	an := a0
+	q := math.Max(math.Max(math.Abs(a0-x), math.Abs(a0-y)), math.Abs(a0-z)) / tolRF
+	x0, y0, z0 := x, y, z
+	mul := 1.0
+	for q >= mul*math.Abs(an) {
+		lam := math.Sqrt(x0)*math.Sqrt(y0) + math.Sqrt(y0)*math.Sqrt(z0) + math.Sqrt(z0)*math.Sqrt(x0)
+		an = (an + lam) / 4
+		x0 = (x0 + lam) / 4
+		y0 = (y0 + lam) / 4
+		z0 = (z0 + lam) / 4
+		mul *= 4
+	}
+	xx := (a0 - x) / (mul * an)
+	yy := (a0 - y) / (mul * an)
+	zz := -(xx + yy)
+	e2 := xx*yy - zz*zz
+	e3 := xx * yy * zz
+	// DLMF 19.36.1
+	return (e3*(6930*e3+e2*(15015*e2-16380)+17160) +
+		e2*((10010-5775*e2)*e2-24024) + 240240) /
+		(240240 * math.Sqrt(an))
+}
+
+// carlsonRD is Carlson's degenerate symmetric integral of the third kind,
+// RD(x, y, z).
+func carlsonRD(x, y, z float64) float64 {
+	tolRD := math.Pow(0.2*(epsilon64*0.01), 1/8.0)
+	a0 := (x + y + 3*z) / 5
+	an := a0
+	q := math.Max(math.Max(math.Abs(a0-x), math.Abs(a0-y)), math.Abs(a0-z)) / tolRD
+	x0, y0, z0 := x, y, z
+	mul := 1.0
+	s := 0.0
+	for q >= mul*math.Abs(an) {
+		lam := math.Sqrt(x0)*math.Sqrt(y0) + math.Sqrt(y0)*math.Sqrt(z0) + math.Sqrt(z0)*math.Sqrt(x0)
+		s += 1 / (mul * math.Sqrt(z0) * (z0 + lam))
+		an = (an + lam) / 4
+		x0 = (x0 + lam) / 4
+		y0 = (y0 + lam) / 4
+		z0 = (z0 + lam) / 4
+		mul *= 4
+	}
+	xx := (a0 - x) / (mul * an)
+	yy := (a0 - y) / (mul * an)
+	zz := -(xx + yy) / 3
+	e2 := xx*yy - 6*zz*zz
+	e3 := (3*xx*yy - 8*zz*zz) * zz
+	e4 := 3 * (xx*yy - zz*zz) * zz * zz
+	e5 := xx * yy * zz * zz * zz
+	// DLMF 19.36.2
+	return ((471240-540540*e2)*e5+
+		(612612*e2-540540*e3-556920)*e4+
+		e3*(306306*e3+e2*(675675*e2-706860)+680680)+
+		e2*((417690-255255*e2)*e2-875160)+4084080)/
+		(4084080*mul*an*math.Sqrt(an)) + 3*s
+}
@@ -0,0 +1,129 @@
+package coordconv
+
+import (
+	"errors"
+	"math"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// EquidistantCylindrical provides conversions between geodetic coordinates
+// (latitude and longitude) and Equidistant Cylindrical (plate carrée)
+// projection coordinates (easting and northing).  Meridians are true to scale
+// and parallels are equally spaced in longitude, with true scale along the
+// standard parallels.
+type EquidistantCylindrical struct {
+	semiMajorAxis float64
+	flattening    float64
+	rectifying    rectifying
+
+	// Equidistant Cylindrical projection Parameters
+	eqcyStandardParallel float64 // Latitude of true scale in radians
+	eqcyCentralMeridian  float64 // Longitude of origin in radians
+	eqcyFalseEasting     float64 // False easting in meters
+	eqcyFalseNorthing    float64 // False northing in meters
+	eqcyParallelRadius   float64 // Radius of the standard parallel
+}
+
+// NewEquidistantCylindrical constructs an Equidistant Cylindrical converter
+// with true scale along the standard parallel and its opposite.
+func NewEquidistantCylindrical(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+	standardParallel, falseEasting, falseNorthing float64) (*EquidistantCylindrical, error) {
+	if err := checkCylindricalParameters(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		centralMeridian); err != nil {
+		return nil, err
+	}
+	if (standardParallel <= -math.Pi/2) || (standardParallel >= math.Pi/2) {
+		return nil, errors.New("standardParallel out of range")
+	}
+
+	if centralMeridian > math.Pi {
+		centralMeridian -= 2 * math.Pi
+	}
+	es2 := 2*ellipsoidFlattening - ellipsoidFlattening*ellipsoidFlattening
+	sinLat := math.Sin(standardParallel)
+	return &EquidistantCylindrical{
+		semiMajorAxis:        ellipsoidSemiMajorAxis,
+		flattening:           ellipsoidFlattening,
+		rectifying:           newRectifying(ellipsoidSemiMajorAxis, ellipsoidFlattening),
+		eqcyStandardParallel: standardParallel,
+		eqcyCentralMeridian:  centralMeridian,
+		eqcyFalseEasting:     falseEasting,
+		eqcyFalseNorthing:    falseNorthing,
+		eqcyParallelRadius:   ellipsoidSemiMajorAxis * math.Cos(standardParallel) / math.Sqrt(1-es2*sinLat*sinLat),
+	}, nil
+}
+
+// NewEquidistantCylindricalEllipsoid constructs an Equidistant Cylindrical
+// converter for the given ellipsoid.
+func NewEquidistantCylindricalEllipsoid(ellipsoid Ellipsoid, centralMeridian,
+	standardParallel, falseEasting, falseNorthing float64) (*EquidistantCylindrical, error) {
+	return NewEquidistantCylindrical(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, centralMeridian,
+		standardParallel, falseEasting, falseNorthing)
+}
+
+// ConvertFromGeodetic converts geodetic coordinates (latitude and longitude) to
+// Equidistant Cylindrical coordinates (easting and northing), according to the
+// current ellipsoid and Equidistant Cylindrical projection parameters.
+func (e *EquidistantCylindrical) ConvertFromGeodetic(geodeticCoordinates s2.LatLng) (MapCoords, error) {
+	longitude := geodeticCoordinates.Lng.Radians()
+	latitude := geodeticCoordinates.Lat.Radians()
+
+	if (latitude < -math.Pi/2) || (latitude > math.Pi/2) {
+		return MapCoords{}, errors.New("latitude out of range")
+	}
+	if (longitude < -math.Pi) || (longitude > 2*math.Pi) {
+		return MapCoords{}, errors.New("longitude out of range")
+	}
+
+	dlam := longitude - e.eqcyCentralMeridian
+	if dlam > math.Pi {
+		dlam -= 2 * math.Pi
+	}
+	if dlam < -math.Pi {
+		dlam += 2 * math.Pi
+	}
+	return MapCoords{
+		Easting:  e.eqcyFalseEasting + e.eqcyParallelRadius*dlam,
+		Northing: e.eqcyFalseNorthing + e.rectifying.distance(latitude),
+	}, nil
+}
+
+// ConvertToGeodetic converts Equidistant Cylindrical coordinates (easting and
+// northing) to geodetic coordinates (latitude and longitude) according to the
+// current ellipsoid and Equidistant Cylindrical projection parameters.
+func (e *EquidistantCylindrical) ConvertToGeodetic(mapProjectionCoordinates MapCoords) (s2.LatLng, error) {
+	dx := mapProjectionCoordinates.Easting - e.eqcyFalseEasting
+	dy := mapProjectionCoordinates.Northing - e.eqcyFalseNorthing
+
+	if !(math.Abs(dy) <= e.rectifying.a*math.Pi/2*(1+1.0e-12)) {
+		return s2.LatLng{}, errors.New("northing out of range")
+	}
+	dlam := dx / e.eqcyParallelRadius
+	if !(math.Abs(dlam) <= math.Pi*(1+1.0e-12)) {
+		return s2.LatLng{}, errors.New("easting out of range")
+	}
+	latitude := math.Max(-math.Pi/2, math.Min(math.Pi/2, e.rectifying.latitude(dy)))
+	longitude := e.eqcyCentralMeridian + dlam
+	if longitude > math.Pi {
+		longitude -= 2 * math.Pi
+	} else if longitude < -math.Pi {
+		longitude += 2 * math.Pi
+	}
+	return s2.LatLng{Lat: s1.Angle(latitude), Lng: s1.Angle(longitude)}, nil
+}
+
+// Parameters returns the ellipsoid and Equidistant Cylindrical projection
+// parameters.
+func (e *EquidistantCylindrical) Parameters() ProjectionParameters {
+	return ProjectionParameters{
+		SemiMajorAxis:    e.semiMajorAxis,
+		Flattening:       e.flattening,
+		CentralMeridian:  e.eqcyCentralMeridian,
+		StandardParallel: e.eqcyStandardParallel,
+		FalseEasting:     e.eqcyFalseEasting,
+		FalseNorthing:    e.eqcyFalseNorthing,
+		ScaleFactor:      1.0,
+	}
+}
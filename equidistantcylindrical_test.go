@@ -0,0 +1,88 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestEquidistantCylindrical(t *testing.T) {
+	// EPSG Guidance Note 7-2, WGS 84 / World Equidistant Cylindrical
+	eqc, err := coordconv.NewEquidistantCylindricalEllipsoid(coordconv.EllipsoidWGS84, 0, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("error creating equidistant cylindrical converter: %s", err)
+	}
+	geo := s2.LatLngFromDegrees(55, 10)
+	expected := coordconv.MapCoords{Easting: 1113194.91, Northing: 6097230.31}
+
+	const epsilon = 0.01
+	mc, err := eqc.ConvertFromGeodetic(geo)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(mc.Easting-expected.Easting) > epsilon ||
+		math.Abs(mc.Northing-expected.Northing) > epsilon {
+		t.Errorf("expected %v, got %v", expected, mc)
+	}
+	geo2, err := eqc.ConvertToGeodetic(expected)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > epsilon {
+		t.Errorf("expected %s, got %s", geo, geo2)
+	}
+
+	// the quarter meridian, from the length of the meridian of WGS 84
+	mc, err = eqc.ConvertFromGeodetic(s2.LatLngFromDegrees(90, 0))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(mc.Northing-10001965.729) > 1e-3 {
+		t.Errorf("expected the pole at 10001965.729, got %v", mc)
+	}
+}
+
+func TestEquidistantCylindricalRoundTrip(t *testing.T) {
+	eqc, err := coordconv.NewEquidistantCylindrical(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		-30*math.Pi/180, 40*math.Pi/180, 500000, 100000)
+	if err != nil {
+		t.Fatalf("error creating equidistant cylindrical converter: %s", err)
+	}
+	for lng := -180.0; lng <= 180; lng += 15 {
+		for lat := -90.0; lat <= 90; lat += 10 {
+			geo := s2.LatLngFromDegrees(lat, lng)
+			mc, err := eqc.ConvertFromGeodetic(geo)
+			if err != nil {
+				t.Fatalf("expected no error at %s, got %s", geo, err)
+			}
+			geo2, err := eqc.ConvertToGeodetic(mc)
+			if err != nil {
+				t.Fatalf("expected no error in round trip at %s, got %s", geo, err)
+			}
+			if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > 1e-3 {
+				t.Errorf("expected %s, got %s", geo, geo2)
+			}
+		}
+	}
+
+	// true scale along the standard parallel
+	west, _ := eqc.ConvertFromGeodetic(s2.LatLngFromDegrees(40, -30))
+	east, _ := eqc.ConvertFromGeodetic(s2.LatLngFromDegrees(40, -29))
+	es2 := 2*ellipsoidFlattening - ellipsoidFlattening*ellipsoidFlattening
+	sinLat := math.Sin(40 * math.Pi / 180)
+	parallelRadius := ellipsoidSemiMajorAxis * math.Cos(40*math.Pi/180) / math.Sqrt(1-es2*sinLat*sinLat)
+	if math.Abs(east.Easting-west.Easting-parallelRadius*math.Pi/180) > 1e-6 {
+		t.Errorf("expected a degree of the standard parallel to be %f, got %f",
+			parallelRadius*math.Pi/180, east.Easting-west.Easting)
+	}
+
+	if _, err := eqc.ConvertToGeodetic(coordconv.MapCoords{Easting: 500000 + 4*parallelRadius, Northing: 100000}); err == nil {
+		t.Errorf("expected an error beyond the edge of the map")
+	}
+	if _, err := coordconv.NewEquidistantCylindrical(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		0, math.Pi/2, 0, 0); err == nil {
+		t.Errorf("expected an error for a standard parallel at the pole")
+	}
+}
@@ -0,0 +1,193 @@
+package coordconv
+
+import (
+	"errors"
+	"math"
+
+	"github.com/golang/geo/s2"
+)
+
+// gaussKrugerZonePrefix is the multiple of the zone number added to the false
+// easting of every Gauss-Kruger zone.
+const gaussKrugerZonePrefix = 1000000.0
+
+// GaussKruger is a Gauss-Kruger coordinate converter for the 3 and 6 degree
+// zone systems used by German, Russian and Chinese mapping.  Each zone is a
+// Transverse Mercator projection with unit scale on its central meridian and a
+// false easting of 500000 meters prefixed by the zone number, so the zone is
+// carried in the easting.  6 degree zones are numbered 1 to 60 eastward from
+// Greenwich and 3 degree zones are numbered by their central meridian divided
+// by 3, 1 to 120, with zone 120 on Greenwich.
+type GaussKruger struct {
+	semiMajorAxis         float64
+	flattening            float64
+	ellipsCode            string
+	zoneWidth             int
+	gkOverride            int
+	transverseMercatorMap []*TransverseMercator
+}
+
+// NewGaussKruger receives the ellipsoid parameters, the zone width of 3 or 6
+// degrees and the zone override parameter as inputs, and sets the
+// corresponding state variables.  override is the Gauss-Kruger override zone,
+// 0 indicates no override.
+func NewGaussKruger(ellipsoidSemiMajorAxis, ellipsoidFlattening float64,
+	ellipsoidCode string, zoneWidth int, override int) (*GaussKruger, error) {
+	invF := 1 / ellipsoidFlattening
+	if ellipsoidSemiMajorAxis <= 0.0 {
+		return nil, errors.New("Semi-major axis must be greater than zero")
+	}
+	if (invF < 250) || (invF > 350) {
+		return nil, errors.New("Inverse flattening must be between 250 and 350")
+	}
+	if (zoneWidth != 3) && (zoneWidth != 6) {
+		return nil, errors.New("zone width must be 3 or 6 degrees")
+	}
+	zones := 360 / zoneWidth
+	if (override < 0) || (override > zones) {
+		return nil, errors.New("zone override out of range")
+	}
+
+	g := &GaussKruger{
+		semiMajorAxis:         ellipsoidSemiMajorAxis,
+		flattening:            ellipsoidFlattening,
+		ellipsCode:            ellipsoidCode,
+		zoneWidth:             zoneWidth,
+		gkOverride:            override,
+		transverseMercatorMap: make([]*TransverseMercator, zones+1),
+	}
+
+	originLatitude := 0.0
+	falseNorthing := 0.0
+	scale := 1.0
+	for zone := 1; zone <= zones; zone++ {
+		centralMeridian := float64(g.centralMeridian(zone)) * math.Pi / 180
+		falseEasting := float64(zone)*gaussKrugerZonePrefix + 500000.0
+
+		var err error
+		g.transverseMercatorMap[zone], err = NewTransverseMercator(
+			g.semiMajorAxis, g.flattening, centralMeridian, originLatitude,
+			falseEasting, falseNorthing, scale, g.ellipsCode)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// NewGaussKrugerEllipsoid constructs a Gauss-Kruger converter for the given
+// ellipsoid.  override is the Gauss-Kruger override zone, 0 indicates no
+// override.
+func NewGaussKrugerEllipsoid(ellipsoid Ellipsoid, zoneWidth int, override int) (*GaussKruger, error) {
+	return NewGaussKruger(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, ellipsoid.Code, zoneWidth, override)
+}
+
+// centralMeridian returns the central meridian in degrees, from -180 to 180,
+// of the given zone.
+func (g *GaussKruger) centralMeridian(zone int) int {
+	var centralMeridian int
+	if g.zoneWidth == 6 {
+		centralMeridian = 6*zone - 3
+	} else {
+		centralMeridian = 3 * zone
+	}
+	if centralMeridian > 180 {
+		centralMeridian -= 360
+	}
+	return centralMeridian
+}
+
+// Zone returns the zone number carried in the easting of Gauss-Kruger
+// coordinates.
+func (g *GaussKruger) Zone(gaussKrugerCoordinates MapCoords) int {
+	return int(math.Floor(gaussKrugerCoordinates.Easting / gaussKrugerZonePrefix))
+}
+
+// ConvertFromGeodetic converts geodetic (latitude and longitude) coordinates
+// to Gauss-Kruger coordinates (easting prefixed by the zone, and northing)
+// according to the current ellipsoid and zone override parameters.  A non-zero
+// gkZoneOverride selects a zone adjacent to the computed one.
+func (g *GaussKruger) ConvertFromGeodetic(geodeticCoordinates s2.LatLng, gkZoneOverride int) (MapCoords, error) {
+	zone, err := g.zone(geodeticCoordinates, gkZoneOverride)
+	if err != nil {
+		return MapCoords{}, err
+	}
+	return g.transverseMercatorMap[zone].ConvertFromGeodetic(geodeticCoordinates)
+}
+
+// ConvertToGeodetic converts Gauss-Kruger coordinates (easting prefixed by the
+// zone, and northing) to geodetic (latitude and longitude) coordinates,
+// according to the current ellipsoid parameters.
+func (g *GaussKruger) ConvertToGeodetic(gaussKrugerCoordinates MapCoords) (s2.LatLng, error) {
+	zone := g.Zone(gaussKrugerCoordinates)
+	if (zone < 1) || (zone >= len(g.transverseMercatorMap)) {
+		return s2.LatLng{}, errors.New("zone out of range")
+	}
+	return g.transverseMercatorMap[zone].ConvertToGeodetic(gaussKrugerCoordinates)
+}
+
+// ConvertFromGeodeticFactors converts geodetic (latitude and longitude)
+// coordinates to Gauss-Kruger coordinates and also returns the meridian
+// convergence and point scale factor at the point.
+func (g *GaussKruger) ConvertFromGeodeticFactors(geodeticCoordinates s2.LatLng, gkZoneOverride int) (MapCoords, GridFactors, error) {
+	zone, err := g.zone(geodeticCoordinates, gkZoneOverride)
+	if err != nil {
+		return MapCoords{}, GridFactors{}, err
+	}
+	return g.transverseMercatorMap[zone].ConvertFromGeodeticFactors(geodeticCoordinates)
+}
+
+// ConvertToGeodeticFactors converts Gauss-Kruger coordinates to geodetic
+// (latitude and longitude) coordinates and also returns the meridian
+// convergence and point scale factor at the point.
+func (g *GaussKruger) ConvertToGeodeticFactors(gaussKrugerCoordinates MapCoords) (s2.LatLng, GridFactors, error) {
+	zone := g.Zone(gaussKrugerCoordinates)
+	if (zone < 1) || (zone >= len(g.transverseMercatorMap)) {
+		return s2.LatLng{}, GridFactors{}, errors.New("zone out of range")
+	}
+	return g.transverseMercatorMap[zone].ConvertToGeodeticFactors(gaussKrugerCoordinates)
+}
+
+// zone selects the zone of the given point, allowing the zone override to
+// move it by up to one zone either way.
+func (g *GaussKruger) zone(geodeticCoordinates s2.LatLng, gkZoneOverride int) (int, error) {
+	longitude := geodeticCoordinates.Lng.Radians()
+	latitude := geodeticCoordinates.Lat.Radians()
+	if (latitude < -math.Pi/2) || (latitude > math.Pi/2) {
+		return 0, errors.New("latitude out of range")
+	}
+	if (longitude < (-math.Pi - epsilonRadians)) ||
+		(longitude > (2*math.Pi + epsilonRadians)) {
+		return 0, errors.New("longitude out of range")
+	}
+
+	if longitude < 0 {
+		longitude += (2 * math.Pi)
+	}
+	zones := len(g.transverseMercatorMap) - 1
+	degrees := (longitude + 1.0e-10) * 180.0 / math.Pi
+	var tempZone int
+	if g.zoneWidth == 6 {
+		tempZone = int(degrees/6.0) + 1
+	} else {
+		tempZone = int(degrees/3.0 + 0.5)
+	}
+	tempZone = (tempZone+zones-1)%zones + 1
+
+	override := gkZoneOverride
+	if override == 0 {
+		override = g.gkOverride
+	}
+	if override != 0 {
+		if (override < 1) || (override > zones) {
+			return 0, errors.New("zone out of range")
+		}
+		// allow zone override up to +/- one zone of the calculated zone
+		diff := (override - tempZone + zones) % zones
+		if (diff != 0) && (diff != 1) && (diff != zones-1) {
+			return 0, errors.New("zone out of range")
+		}
+		tempZone = override
+	}
+	return tempZone, nil
+}
@@ -0,0 +1,140 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestGaussKruger(t *testing.T) {
+	bessel, _ := coordconv.EllipsoidByCode("BR")
+	krassovsky, _ := coordconv.EllipsoidByCode("KA")
+
+	testCases := []struct {
+		name            string
+		ellipsoid       coordconv.Ellipsoid
+		zoneWidth       int
+		geo             s2.LatLng
+		zone            int
+		centralMeridian float64
+	}{
+		{"3 degree berlin", bessel, 3, s2.LatLngFromDegrees(52.5163, 13.3777), 4, 12},
+		{"3 degree munich", bessel, 3, s2.LatLngFromDegrees(48.137, 11.575), 4, 12},
+		{"3 degree cologne", bessel, 3, s2.LatLngFromDegrees(50.9375, 6.9603), 2, 6},
+		{"3 degree greenwich", bessel, 3, s2.LatLngFromDegrees(51.4779, -0.0015), 120, 0},
+		{"6 degree moscow", krassovsky, 6, s2.LatLngFromDegrees(55.7558, 37.6173), 7, 39},
+		{"6 degree beijing", krassovsky, 6, s2.LatLngFromDegrees(39.9042, 116.4074), 20, 117},
+		{"6 degree anadyr", krassovsky, 6, s2.LatLngFromDegrees(64.7337, 177.4968), 30, 177},
+		{"6 degree west", krassovsky, 6, s2.LatLngFromDegrees(40, -1), 60, -3},
+	}
+
+	for _, tc := range testCases {
+		gk, err := coordconv.NewGaussKrugerEllipsoid(tc.ellipsoid, tc.zoneWidth, 0)
+		if err != nil {
+			t.Fatalf("%s: error creating gauss kruger converter: %s", tc.name, err)
+		}
+		mc, factors, err := gk.ConvertFromGeodeticFactors(tc.geo, 0)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if zone := gk.Zone(mc); zone != tc.zone {
+			t.Errorf("%s: expected zone %d, got %d from %v", tc.name, tc.zone, zone, mc)
+		}
+
+		tm, err := coordconv.NewTransverseMercatorEllipsoid(tc.ellipsoid, tc.centralMeridian*math.Pi/180, 0,
+			float64(tc.zone)*1000000+500000, 0, 1)
+		if err != nil {
+			t.Fatalf("%s: error creating transverse mercator converter: %s", tc.name, err)
+		}
+		expected, err := tm.ConvertFromGeodetic(tc.geo)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if math.Abs(mc.Easting-expected.Easting) > 1e-6 || math.Abs(mc.Northing-expected.Northing) > 1e-6 {
+			t.Errorf("%s: expected %v, got %v", tc.name, expected, mc)
+		}
+		checkGridFactors(t, tc.name, tm.ConvertFromGeodetic,
+			tc.ellipsoid.SemiMajorAxis, tc.ellipsoid.Flattening, tc.geo, factors)
+
+		geo, _, err := gk.ConvertToGeodeticFactors(mc)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if geo.Distance(tc.geo).Radians()*tc.ellipsoid.SemiMajorAxis > 1e-3 {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.geo, geo)
+		}
+	}
+}
+
+func TestGaussKrugerOverride(t *testing.T) {
+	bessel, _ := coordconv.EllipsoidByCode("BR")
+	gk, err := coordconv.NewGaussKrugerEllipsoid(bessel, 3, 0)
+	if err != nil {
+		t.Fatalf("error creating gauss kruger converter: %s", err)
+	}
+	berlin := s2.LatLngFromDegrees(52.5163, 13.3777)
+
+	// Berlin is mapped in the neighbouring zone 5 as well as zone 4
+	mc, err := gk.ConvertFromGeodetic(berlin, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if zone := gk.Zone(mc); zone != 5 {
+		t.Errorf("expected zone 5, got %d", zone)
+	}
+	geo, err := gk.ConvertToGeodetic(mc)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if geo.Distance(berlin).Radians()*bessel.SemiMajorAxis > 1e-3 {
+		t.Errorf("expected %s, got %s", berlin, geo)
+	}
+	if _, err := gk.ConvertFromGeodetic(berlin, 6); err == nil {
+		t.Errorf("expected an error overriding two zones away")
+	}
+
+	// the converter's override applies when none is given
+	gk5, err := coordconv.NewGaussKrugerEllipsoid(bessel, 3, 5)
+	if err != nil {
+		t.Fatalf("error creating gauss kruger converter: %s", err)
+	}
+	mc5, err := gk5.ConvertFromGeodetic(berlin, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if mc5 != mc {
+		t.Errorf("expected %v, got %v", mc, mc5)
+	}
+
+	// overrides wrap around the antimeridian and Greenwich
+	gk6, err := coordconv.NewGaussKrugerEllipsoid(bessel, 6, 0)
+	if err != nil {
+		t.Fatalf("error creating gauss kruger converter: %s", err)
+	}
+	if mc, err := gk6.ConvertFromGeodetic(s2.LatLngFromDegrees(50, 1), 60); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	} else if zone := gk6.Zone(mc); zone != 60 {
+		t.Errorf("expected zone 60, got %d", zone)
+	}
+}
+
+func TestGaussKrugerInvalid(t *testing.T) {
+	if _, err := coordconv.NewGaussKruger(ellipsoidSemiMajorAxis, ellipsoidFlattening, "WE", 4, 0); err == nil {
+		t.Errorf("expected an error for a 4 degree zone width")
+	}
+	if _, err := coordconv.NewGaussKruger(ellipsoidSemiMajorAxis, ellipsoidFlattening, "WE", 6, 61); err == nil {
+		t.Errorf("expected an error for a zone override out of range")
+	}
+	gk, err := coordconv.NewGaussKruger(ellipsoidSemiMajorAxis, ellipsoidFlattening, "WE", 6, 0)
+	if err != nil {
+		t.Fatalf("error creating gauss kruger converter: %s", err)
+	}
+	if _, err := gk.ConvertToGeodetic(coordconv.MapCoords{Easting: 500000, Northing: 5000000}); err == nil {
+		t.Errorf("expected an error for an easting without a zone")
+	}
+	if _, err := gk.ConvertToGeodetic(coordconv.MapCoords{Easting: 61500000, Northing: 5000000}); err == nil {
+		t.Errorf("expected an error for zone 61")
+	}
+}
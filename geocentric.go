@@ -0,0 +1,151 @@
+package coordconv
+
+import (
+	"errors"
+	"math"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// GeocentricCoord is an earth centered, earth fixed coordinate in meters.
+type GeocentricCoord struct {
+	X float64
+	Y float64
+	Z float64
+}
+
+// Geocentric is a coordinate converter to and from earth centered, earth
+// fixed (ECEF) coordinates.
+type Geocentric struct {
+	semiMajorAxis float64
+	flattening    float64
+	semiMinorAxis float64
+	es2           float64 // Eccentricity squared
+	ep2           float64 // Second eccentricity squared
+}
+
+// NewGeocentric constructs a geocentric converter with ellipsoid parameters.
+func NewGeocentric(ellipsoidSemiMajorAxis, ellipsoidFlattening float64) (*Geocentric, error) {
+	invF := 1 / ellipsoidFlattening
+	if ellipsoidSemiMajorAxis <= 0.0 {
+		return nil, errors.New("Semi-major axis must be greater than zero")
+	}
+	if (invF < 250) || (invF > 350) {
+		return nil, errors.New("Inverse flattening must be between 250 and 350")
+	}
+	return newGeocentric(ellipsoidSemiMajorAxis, ellipsoidFlattening), nil
+}
+
+// NewGeocentricEllipsoid constructs a geocentric converter for the given
+// ellipsoid.
+func NewGeocentricEllipsoid(ellipsoid Ellipsoid) (*Geocentric, error) {
+	return NewGeocentric(ellipsoid.SemiMajorAxis, ellipsoid.Flattening)
+}
+
+func newGeocentric(ellipsoidSemiMajorAxis, ellipsoidFlattening float64) *Geocentric {
+	g := &Geocentric{
+		semiMajorAxis: ellipsoidSemiMajorAxis,
+		flattening:    ellipsoidFlattening,
+	}
+	g.semiMinorAxis = g.semiMajorAxis * (1 - g.flattening)
+	g.es2 = 2*g.flattening - g.flattening*g.flattening
+	g.ep2 = g.es2 / (1 - g.es2)
+	return g
+}
+
+// ConvertFromGeodetic converts geodetic coordinates (latitude, longitude and
+// height above the ellipsoid in meters) to geocentric coordinates.
+func (g *Geocentric) ConvertFromGeodetic(geodeticCoordinates s2.LatLng, height float64) (GeocentricCoord, error) {
+	latitude := geodeticCoordinates.Lat.Radians()
+	longitude := geodeticCoordinates.Lng.Radians()
+
+	if (latitude < -math.Pi/2) || (latitude > math.Pi/2) {
+		return GeocentricCoord{}, errors.New("latitude out of range")
+	}
+	if (longitude < -math.Pi) || (longitude > 2*math.Pi) {
+		return GeocentricCoord{}, errors.New("longitude out of range")
+	}
+	x, y, z := g.forward(latitude, longitude, height)
+	return GeocentricCoord{X: x, Y: y, Z: z}, nil
+}
+
+// ConvertToGeodetic converts geocentric coordinates to geodetic coordinates
+// and the height above the ellipsoid in meters.
+func (g *Geocentric) ConvertToGeodetic(geocentricCoordinates GeocentricCoord) (s2.LatLng, float64, error) {
+	x := geocentricCoordinates.X
+	y := geocentricCoordinates.Y
+	z := geocentricCoordinates.Z
+	if math.IsNaN(x) || math.IsNaN(y) || math.IsNaN(z) ||
+		math.IsInf(x, 0) || math.IsInf(y, 0) || math.IsInf(z, 0) {
+		return s2.LatLng{}, 0, errors.New("geocentric coordinates out of range")
+	}
+	latitude, longitude, height := g.inverse(x, y, z)
+	return s2.LatLng{Lat: s1.Angle(latitude), Lng: s1.Angle(longitude)}, height, nil
+}
+
+// forward converts geodetic coordinates in radians and height to geocentric
+// coordinates.
+func (g *Geocentric) forward(latitude, longitude, height float64) (float64, float64, float64) {
+	sinLat := math.Sin(latitude)
+	cosLat := math.Cos(latitude)
+	rn := g.semiMajorAxis / math.Sqrt(1-g.es2*sinLat*sinLat) // Radius of curvature in the prime vertical
+	return (rn + height) * cosLat * math.Cos(longitude),
+		(rn + height) * cosLat * math.Sin(longitude),
+		(rn*(1-g.es2) + height) * sinLat
+}
+
+// inverse converts geocentric coordinates to geodetic coordinates in radians
+// and height using Heikkinen's closed form solution.  A couple of fixed point
+// steps on the latitude polish the result and also cover points deep inside
+// the ellipsoid, where the closed form breaks down.
+func (g *Geocentric) inverse(x, y, z float64) (float64, float64, float64) {
+	a := g.semiMajorAxis
+	b := g.semiMinorAxis
+	es2 := g.es2
+	r := math.Hypot(x, y)
+	longitude := math.Atan2(y, x)
+
+	if r == 0 {
+		// on the polar axis
+		if z < 0 {
+			return -math.Pi / 2, longitude, -z - b
+		}
+		return math.Pi / 2, longitude, z - b
+	}
+
+	a2 := a * a
+	b2 := b * b
+	z2 := z * z
+	f := 54 * b2 * z2
+	gg := r*r + (1-es2)*z2 - es2*(a2-b2)
+	c := es2 * es2 * f * r * r / (gg * gg * gg)
+	s := math.Cbrt(1 + c + math.Sqrt(c*c+2*c))
+	k := s + 1/s + 1
+	p := f / (3 * k * k * gg * gg)
+	q := math.Sqrt(1 + 2*es2*es2*p)
+	r0 := -p*es2*r/(1+q) +
+		math.Sqrt(math.Max(0, a2/2*(1+1/q)-p*(1-es2)*z2/(q*(1+q))-p*r*r/2))
+	u := math.Hypot(r-es2*r0, z)
+	v := math.Sqrt((r-es2*r0)*(r-es2*r0) + (1-es2)*z2)
+	z0 := b2 * z / (a * v)
+	height := u * (1 - b2/(a*v))
+	latitude := math.Atan2(z+g.ep2*z0, r)
+
+	if math.IsNaN(latitude) || math.IsNaN(height) {
+		// deep inside the ellipsoid the closed form breaks down
+		latitude = math.Atan2(z, r*(1-es2))
+	}
+
+	// refine along the normal through the point
+	for i := 0; i < 2; i++ {
+		sinLat := math.Sin(latitude)
+		cosLat := math.Cos(latitude)
+		rn := a / math.Sqrt(1-es2*sinLat*sinLat)
+		height = r*cosLat + z*sinLat - a*a/rn
+		latitude = math.Atan2(z*(rn+height), r*(rn*(1-es2)+height))
+	}
+	sinLat := math.Sin(latitude)
+	height = r*math.Cos(latitude) + z*sinLat - a*math.Sqrt(1-es2*sinLat*sinLat)
+	return latitude, longitude, height
+}
@@ -0,0 +1,104 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestGeocentric(t *testing.T) {
+	g, err := coordconv.NewGeocentricEllipsoid(coordconv.EllipsoidWGS84)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	testCases := []struct {
+		geo      s2.LatLng
+		height   float64
+		expected coordconv.GeocentricCoord
+	}{
+		{s2.LatLngFromDegrees(0, 0), 0, coordconv.GeocentricCoord{X: 6378137}},
+		{s2.LatLngFromDegrees(0, 90), 0, coordconv.GeocentricCoord{Y: 6378137}},
+		{s2.LatLngFromDegrees(90, 0), 0, coordconv.GeocentricCoord{Z: 6356752.314245}},
+		{s2.LatLngFromDegrees(-90, 0), 100, coordconv.GeocentricCoord{Z: -6356852.314245}},
+		// EPSG guidance note 7-2 example for geographic/geocentric conversions
+		{s2.LatLngFromDegrees(53+48.0/60+33.820/3600, 2+7.0/60+46.380/3600), 73,
+			coordconv.GeocentricCoord{X: 3771793.968, Y: 140253.342, Z: 5124304.349}},
+	}
+	for _, tc := range testCases {
+		c, err := g.ConvertFromGeodetic(tc.geo, tc.height)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.geo, err)
+		}
+		if math.Abs(c.X-tc.expected.X) > 1e-3 || math.Abs(c.Y-tc.expected.Y) > 1e-3 ||
+			math.Abs(c.Z-tc.expected.Z) > 1e-3 {
+			t.Errorf("%s: expected %+v, got %+v", tc.geo, tc.expected, c)
+		}
+		geo, height, err := g.ConvertToGeodetic(tc.expected)
+		if err != nil {
+			t.Fatalf("%+v: expected no error, got %s", tc.expected, err)
+		}
+		if math.Abs(geo.Lat.Degrees()-tc.geo.Lat.Degrees()) > 1e-8 ||
+			(math.Abs(tc.geo.Lat.Degrees()) != 90 && math.Abs(geo.Lng.Degrees()-tc.geo.Lng.Degrees()) > 1e-8) {
+			t.Errorf("%+v: expected %s, got %s", tc.expected, tc.geo, geo)
+		}
+		if math.Abs(height-tc.height) > 1e-3 {
+			t.Errorf("%+v: expected height %f, got %f", tc.expected, tc.height, height)
+		}
+	}
+}
+
+func TestGeocentricRoundTrip(t *testing.T) {
+	g, err := coordconv.NewGeocentric(ellipsoidSemiMajorAxis, ellipsoidFlattening)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	for _, height := range []float64{-6000000, -10000, 0, 8848, 400000, 36000000} {
+		for lat := -90.0; lat <= 90; lat += 0.5 {
+			for lng := -180.0; lng < 180; lng += 37 {
+				geo := s2.LatLngFromDegrees(lat, lng)
+				c, err := g.ConvertFromGeodetic(geo, height)
+				if err != nil {
+					t.Fatalf("%s: expected no error, got %s", geo, err)
+				}
+				back, backHeight, err := g.ConvertToGeodetic(c)
+				if err != nil {
+					t.Fatalf("%+v: expected no error, got %s", c, err)
+				}
+				if dist := back.Distance(geo).Radians() * (ellipsoidSemiMajorAxis + height); dist > 1e-4 {
+					t.Errorf("%s at %fm: expected no change, got %s (%gm)", geo, height, back, dist)
+				}
+				if math.Abs(backHeight-height) > 1e-4 {
+					t.Errorf("%s: expected height %f, got %f", geo, height, backHeight)
+				}
+			}
+		}
+	}
+
+	if _, err := coordconv.NewGeocentric(ellipsoidSemiMajorAxis, 1/100.0); err == nil {
+		t.Errorf("expected error for invalid flattening")
+	}
+	if _, err := g.ConvertFromGeodetic(s2.LatLngFromDegrees(91, 0), 0); err == nil {
+		t.Errorf("expected error for latitude out of range")
+	}
+	if _, _, err := g.ConvertToGeodetic(coordconv.GeocentricCoord{X: math.NaN()}); err == nil {
+		t.Errorf("expected error for invalid coordinates")
+	}
+}
+
+func TestMGRSGeocentric(t *testing.T) {
+	const mgrs = "16SGC3855124838"
+	c, err := coordconv.DefaultMGRSConverter.ConvertToGeocentric(mgrs, 250)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	got, err := coordconv.DefaultMGRSConverter.ConvertFromGeocentric(c, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if got != mgrs {
+		t.Errorf("expected %s, got %s", mgrs, got)
+	}
+}
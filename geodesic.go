@@ -0,0 +1,1086 @@
+// This file is a port of geodesic.c from GeographicLib, and unlike the rest of
+// this package is not in the public domain but under the MIT license:
+//
+// Copyright (c) Charles Karney (2012-2023) <karney@alum.mit.edu>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package coordconv
+
+import "math"
+
+// geodesic solves the direct and inverse geodesic problems on an ellipsoid of
+// revolution.  It is a port of GeographicLib's implementation of the series
+// solution of C. F. F. Karney, "Algorithms for geodesics", J. Geodesy 87,
+// 43-55 (2013), to sixth order in the third flattening.  Latitudes,
+// longitudes and azimuths are in degrees, so that the reductions of angles can
+// be done exactly.
+type geodesic struct {
+	a, f, f1, e2, ep2, n, b float64
+	etol2                   float64
+	a3x                     [geodesicNA3]float64
+	c3x                     [geodesicNC3x]float64
+}
+
+const geodesicOrder = 6
+const geodesicNA1 = geodesicOrder
+const geodesicNC1 = geodesicOrder
+const geodesicNC1p = geodesicOrder
+const geodesicNA2 = geodesicOrder
+const geodesicNC2 = geodesicOrder
+const geodesicNA3 = geodesicOrder
+const geodesicNC3 = geodesicOrder
+const geodesicNC3x = (geodesicNC3 * (geodesicNC3 - 1)) / 2
+const geodesicNC = geodesicOrder + 1
+
+const geodesicDigits = 53
+const geodesicMaxit1 = 20
+const geodesicMaxit2 = geodesicMaxit1 + geodesicDigits + 10
+
+var geodesicTol0 = epsilon64
+var geodesicTol1 = 200 * geodesicTol0
+var geodesicTol2 = math.Sqrt(geodesicTol0)
+var geodesicTolb = geodesicTol0 * geodesicTol2
+var geodesicXthresh = 1000 * geodesicTol2
+var geodesicTiny = math.Sqrt(math.SmallestNonzeroFloat64 * (1 << 52))
+
+const degree = math.Pi / 180
+
+func newGeodesic(semiMajorAxis, flattening float64) *geodesic {
+	g := &geodesic{a: semiMajorAxis, f: flattening}
+	g.f1 = 1 - g.f
+	g.e2 = g.f * (2 - g.f)
+	g.ep2 = g.e2 / (g.f1 * g.f1)
+	g.n = g.f / (2 - g.f)
+	g.b = g.a * g.f1
+	g.etol2 = 0.1 * geodesicTol2 /
+		math.Sqrt(math.Max(0.001, math.Abs(g.f))*math.Min(1, 1-g.f/2)/2)
+	g.a3coeff()
+	g.c3coeff()
+	return g
+}
+
+// inverse solves the inverse geodesic problem, returning the arc length on
+// the auxiliary sphere in degrees, the distance between the points, the
+// azimuths at each point, the reduced length and the geodesic scales.
+func (g *geodesic) inverse(lat1, lon1, lat2, lon2 float64) (a12, s12, azi1, azi2, m12, M12, M21 float64) {
+	var salp1, calp1, salp2, calp2 float64
+	var s12x, m12x float64
+	var Ca [geodesicNC]float64
+
+	lon12, lon12s := angDiff(lon1, lon2)
+	lonsign := 1.0
+	if lon12 < 0 {
+		lonsign = -1
+	}
+	// If very close to being on the same half-meridian, then make it so.
+	lon12 = lonsign * angRound(lon12)
+	lon12s = angRound((180 - lon12) - lonsign*lon12s)
+	lam12 := lon12 * degree
+	var slam12, clam12 float64
+	if lon12 > 90 {
+		slam12, clam12 = sincosd(lon12s)
+		clam12 = -clam12
+	} else {
+		slam12, clam12 = sincosd(lon12)
+	}
+
+	// If really close to the equator, treat as on equator.
+	lat1 = angRound(latFix(lat1))
+	lat2 = angRound(latFix(lat2))
+	// Swap points so that point with higher (abs) latitude is point 1.
+	swapp := 1.0
+	if math.Abs(lat1) < math.Abs(lat2) {
+		swapp = -1
+		lonsign *= -1
+		lat1, lat2 = lat2, lat1
+	}
+	// Make lat1 <= 0.
+	latsign := -1.0
+	if lat1 < 0 {
+		latsign = 1
+	}
+	lat1 *= latsign
+	lat2 *= latsign
+
+	sbet1, cbet1 := sincosd(lat1)
+	sbet1 *= g.f1
+	sbet1, cbet1 = norm2(sbet1, cbet1)
+	cbet1 = math.Max(geodesicTiny, cbet1)
+
+	sbet2, cbet2 := sincosd(lat2)
+	sbet2 *= g.f1
+	sbet2, cbet2 = norm2(sbet2, cbet2)
+	cbet2 = math.Max(geodesicTiny, cbet2)
+
+	// If cbet1 < -sbet1, then cbet2 - cbet1 is a sensitive measure of the
+	// |bet1| - |bet2|.  Alternatively (cbet1 >= -sbet1), abs(sbet2) + sbet1 is
+	// a better measure.
+	if cbet1 < -sbet1 {
+		if cbet2 == cbet1 {
+			sbet2 = math.Copysign(sbet1, sbet2)
+		}
+	} else if math.Abs(sbet2) == -sbet1 {
+		cbet2 = cbet1
+	}
+
+	dn1 := math.Sqrt(1 + g.ep2*sbet1*sbet1)
+	dn2 := math.Sqrt(1 + g.ep2*sbet2*sbet2)
+
+	var sig12 float64
+	meridian := lat1 == -90 || slam12 == 0
+	if meridian {
+		// Endpoints are on a single full meridian, so the geodesic might lie
+		// on a meridian.
+		calp1, salp1 = clam12, slam12 // Head to the target longitude
+		calp2, salp2 = 1, 0           // At the target we're heading north
+
+		ssig1, csig1 := sbet1, calp1*cbet1
+		ssig2, csig2 := sbet2, calp2*cbet2
+
+		sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2)+0, csig1*csig2+ssig1*ssig2)
+		s12x, m12x, _, M12, M21 = g.lengths(g.n, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2,
+			cbet1, cbet2, Ca[:])
+		// Add the check for sig12 since zero length geodesics might yield m12
+		// < 0.  Test case was
+		//
+		//    echo 20.001 0 20.001 0 | GeodSolve -i
+		//
+		// In fact, we will have sig12 > pi/2 for meridional geodesic which is
+		// not a shortest path.
+		if sig12 < 1 || m12x >= 0 {
+			// Need at least 2, to handle 90 0 90 180
+			if sig12 < 3*geodesicTiny || (sig12 < geodesicTol0 && (s12x < 0 || m12x < 0)) {
+				sig12, m12x, s12x = 0, 0, 0
+			}
+			m12x *= g.b
+			s12x *= g.b
+			a12 = sig12 / degree
+		} else {
+			// m12 < 0, i.e., prolate and too close to anti-podal
+			meridian = false
+		}
+	}
+
+	if !meridian && sbet1 == 0 && (g.f <= 0 || lon12s >= g.f*180) {
+		// Geodesic runs along equator
+		calp1, calp2 = 0, 0
+		salp1, salp2 = 1, 1
+		s12x = g.a * lam12
+		sig12 = lam12 / g.f1
+		m12x = g.b * math.Sin(sig12)
+		M12 = math.Cos(sig12)
+		M21 = M12
+		a12 = lon12 / g.f1
+	} else if !meridian {
+		// Now point1 and point2 belong within a hemisphere bounded by a
+		// meridian and geodesic is neither meridional or equatorial.
+
+		// Figure a starting point for Newton's method
+		var dnm float64
+		sig12, salp1, calp1, salp2, calp2, dnm = g.inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2,
+			lam12, slam12, clam12, Ca[:])
+
+		if sig12 >= 0 {
+			// Short lines (inverseStart sets salp2, calp2, dnm)
+			s12x = sig12 * g.b * dnm
+			m12x = dnm * dnm * g.b * math.Sin(sig12/dnm)
+			M12 = math.Cos(sig12 / dnm)
+			M21 = M12
+			a12 = sig12 / degree
+		} else {
+			// Newton's method.  This is a straightforward solution of f(alp1)
+			// = lambda12(alp1) - lam12 = 0 with one wrinkle.  f(alp) has
+			// exactly one root in the interval (0, pi) and its derivative is
+			// positive at the root.  Thus f(alp) is positive for alp > alp1
+			// and negative for alp < alp1.  During the course of the
+			// iteration, a range (alp1a, alp1b) is maintained which brackets
+			// the root and with each evaluation of f(alp) the range is
+			// shrunk, if possible.  Newton's method is restarted whenever the
+			// derivative of f is negative (because the new value of alp1 is
+			// then further from the solution) or if the new estimate of alp1
+			// lies outside (0,pi); in this case, the new starting guess is
+			// taken to be (alp1a + alp1b) / 2.
+			var ssig1, csig1, ssig2, csig2, eps float64
+			// Bracketing range
+			salp1a, calp1a, salp1b, calp1b := geodesicTiny, 1.0, geodesicTiny, -1.0
+			tripn, tripb := false, false
+			for numit := 0; ; numit++ {
+				var v, dv float64
+				v, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, dv = g.lambda12(sbet1, cbet1, dn1,
+					sbet2, cbet2, dn2, salp1, calp1, slam12, clam12, numit < geodesicMaxit1, Ca[:])
+				tol := 1.0
+				if tripn {
+					tol = 8
+				}
+				// Reversed test to allow escape with NaNs
+				if tripb || !(math.Abs(v) >= tol*geodesicTol0) || numit == geodesicMaxit2 {
+					break
+				}
+				// Update bracketing values
+				if v > 0 && (numit > geodesicMaxit1 || calp1/salp1 > calp1b/salp1b) {
+					salp1b, calp1b = salp1, calp1
+				} else if v < 0 && (numit > geodesicMaxit1 || calp1/salp1 < calp1a/salp1a) {
+					salp1a, calp1a = salp1, calp1
+				}
+				if numit < geodesicMaxit1 && dv > 0 {
+					dalp1 := -v / dv
+					if math.Abs(dalp1) < math.Pi {
+						sdalp1, cdalp1 := math.Sincos(dalp1)
+						nsalp1 := salp1*cdalp1 + calp1*sdalp1
+						if nsalp1 > 0 {
+							calp1 = calp1*cdalp1 - salp1*sdalp1
+							salp1 = nsalp1
+							salp1, calp1 = norm2(salp1, calp1)
+							// In some regimes we don't get quadratic
+							// convergence because slope -> 0.  So use
+							// convergence conditions based on epsilon
+							// instead of sqrt(epsilon).
+							tripn = math.Abs(v) <= 16*geodesicTol0
+							continue
+						}
+					}
+				}
+				// Either dv was not positive or updated value was outside
+				// legal range.  Use the midpoint of the bracket as the next
+				// estimate.
+				salp1 = (salp1a + salp1b) / 2
+				calp1 = (calp1a + calp1b) / 2
+				salp1, calp1 = norm2(salp1, calp1)
+				tripn = false
+				tripb = math.Abs(salp1a-salp1)+(calp1a-calp1) < geodesicTolb ||
+					math.Abs(salp1-salp1b)+(calp1-calp1b) < geodesicTolb
+			}
+			s12x, m12x, _, M12, M21 = g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2,
+				cbet1, cbet2, Ca[:])
+			m12x *= g.b
+			s12x *= g.b
+			a12 = sig12 / degree
+		}
+	}
+
+	// Convert -0 to 0
+	s12 = 0 + s12x
+	m12 = 0 + m12x
+
+	if swapp < 0 {
+		salp1, salp2 = salp2, salp1
+		calp1, calp2 = calp2, calp1
+		M12, M21 = M21, M12
+	}
+	salp1 *= swapp * lonsign
+	calp1 *= swapp * latsign
+	salp2 *= swapp * lonsign
+	calp2 *= swapp * latsign
+
+	azi1 = atan2d(salp1, calp1)
+	azi2 = atan2d(salp2, calp2)
+	return a12, s12, azi1, azi2, m12, M12, M21
+}
+
+// geodesicLine is a geodesic starting at a point with a given azimuth, which
+// can be followed for any distance to solve the direct geodesic problem.
+type geodesicLine struct {
+	lat1, lon1, azi1                float64
+	a, f, b, f1                     float64
+	salp0, calp0, k2                float64
+	salp1, calp1, ssig1, csig1, dn1 float64
+	stau1, ctau1, somg1, comg1      float64
+	a1m1, a2m1, a3c, b11, b21, b31  float64
+	c1a                             [geodesicNC1 + 1]float64
+	c1pa                            [geodesicNC1p + 1]float64
+	c2a                             [geodesicNC2 + 1]float64
+	c3a                             [geodesicNC3]float64
+}
+
+// line returns the geodesic starting at the given point and azimuth.
+func (g *geodesic) line(lat1, lon1, azi1 float64) *geodesicLine {
+	l := &geodesicLine{a: g.a, f: g.f, b: g.b, f1: g.f1}
+	azi1 = angNormalize(azi1)
+	l.lat1 = latFix(lat1)
+	l.lon1 = lon1
+	l.azi1 = azi1
+	// Guard against underflow in salp0
+	l.salp1, l.calp1 = sincosd(angRound(azi1))
+
+	sbet1, cbet1 := sincosd(angRound(l.lat1))
+	sbet1 *= l.f1
+	// Ensure cbet1 = +epsilon at poles
+	sbet1, cbet1 = norm2(sbet1, cbet1)
+	cbet1 = math.Max(geodesicTiny, cbet1)
+	l.dn1 = math.Sqrt(1 + g.ep2*sbet1*sbet1)
+
+	// Evaluate alp0 from sin(alp1) * cos(bet1) = sin(alp0),
+	l.salp0 = l.salp1 * cbet1 // alp0 in [0, pi/2 - |bet1|]
+	// Alt: calp0 = hypot(sbet1, calp1 * cbet1).  The following is slightly
+	// better (consider the case salp1 = 0).
+	l.calp0 = math.Hypot(l.calp1, l.salp1*sbet1)
+	// Evaluate sig with tan(bet1) = tan(sig1) * cos(alp1).
+	// sig = 0 is nearest northward crossing of equator.
+	// With bet1 = 0, alp1 = pi/2, we have sig1 = 0 (equatorial line).
+	// With bet1 =  pi/2, alp1 = -pi, sig1 =  pi/2
+	// With bet1 = -pi/2, alp1 =  0 , sig1 = -pi/2
+	// Evaluate omg1 with tan(omg1) = sin(alp0) * tan(sig1).
+	// With alp0 in (0, pi/2], quadrants for sig and omg coincide.
+	// No atan2(0,0) ambiguity at poles since cbet1 = +epsilon.
+	// With alp0 = 0, omg1 = 0 for alp1 = 0, omg1 = pi for alp1 = pi.
+	l.ssig1 = sbet1
+	l.somg1 = l.salp0 * sbet1
+	if sbet1 != 0 || l.calp1 != 0 {
+		l.csig1 = cbet1 * l.calp1
+	} else {
+		l.csig1 = 1
+	}
+	l.comg1 = l.csig1
+	l.ssig1, l.csig1 = norm2(l.ssig1, l.csig1) // sig1 in (-pi, pi]
+
+	l.k2 = l.calp0 * l.calp0 * g.ep2
+	eps := l.k2 / (2*(1+math.Sqrt(1+l.k2)) + l.k2)
+
+	l.a1m1 = a1m1f(eps)
+	c1f(eps, l.c1a[:])
+	l.b11 = sinCosSeries(true, l.ssig1, l.csig1, l.c1a[:], geodesicNC1)
+	s, c := math.Sincos(l.b11)
+	// tau1 = sig1 + B11
+	l.stau1 = l.ssig1*c + l.csig1*s
+	l.ctau1 = l.csig1*c - l.ssig1*s
+	// Not necessary because c1pa reverts c1a
+	//    B11 = -sinCosSeries(true, stau1, ctau1, c1pa, nC1p)
+
+	c1pf(eps, l.c1pa[:])
+
+	l.a2m1 = a2m1f(eps)
+	c2f(eps, l.c2a[:])
+	l.b21 = sinCosSeries(true, l.ssig1, l.csig1, l.c2a[:], geodesicNC2)
+
+	g.c3f(eps, l.c3a[:])
+	l.a3c = -l.f * l.salp0 * g.a3f(eps)
+	l.b31 = sinCosSeries(true, l.ssig1, l.csig1, l.c3a[:], geodesicNC3-1)
+	return l
+}
+
+// position returns the point at the given distance along the geodesic, the
+// azimuth there, the reduced length and the geodesic scales.
+func (l *geodesicLine) position(s12 float64) (lat2, lon2, azi2, m12, M12, M21 float64) {
+	// Interpret s12 as distance
+	tau12 := s12 / (l.b * (1 + l.a1m1))
+	s, c := math.Sincos(tau12)
+	// tau2 = tau1 + tau12
+	B12 := -sinCosSeries(true, l.stau1*c+l.ctau1*s, l.ctau1*c-l.stau1*s, l.c1pa[:], geodesicNC1p)
+	sig12 := tau12 - (B12 - l.b11)
+	ssig12, csig12 := math.Sincos(sig12)
+	if math.Abs(l.f) > 0.01 {
+		// Reverted distance series is inaccurate for |f| > 1/100, so correct
+		// sig12 with 1 Newton iteration.
+		ssig2 := l.ssig1*csig12 + l.csig1*ssig12
+		csig2 := l.csig1*csig12 - l.ssig1*ssig12
+		B12 = sinCosSeries(true, ssig2, csig2, l.c1a[:], geodesicNC1)
+		serr := (1+l.a1m1)*(sig12+(B12-l.b11)) - s12/l.b
+		sig12 = sig12 - serr/math.Sqrt(1+l.k2*ssig2*ssig2)
+		ssig12, csig12 = math.Sincos(sig12)
+		// Update B12 below
+	}
+
+	// sig2 = sig1 + sig12
+	ssig2 := l.ssig1*csig12 + l.csig1*ssig12
+	csig2 := l.csig1*csig12 - l.ssig1*ssig12
+	dn2 := math.Sqrt(1 + l.k2*ssig2*ssig2)
+	if math.Abs(l.f) > 0.01 {
+		B12 = sinCosSeries(true, ssig2, csig2, l.c1a[:], geodesicNC1)
+	}
+	AB1 := (1 + l.a1m1) * (B12 - l.b11)
+	// sin(bet2) = cos(alp0) * sin(sig2)
+	sbet2 := l.calp0 * ssig2
+	// Alt: cbet2 = hypot(csig2, salp0 * ssig2)
+	cbet2 := math.Hypot(l.salp0, l.calp0*csig2)
+	if cbet2 == 0 {
+		// I.e., salp0 = 0, csig2 = 0.  Break the degeneracy in this case
+		cbet2 = geodesicTiny
+		csig2 = geodesicTiny
+	}
+	// tan(alp0) = cos(sig2)*tan(alp2)
+	salp2 := l.salp0
+	calp2 := l.calp0 * csig2 // No need to normalize
+
+	// tan(omg2) = sin(alp0) * tan(sig2)
+	somg2 := l.salp0 * ssig2
+	comg2 := csig2 // No need to normalize
+	// omg12 = omg2 - omg1
+	omg12 := math.Atan2(somg2*l.comg1-comg2*l.somg1, comg2*l.comg1+somg2*l.somg1)
+	lam12 := omg12 + l.a3c*(sig12+(sinCosSeries(true, ssig2, csig2, l.c3a[:], geodesicNC3-1)-l.b31))
+	lon12 := lam12 / degree
+	lon2 = angNormalize(angNormalize(l.lon1) + angNormalize(lon12))
+	lat2 = atan2d(sbet2, l.f1*cbet2)
+	azi2 = atan2d(salp2, calp2)
+
+	B22 := sinCosSeries(true, ssig2, csig2, l.c2a[:], geodesicNC2)
+	AB2 := (1 + l.a2m1) * (B22 - l.b21)
+	J12 := (l.a1m1-l.a2m1)*sig12 + (AB1 - AB2)
+	// Add parens around (csig1 * ssig2) and (ssig1 * csig2) to ensure
+	// accurate cancellation in the case of coincident points.
+	m12 = l.b * ((dn2*(l.csig1*ssig2) - l.dn1*(l.ssig1*csig2)) - l.csig1*csig2*J12)
+	t := l.k2 * (ssig2 - l.ssig1) * (ssig2 + l.ssig1) / (l.dn1 + dn2)
+	M12 = csig12 + (t*ssig2-csig2*J12)*l.ssig1/l.dn1
+	M21 = csig12 - (t*l.ssig1-l.csig1*J12)*ssig2/dn2
+	return lat2, lon2, azi2, m12, M12, M21
+}
+
+// direct solves the direct geodesic problem, returning the point at the given
+// distance and azimuth from the first point, the azimuth there, the reduced
+// length and the geodesic scales.
+func (g *geodesic) direct(lat1, lon1, azi1, s12 float64) (lat2, lon2, azi2, m12, M12, M21 float64) {
+	return g.line(lat1, lon1, azi1).position(s12)
+}
+
+// lengths returns the distance, reduced length, m0 and geodesic scales of a
+// geodesic, all scaled by b.
+func (g *geodesic) lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2,
+	cbet1, cbet2 float64, Ca []float64) (s12b, m12b, m0, M12, M21 float64) {
+	var Cb [geodesicNC]float64
+
+	A1 := a1m1f(eps)
+	c1f(eps, Ca)
+	A2 := a2m1f(eps)
+	c2f(eps, Cb[:])
+	m0 = A1 - A2
+	A2 = 1 + A2
+	A1 = 1 + A1
+
+	B1 := sinCosSeries(true, ssig2, csig2, Ca, geodesicNC1) -
+		sinCosSeries(true, ssig1, csig1, Ca, geodesicNC1)
+	// Missing a factor of b
+	s12b = A1 * (sig12 + B1)
+	B2 := sinCosSeries(true, ssig2, csig2, Cb[:], geodesicNC2) -
+		sinCosSeries(true, ssig1, csig1, Cb[:], geodesicNC2)
+	J12 := m0*sig12 + (A1*B1 - A2*B2)
+
+	// Missing a factor of b.
+	// Add parens around (csig1 * ssig2) and (ssig1 * csig2) to ensure
+	// accurate cancellation in the case of coincident points.
+	m12b = dn2*(csig1*ssig2) - dn1*(ssig1*csig2) - csig1*csig2*J12
+	csig12 := csig1*csig2 + ssig1*ssig2
+	t := g.ep2 * (cbet1 - cbet2) * (cbet1 + cbet2) / (dn1 + dn2)
+	M12 = csig12 + (t*ssig2-csig2*J12)*ssig1/dn1
+	M21 = csig12 - (t*ssig1-csig1*J12)*ssig2/dn2
+	return s12b, m12b, m0, M12, M21
+}
+
+// inverseStart returns a starting point for Newton's method in salp1 and
+// calp1.  If the geodesic is short enough to be solved directly, sig12 is
+// non-negative and salp2, calp2 and dnm are set too.
+func (g *geodesic) inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2,
+	lam12, slam12, clam12 float64, Ca []float64) (sig12, salp1, calp1, salp2, calp2, dnm float64) {
+	// Return a starting point for Newton's method in salp1 and calp1
+	// (function value is -1).  If Newton's method doesn't need to be used,
+	// return also salp2 and calp2 and function value is sig12.
+	sig12 = -1 // Return value
+	// bet12 = bet2 - bet1 in [0, pi); bet12a = bet2 + bet1 in (-pi, 0]
+	sbet12 := sbet2*cbet1 - cbet2*sbet1
+	cbet12 := cbet2*cbet1 + sbet2*sbet1
+	sbet12a := sbet2*cbet1 + cbet2*sbet1
+	shortline := cbet12 >= 0 && sbet12 < 0.5 && cbet2*lam12 < 0.5
+	var somg12, comg12 float64
+	if shortline {
+		sbetm2 := (sbet1 + sbet2) * (sbet1 + sbet2)
+		// sin((bet1+bet2)/2)^2
+		// =  (sbet1 + sbet2)^2 / ((sbet1 + sbet2)^2 + (cbet1 + cbet2)^2)
+		sbetm2 /= sbetm2 + (cbet1+cbet2)*(cbet1+cbet2)
+		dnm = math.Sqrt(1 + g.ep2*sbetm2)
+		omg12 := lam12 / (g.f1 * dnm)
+		somg12, comg12 = math.Sincos(omg12)
+	} else {
+		somg12, comg12 = slam12, clam12
+	}
+
+	salp1 = cbet2 * somg12
+	if comg12 >= 0 {
+		calp1 = sbet12 + cbet2*sbet1*somg12*somg12/(1+comg12)
+	} else {
+		calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+	}
+
+	ssig12 := math.Hypot(salp1, calp1)
+	csig12 := sbet1*sbet2 + cbet1*cbet2*comg12
+
+	if shortline && ssig12 < g.etol2 {
+		// really short lines
+		salp2 = cbet1 * somg12
+		if comg12 >= 0 {
+			calp2 = sbet12 - cbet1*sbet2*(somg12*somg12/(1+comg12))
+		} else {
+			calp2 = sbet12 - cbet1*sbet2*(1-comg12)
+		}
+		salp2, calp2 = norm2(salp2, calp2)
+		// Set return value
+		sig12 = math.Atan2(ssig12, csig12)
+	} else if math.Abs(g.n) > 0.1 || // No astroid calc if too eccentric
+		csig12 >= 0 ||
+		ssig12 >= 6*math.Abs(g.n)*math.Pi*cbet1*cbet1 {
+		// Nothing to do, zeroth order spherical approximation is OK
+	} else {
+		// Scale lam12 and bet2 to x, y coordinate system where antipodal
+		// point is at origin and singular point is at y = 0, x = -1.
+		lam12x := math.Atan2(-slam12, -clam12) // lam12 - pi
+		k2 := sbet1 * sbet1 * g.ep2
+		eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+		lamscale := g.f * cbet1 * g.a3f(eps) * math.Pi
+		betscale := lamscale * cbet1
+		x := lam12x / lamscale
+		y := sbet12a / betscale
+
+		if y > -geodesicTol1 && x > -1-geodesicXthresh {
+			salp1 = math.Min(1, -x)
+			calp1 = -math.Sqrt(1 - salp1*salp1)
+		} else {
+			// Estimate alp1, by solving the astroid problem.  It's better
+			// to estimate omg12 from the astroid and use the spherical
+			// formula to compute alp1, since that reduces the number of
+			// Newton iterations.  Because omg12 is near pi, work with omg12a
+			// = pi - omg12.
+			k := astroid(x, y)
+			omg12a := lamscale * (-x * k / (1 + k))
+			somg12, comg12 = math.Sincos(omg12a)
+			comg12 = -comg12
+			// Update spherical estimate of alp1 using omg12 instead of lam12
+			salp1 = cbet2 * somg12
+			calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+		}
+	}
+	// Sanity check on starting guess.  Backwards check allows NaN through.
+	if !(salp1 <= 0) {
+		salp1, calp1 = norm2(salp1, calp1)
+	} else {
+		salp1 = 1
+		calp1 = 0
+	}
+	return sig12, salp1, calp1, salp2, calp2, dnm
+}
+
+// lambda12 returns the difference between the longitude difference of the
+// geodesic leaving point 1 with azimuth alp1 and the target, and its
+// derivative with respect to alp1 if diffp is set.
+func (g *geodesic) lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1,
+	slam120, clam120 float64, diffp bool, Ca []float64) (
+	lam12, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, dlam12 float64) {
+	if sbet1 == 0 && calp1 == 0 {
+		// Break degeneracy of equatorial line.  This case has already been
+		// handled.
+		calp1 = -geodesicTiny
+	}
+
+	// sin(alp1) * cos(bet1) = sin(alp0)
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1) // calp0 > 0
+
+	// tan(bet1) = tan(sig1) * cos(alp1)
+	// tan(omg1) = sin(alp0) * tan(sig1) = tan(omg1)=tan(alp1)*sin(bet1)
+	ssig1 = sbet1
+	somg1 := salp0 * sbet1
+	csig1 = calp1 * cbet1
+	comg1 := csig1
+	ssig1, csig1 = norm2(ssig1, csig1)
+	// norm2(somg1, comg1); -- don't need to normalize!
+
+	// Enforce symmetries in the case abs(bet2) = -bet1.  Need to be careful
+	// about this case, since this can yield singularities in the Newton
+	// iteration.
+	// sin(alp2) * cos(bet2) = sin(alp0)
+	if cbet2 != cbet1 {
+		salp2 = salp0 / cbet2
+	} else {
+		salp2 = salp1
+	}
+	// calp2 = sqrt(1 - sq(salp2))
+	//       = sqrt(sq(calp0) - sq(sbet2)) / cbet2
+	// and subst for calp0 and rearrange to give (choose positive sqrt
+	// to give alp2 in [0, pi/2]).
+	if cbet2 != cbet1 || math.Abs(sbet2) != -sbet1 {
+		var d float64
+		if cbet1 < -sbet1 {
+			d = (cbet2 - cbet1) * (cbet1 + cbet2)
+		} else {
+			d = (sbet1 - sbet2) * (sbet1 + sbet2)
+		}
+		calp2 = math.Sqrt(calp1*cbet1*calp1*cbet1+d) / cbet2
+	} else {
+		calp2 = math.Abs(calp1)
+	}
+	// tan(bet2) = tan(sig2) * cos(alp2)
+	// tan(omg2) = sin(alp0) * tan(sig2).
+	ssig2 = sbet2
+	somg2 := salp0 * sbet2
+	csig2 = calp2 * cbet2
+	comg2 := csig2
+	ssig2, csig2 = norm2(ssig2, csig2)
+	// norm2(somg2, comg2); -- don't need to normalize!
+
+	// sig12 = sig2 - sig1, limit to [0, pi]
+	sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2)+0, csig1*csig2+ssig1*ssig2)
+
+	// omg12 = omg2 - omg1, limit to [0, pi]
+	somg12 := math.Max(0, comg1*somg2-somg1*comg2) + 0
+	comg12 := comg1*comg2 + somg1*somg2
+	// eta = omg12 - lam120
+	eta := math.Atan2(somg12*clam120-comg12*slam120, comg12*clam120+somg12*slam120)
+	k2 := calp0 * calp0 * g.ep2
+	eps = k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+	g.c3f(eps, Ca)
+	B312 := sinCosSeries(true, ssig2, csig2, Ca, geodesicNC3-1) -
+		sinCosSeries(true, ssig1, csig1, Ca, geodesicNC3-1)
+	domg12 := -g.f * g.a3f(eps) * salp0 * (sig12 + B312)
+	lam12 = eta + domg12
+
+	if diffp {
+		if calp2 == 0 {
+			dlam12 = -2 * g.f1 * dn1 / sbet1
+		} else {
+			_, dlam12, _, _, _ = g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2,
+				cbet1, cbet2, Ca)
+			dlam12 *= g.f1 / (calp2 * cbet2)
+		}
+	}
+	return lam12, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, dlam12
+}
+
+// astroid solves k^4+2*k^3-(x^2+y^2-1)*k^2-2*y^2*k-y^2 = 0 for positive root
+// k.  This solution is adapted from Geocentric::Reverse.
+func astroid(x, y float64) float64 {
+	p := x * x
+	q := y * y
+	r := (p + q - 1) / 6
+	if q == 0 && r <= 0 {
+		// y = 0 with |x| <= 1.  Handle this case directly, for y small,
+		// positive root is k = abs(y)/sqrt(1-x^2).
+		return 0
+	}
+	// Avoid possible division by zero when r = 0 by multiplying equations
+	// for s and t by r^3 and r, resp.
+	S := p * q / 4 // S = r^3 * s
+	r2 := r * r
+	r3 := r * r2
+	// The discriminant of the quadratic equation for T3.  This is zero on
+	// the evolute curve p^(1/3)+q^(1/3) = 1
+	disc := S * (S + 2*r3)
+	u := r
+	if disc >= 0 {
+		T3 := S + r3
+		// Pick the sign on the sqrt to maximize abs(T3).  This minimizes loss
+		// of precision due to cancellation.  The result is unchanged because
+		// of the way the T is used in definition of u.
+		if T3 < 0 {
+			T3 -= math.Sqrt(disc)
+		} else {
+			T3 += math.Sqrt(disc) // T3 = (r * t)^3
+		}
+		// N.B. cbrt always returns the real root.  cbrt(-8) = -2.
+		T := math.Cbrt(T3) // T = r * t
+		// T can be zero; but then r2 / T -> 0.
+		u += T
+		if T != 0 {
+			u += r2 / T
+		}
+	} else {
+		// T is complex, but the way u is defined the result is real.
+		ang := math.Atan2(math.Sqrt(-disc), -(S + r3))
+		// There are three possible cube roots.  We choose the root which
+		// avoids cancellation.  Note that disc < 0 implies that r < 0.
+		u += 2 * r * math.Cos(ang/3)
+	}
+	v := math.Sqrt(u*u + q) // guaranteed positive
+	// Avoid loss of accuracy when u < 0.
+	var uv float64
+	if u < 0 {
+		uv = q / (v - u)
+	} else {
+		uv = u + v // u+v, guaranteed positive
+	}
+	w := (uv - q) / (2 * v) // positive?
+	// Rearrange expression for k to avoid loss of accuracy due to
+	// subtraction.  Division by 0 not possible because uv > 0, w >= 0.
+	return uv / (math.Sqrt(uv+w*w) + w) // guaranteed positive
+}
+
+// a3coeff computes the coefficients of A3 as polynomials in n.
+func (g *geodesic) a3coeff() {
+	coeff := [...]float64{
+		// A3, coeff of eps^5, polynomial in n of order 0
+		-3, 128,
+		// A3, coeff of eps^4, polynomial in n of order 1
+		-2, -3, 64,
+		// A3, coeff of eps^3, polynomial in n of order 2
+		-1, -3, -1, 16,
+		// A3, coeff of eps^2, polynomial in n of order 2
+		3, -1, -2, 8,
+		// A3, coeff of eps^1, polynomial in n of order 1
+		1, -1, 2,
+		// A3, coeff of eps^0, polynomial in n of order 0
+		1, 1,
+	}
+	o, k := 0, 0
+	for j := geodesicNA3 - 1; j >= 0; j-- { // coeff of eps^j
+		m := geodesicNA3 - j - 1 // order of polynomial in n
+		if j < m {
+			m = j
+		}
+		g.a3x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+		k++
+		o += m + 2
+	}
+}
+
+// c3coeff computes the coefficients of C3 as polynomials in n.
+func (g *geodesic) c3coeff() {
+	coeff := [...]float64{
+		// C3[1], coeff of eps^5, polynomial in n of order 0
+		3, 128,
+		// C3[1], coeff of eps^4, polynomial in n of order 1
+		2, 5, 128,
+		// C3[1], coeff of eps^3, polynomial in n of order 2
+		-1, 3, 3, 64,
+		// C3[1], coeff of eps^2, polynomial in n of order 2
+		-1, 0, 1, 8,
+		// C3[1], coeff of eps^1, polynomial in n of order 1
+		-1, 1, 4,
+		// C3[2], coeff of eps^5, polynomial in n of order 0
+		5, 256,
+		// C3[2], coeff of eps^4, polynomial in n of order 1
+		1, 3, 128,
+		// C3[2], coeff of eps^3, polynomial in n of order 2
+		-3, -2, 3, 64,
+		// C3[2], coeff of eps^2, polynomial in n of order 2
+		1, -3, 2, 32,
+		// C3[3], coeff of eps^5, polynomial in n of order 0
+		7, 512,
+		// C3[3], coeff of eps^4, polynomial in n of order 1
+		-10, 9, 384,
+		// C3[3], coeff of eps^3, polynomial in n of order 2
+		5, -9, 5, 192,
+		// C3[4], coeff of eps^5, polynomial in n of order 0
+		7, 512,
+		// C3[4], coeff of eps^4, polynomial in n of order 1
+		-14, 7, 512,
+		// C3[5], coeff of eps^5, polynomial in n of order 0
+		21, 2560,
+	}
+	o, k := 0, 0
+	for l := 1; l < geodesicNC3; l++ { // l is index of C3[l]
+		for j := geodesicNC3 - 1; j >= l; j-- { // coeff of eps^j
+			m := geodesicNC3 - j - 1 // order of polynomial in n
+			if j < m {
+				m = j
+			}
+			g.c3x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
+
+// a3f evaluates A3.
+func (g *geodesic) a3f(eps float64) float64 {
+	// Evaluate A3
+	return polyval(geodesicNA3-1, g.a3x[:], eps)
+}
+
+// c3f evaluates C3 coeffs.  Elements c[1] through c[nC3 - 1] are set.
+func (g *geodesic) c3f(eps float64, c []float64) {
+	mult := 1.0
+	o := 0
+	for l := 1; l < geodesicNC3; l++ { // l is index of C3[l]
+		m := geodesicNC3 - l - 1 // order of polynomial in eps
+		mult *= eps
+		c[l] = mult * polyval(m, g.c3x[o:], eps)
+		o += m + 1
+	}
+}
+
+// a1m1f is the scale factor A1-1 = mean value of (d/dsigma)I1 - 1.
+func a1m1f(eps float64) float64 {
+	coeff := [...]float64{
+		// (1-eps)*A1-1, polynomial in eps2 of order 3
+		1, 4, 64, 0, 256,
+	}
+	m := geodesicNA1 / 2
+	t := polyval(m, coeff[:], eps*eps) / coeff[m+1]
+	return (t + eps) / (1 - eps)
+}
+
+// c1f evaluates the coefficients C1[l] in the Fourier expansion of B1.
+func c1f(eps float64, c []float64) {
+	coeff := [...]float64{
+		// C1[1]/eps^1, polynomial in eps2 of order 2
+		-1, 6, -16, 32,
+		// C1[2]/eps^2, polynomial in eps2 of order 2
+		-9, 64, -128, 2048,
+		// C1[3]/eps^3, polynomial in eps2 of order 1
+		9, -16, 768,
+		// C1[4]/eps^4, polynomial in eps2 of order 1
+		3, -5, 512,
+		// C1[5]/eps^5, polynomial in eps2 of order 0
+		-7, 1280,
+		// C1[6]/eps^6, polynomial in eps2 of order 0
+		-7, 2048,
+	}
+	eps2 := eps * eps
+	d := eps
+	o := 0
+	for l := 1; l <= geodesicNC1; l++ { // l is index of C1p[l]
+		m := (geodesicNC1 - l) / 2 // order of polynomial in eps^2
+		c[l] = d * polyval(m, coeff[o:], eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+// c1pf evaluates the coefficients C1p[l] in the Fourier expansion of B1p.
+func c1pf(eps float64, c []float64) {
+	coeff := [...]float64{
+		// C1p[1]/eps^1, polynomial in eps2 of order 2
+		205, -432, 768, 1536,
+		// C1p[2]/eps^2, polynomial in eps2 of order 2
+		4005, -4736, 3840, 12288,
+		// C1p[3]/eps^3, polynomial in eps2 of order 1
+		-225, 116, 384,
+		// C1p[4]/eps^4, polynomial in eps2 of order 1
+		-7173, 2695, 7680,
+		// C1p[5]/eps^5, polynomial in eps2 of order 0
+		3467, 7680,
+		// C1p[6]/eps^6, polynomial in eps2 of order 0
+		38081, 61440,
+	}
+	eps2 := eps * eps
+	d := eps
+	o := 0
+	for l := 1; l <= geodesicNC1p; l++ { // l is index of C1p[l]
+		m := (geodesicNC1p - l) / 2 // order of polynomial in eps^2
+		c[l] = d * polyval(m, coeff[o:], eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+// a2m1f is the scale factor A2-1 = mean value of (d/dsigma)I2 - 1.
+func a2m1f(eps float64) float64 {
+	coeff := [...]float64{
+		// (eps+1)*A2-1, polynomial in eps2 of order 3
+		-11, -28, -192, 0, 256,
+	}
+	m := geodesicNA2 / 2
+	t := polyval(m, coeff[:], eps*eps) / coeff[m+1]
+	return (t - eps) / (1 + eps)
+}
+
+// c2f evaluates the coefficients C2[l] in the Fourier expansion of B2.
+func c2f(eps float64, c []float64) {
+	coeff := [...]float64{
+		// C2[1]/eps^1, polynomial in eps2 of order 2
+		1, 2, 16, 32,
+		// C2[2]/eps^2, polynomial in eps2 of order 2
+		35, 64, 384, 2048,
+		// C2[3]/eps^3, polynomial in eps2 of order 1
+		15, 80, 768,
+		// C2[4]/eps^4, polynomial in eps2 of order 1
+		7, 35, 512,
+		// C2[5]/eps^5, polynomial in eps2 of order 0
+		63, 1280,
+		// C2[6]/eps^6, polynomial in eps2 of order 0
+		77, 2048,
+	}
+	eps2 := eps * eps
+	d := eps
+	o := 0
+	for l := 1; l <= geodesicNC2; l++ { // l is index of C2[l]
+		m := (geodesicNC2 - l) / 2 // order of polynomial in eps^2
+		c[l] = d * polyval(m, coeff[o:], eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+// sinCosSeries evaluates
+//
+//	y = sinp ? sum(c[i] * sin( 2*i    * x), i, 1, n) :
+//	           sum(c[i] * cos((2*i+1) * x), i, 0, n-1)
+//
+// using Clenshaw summation.  N.B. c[0] is unused for sin series.
+func sinCosSeries(sinp bool, sinx, cosx float64, c []float64, n int) float64 {
+	// Point to one beyond last element
+	k := n
+	if sinp {
+		k++
+	}
+	ar := 2 * (cosx - sinx) * (cosx + sinx) // 2 * cos(2 * x)
+	var y0, y1 float64
+	if n&1 != 0 {
+		k--
+		y0 = c[k]
+	}
+	// Now n is even
+	for n /= 2; n > 0; n-- {
+		// Unroll loop x 2, so accumulators return to their original role
+		k--
+		y1 = ar*y0 - y1 + c[k]
+		k--
+		y0 = ar*y1 - y0 + c[k]
+	}
+	if sinp {
+		return 2 * sinx * cosx * y0 // sin(2 * x) * y0
+	}
+	return cosx * (y0 - y1) // cos(x) * (y0 - y1)
+}
+
+// polyval evaluates the polynomial of order n with coefficients p, highest
+// power first.
+func polyval(n int, p []float64, x float64) float64 {
+	if n < 0 {
+		return 0
+	}
+	y := p[0]
+	for i := 1; i <= n; i++ {
+		y = y*x + p[i]
+	}
+	return y
+}
+
+// sumx returns the sum of u and v and the error in the rounded sum.
+func sumx(u, v float64) (s, t float64) {
+	s = u + v
+	up := s - v
+	vpp := s - up
+	up -= u
+	vpp -= v
+	t = -(up + vpp)
+	// u + v =       s      + t
+	//       = round(u + v) + t
+	return s, t
+}
+
+// angNormalize reduces an angle in degrees to [-180, 180].
+func angNormalize(x float64) float64 {
+	y := math.Remainder(x, 360)
+	if math.Abs(y) == 180 {
+		return math.Copysign(180, x)
+	}
+	return y
+}
+
+// angDiff returns the exact difference y - x of two angles in degrees,
+// reduced to [-180, 180], and the error in the rounded difference.
+func angDiff(x, y float64) (d, e float64) {
+	d, t := sumx(math.Remainder(-x, 360), math.Remainder(y, 360))
+	d, t = sumx(math.Remainder(d, 360), t)
+	if d == 0 || math.Abs(d) == 180 {
+		// If t == 0, take sign from y - x
+		// else (t != 0, implies d = +/-180), d and t must have opposite signs
+		if t == 0 {
+			d = math.Copysign(d, y-x)
+		} else {
+			d = math.Copysign(d, -t)
+		}
+	}
+	return d, t
+}
+
+// angRound rounds tiny angles in degrees to zero, to avoid underflow in the
+// geodesic calculations.
+func angRound(x float64) float64 {
+	// The makes the smallest gap in x = 1/16 - nextafter(1/16, 0) = 1/2^57
+	// for reals = 0.7 pm on the earth if x is an angle in degrees.  (This is
+	// about 1000 times more resolution than we get with angles around 90
+	// degrees.)  We use this to avoid having to deal with near singular cases
+	// when x is non-zero but tiny (e.g., 1.0e-200).
+	const z = 1.0 / 16
+	y := math.Abs(x)
+	w := z - y
+	// The compiler mustn't "simplify" z - (z - y) to y
+	if w > 0 {
+		y = z - w
+	}
+	return math.Copysign(y, x)
+}
+
+// latFix returns NaN for latitudes outside [-90, 90] degrees.
+func latFix(x float64) float64 {
+	if math.Abs(x) > 90 {
+		return math.NaN()
+	}
+	return x
+}
+
+// sincosd returns the sine and cosine of an angle in degrees, exact for
+// multiples of 90 degrees.
+func sincosd(x float64) (sinx, cosx float64) {
+	// In order to minimize round-off errors, this function exactly reduces
+	// the argument to the range [-45, 45] before converting it to radians.
+	r := math.Mod(x, 360)
+	q := int(math.Round(r / 90)) // If r is NaN this returns garbage
+	r -= 90 * float64(q)
+	// now abs(r) <= 45
+	s, c := math.Sincos(r * degree)
+	switch q & 3 {
+	case 0:
+		sinx, cosx = s, c
+	case 1:
+		sinx, cosx = c, -s
+	case 2:
+		sinx, cosx = -s, -c
+	default: // case 3
+		sinx, cosx = -c, s
+	}
+	// http://www.open-std.org/jtc1/sc22/wg14/www/docs/n1950.pdf
+	cosx += 0
+	if sinx == 0 {
+		sinx = math.Copysign(sinx, x)
+	}
+	return sinx, cosx
+}
+
+// atan2d returns atan2(y, x) in degrees, exact for multiples of 45 degrees.
+func atan2d(y, x float64) float64 {
+	// In order to minimize round-off errors, this function rearranges the
+	// arguments so that result of atan2 is in the range [-pi/4, pi/4] before
+	// converting it to degrees and mapping the result to the correct
+	// quadrant.
+	q := 0
+	if math.Abs(y) > math.Abs(x) {
+		x, y = y, x
+		q = 2
+	}
+	if math.Signbit(x) {
+		x = -x
+		q++
+	}
+	// here x >= 0 and x >= abs(y), so angle is in [-pi/4, pi/4]
+	ang := math.Atan2(y, x) / degree
+	switch q {
+	case 1:
+		ang = math.Copysign(180, y) - ang
+	case 2:
+		ang = 90 - ang
+	case 3:
+		ang = -90 + ang
+	}
+	return ang
+}
+
+// norm2 normalizes a sine and cosine pair.
+func norm2(sinx, cosx float64) (float64, float64) {
+	r := math.Hypot(sinx, cosx)
+	return sinx / r, cosx / r
+}
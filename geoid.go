@@ -0,0 +1,307 @@
+package coordconv
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/golang/geo/s2"
+)
+
+// GeoidInterpolation selects how geoid undulations are interpolated between
+// grid points.
+type GeoidInterpolation byte
+
+// GeoidInterpolation constants
+const (
+	GeoidBilinear GeoidInterpolation = iota
+	GeoidBicubic
+)
+
+// Geoid is a grid of geoid undulations, the height of the geoid above the
+// ellipsoid, such as EGM96 or EGM2008.  It converts between ellipsoidal
+// heights and orthometric (mean sea level) heights.
+type Geoid struct {
+	north         float64 // Latitude of the first row in degrees
+	west          float64 // Longitude of the first column in degrees
+	latStep       float64 // Row spacing in degrees
+	lonStep       float64 // Column spacing in degrees
+	rows          int
+	columns       int
+	period        int       // Columns spanning 360 degrees, 0 if the grid isn't global
+	values        []float64 // Undulations in meters, by row from the north
+	interpolation GeoidInterpolation
+}
+
+// LoadGeoidGRD reads a geoid in the NGA ASCII .GRD format, e.g. the EGM96
+// 15 minute grid WW15MGH.GRD, from disk.
+func LoadGeoidGRD(filename string) (*Geoid, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadGeoidGRD(f)
+}
+
+// ReadGeoidGRD reads a geoid in the NGA ASCII .GRD format.  The header gives
+// the south, north, west and east bounds and the latitude and longitude
+// spacing in degrees, followed by the undulations by row from the north.
+func ReadGeoidGRD(r io.Reader) (*Geoid, error) {
+	sc := bufio.NewScanner(r)
+	sc.Split(bufio.ScanWords)
+	next := func() (float64, error) {
+		if !sc.Scan() {
+			if err := sc.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.ErrUnexpectedEOF
+		}
+		return strconv.ParseFloat(sc.Text(), 64)
+	}
+
+	var header [6]float64
+	for i := range header {
+		v, err := next()
+		if err != nil {
+			return nil, fmt.Errorf("error reading geoid header: %s", err)
+		}
+		header[i] = v
+	}
+	south, north, west, east, latStep, lonStep := header[0], header[1], header[2], header[3], header[4], header[5]
+	if latStep <= 0 || lonStep <= 0 || north <= south || east <= west {
+		return nil, errors.New("invalid geoid grid header")
+	}
+
+	g := &Geoid{
+		north:   north,
+		west:    west,
+		latStep: latStep,
+		lonStep: lonStep,
+		rows:    int(math.Round((north-south)/latStep)) + 1,
+		columns: int(math.Round((east-west)/lonStep)) + 1,
+	}
+	g.setPeriod()
+	g.values = make([]float64, g.rows*g.columns)
+	for i := range g.values {
+		v, err := next()
+		if err != nil {
+			return nil, fmt.Errorf("error reading geoid undulations: %s", err)
+		}
+		g.values[i] = v
+	}
+	return g, nil
+}
+
+// LoadGeoidPGM reads a geoid in the GeographicLib PGM format, e.g.
+// egm2008-2_5.pgm, from disk.
+func LoadGeoidPGM(filename string) (*Geoid, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadGeoidPGM(bufio.NewReader(f))
+}
+
+// ReadGeoidPGM reads a geoid in the GeographicLib PGM format, a global 16 bit
+// image with rows from the north pole to the south pole and columns eastward
+// from the prime meridian.  The undulation is Offset + Scale * pixel, taken
+// from the header comments.
+func ReadGeoidPGM(r io.Reader) (*Geoid, error) {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	offset, scale := math.NaN(), math.NaN()
+	var fields []int
+	token := []byte{}
+	for len(fields) < 4 {
+		b, err := br.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("error reading geoid header: %s", err)
+		}
+		switch {
+		case b == '#' && len(token) == 0:
+			comment, err := br.ReadString('\n')
+			if err != nil {
+				return nil, fmt.Errorf("error reading geoid header: %s", err)
+			}
+			parts := strings.Fields(comment)
+			if len(parts) == 2 {
+				v, err := strconv.ParseFloat(parts[1], 64)
+				if err == nil && parts[0] == "Offset" {
+					offset = v
+				} else if err == nil && parts[0] == "Scale" {
+					scale = v
+				}
+			}
+		case b == ' ' || b == '\t' || b == '\n' || b == '\r':
+			if len(token) == 0 {
+				continue
+			}
+			if len(fields) == 0 {
+				if string(token) != "P5" {
+					return nil, errors.New("not a binary PGM geoid file")
+				}
+				fields = append(fields, 0)
+			} else {
+				v, err := strconv.Atoi(string(token))
+				if err != nil {
+					return nil, fmt.Errorf("invalid geoid header: %s", err)
+				}
+				fields = append(fields, v)
+			}
+			token = token[:0]
+		default:
+			token = append(token, b)
+		}
+	}
+	width, height, maxValue := fields[1], fields[2], fields[3]
+	if math.IsNaN(offset) || math.IsNaN(scale) {
+		return nil, errors.New("geoid file missing offset or scale")
+	}
+	if width <= 0 || height <= 1 || maxValue != 65535 {
+		return nil, errors.New("invalid geoid grid size")
+	}
+
+	g := &Geoid{
+		north:   90,
+		west:    0,
+		latStep: 180 / float64(height-1),
+		lonStep: 360 / float64(width),
+		rows:    height,
+		columns: width,
+	}
+	g.setPeriod()
+	raw := make([]uint16, width*height)
+	if err := binary.Read(br, binary.BigEndian, raw); err != nil {
+		return nil, fmt.Errorf("error reading geoid undulations: %s", err)
+	}
+	g.values = make([]float64, len(raw))
+	for i, v := range raw {
+		g.values[i] = offset + scale*float64(v)
+	}
+	return g, nil
+}
+
+// setPeriod determines whether the grid wraps around in longitude.
+func (g *Geoid) setPeriod() {
+	period := int(math.Round(360 / g.lonStep))
+	if math.Abs(float64(period)*g.lonStep-360) < 1e-9 && g.columns >= period {
+		g.period = period
+	}
+}
+
+// SetInterpolation sets how undulations are interpolated between grid
+// points.  The default is bilinear.
+func (g *Geoid) SetInterpolation(interpolation GeoidInterpolation) {
+	g.interpolation = interpolation
+}
+
+// Undulation returns the height of the geoid above the ellipsoid in meters.
+func (g *Geoid) Undulation(geodeticCoordinates s2.LatLng) (float64, error) {
+	latitude := geodeticCoordinates.Lat.Degrees()
+	longitude := geodeticCoordinates.Lng.Degrees()
+	if (latitude < -90) || (latitude > 90) {
+		return 0, errors.New("latitude out of range")
+	}
+	if (longitude < -180) || (longitude > 360) {
+		return 0, errors.New("longitude out of range")
+	}
+
+	y := (g.north - latitude) / g.latStep
+	var x float64
+	if g.period != 0 {
+		x = math.Mod(longitude-g.west, 360)
+		if x < 0 {
+			x += 360
+		}
+		x /= g.lonStep
+	} else {
+		x = (longitude - g.west) / g.lonStep
+		if x < 0 {
+			x = (longitude + 360 - g.west) / g.lonStep
+		}
+	}
+	if y < 0 || y > float64(g.rows-1) || (g.period == 0 && (x < 0 || x > float64(g.columns-1))) {
+		return 0, errors.New("point outside of geoid grid")
+	}
+
+	row := int(math.Min(math.Floor(y), float64(g.rows-2)))
+	column := int(math.Floor(x))
+	if g.period == 0 && column > g.columns-2 {
+		column = g.columns - 2
+	}
+	fy := y - float64(row)
+	fx := x - float64(column)
+
+	if g.interpolation == GeoidBicubic {
+		var v [4]float64
+		for i := range v {
+			v[i] = cubicConvolution(fx,
+				g.value(row+i-1, column-1), g.value(row+i-1, column),
+				g.value(row+i-1, column+1), g.value(row+i-1, column+2))
+		}
+		return cubicConvolution(fy, v[0], v[1], v[2], v[3]), nil
+	}
+	v00 := g.value(row, column)
+	v01 := g.value(row, column+1)
+	v10 := g.value(row+1, column)
+	v11 := g.value(row+1, column+1)
+	return v00*(1-fx)*(1-fy) + v01*fx*(1-fy) + v10*(1-fx)*fy + v11*fx*fy, nil
+}
+
+// OrthometricHeight converts a height above the ellipsoid to a height above
+// the geoid (mean sea level).
+func (g *Geoid) OrthometricHeight(geodeticCoordinates s2.LatLng, ellipsoidHeight float64) (float64, error) {
+	n, err := g.Undulation(geodeticCoordinates)
+	if err != nil {
+		return 0, err
+	}
+	return ellipsoidHeight - n, nil
+}
+
+// EllipsoidHeight converts a height above the geoid (mean sea level) to a
+// height above the ellipsoid.
+func (g *Geoid) EllipsoidHeight(geodeticCoordinates s2.LatLng, orthometricHeight float64) (float64, error) {
+	n, err := g.Undulation(geodeticCoordinates)
+	if err != nil {
+		return 0, err
+	}
+	return orthometricHeight + n, nil
+}
+
+// value returns the grid value, wrapping columns around the globe and
+// clamping rows and columns at the edges of the grid.
+func (g *Geoid) value(row, column int) float64 {
+	if row < 0 {
+		row = 0
+	} else if row > g.rows-1 {
+		row = g.rows - 1
+	}
+	if g.period != 0 {
+		column %= g.period
+		if column < 0 {
+			column += g.period
+		}
+	} else if column < 0 {
+		column = 0
+	} else if column > g.columns-1 {
+		column = g.columns - 1
+	}
+	return g.values[row*g.columns+column]
+}
+
+// cubicConvolution interpolates between v1 and v2 at t in [0, 1] using the
+// Catmull-Rom cubic through the four points.
+func cubicConvolution(t, v0, v1, v2, v3 float64) float64 {
+	return v1 + 0.5*t*(v2-v0+t*(2*v0-5*v1+4*v2-v3+t*(3*(v1-v2)+v3-v0)))
+}
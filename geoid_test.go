@@ -0,0 +1,176 @@
+package coordconv_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+// testUndulation is linear away from the prime meridian, so both bilinear
+// and bicubic interpolation reproduce it exactly there.
+func testUndulation(lat, lng float64) float64 {
+	return -20 + 0.25*lat + 0.1*lng
+}
+
+func writeTestGeoidGRD(t *testing.T) string {
+	t.Helper()
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%12.6f%12.6f%12.6f%12.6f%12.6f%12.6f\n", -90.0, 90.0, 0.0, 360.0, 5.0, 5.0)
+	n := 0
+	for lat := 90.0; lat >= -90; lat -= 5 {
+		for lng := 0.0; lng <= 360; lng += 5 {
+			fmt.Fprintf(&buf, "%10.3f", testUndulation(lat, math.Mod(lng, 360)))
+			n++
+			if n%8 == 0 {
+				buf.WriteString("\n")
+			}
+		}
+	}
+	filename := filepath.Join(t.TempDir(), "test.grd")
+	if err := os.WriteFile(filename, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("error writing geoid file: %s", err)
+	}
+	return filename
+}
+
+func writeTestGeoidPGM(t *testing.T) string {
+	t.Helper()
+	const offset, scale = -108.0, 0.003
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "P5\n# Geoid file in PGM format for testing\n# Offset %f\n# Scale %f\n%d %d\n65535\n",
+		offset, scale, 72, 37)
+	for lat := 90.0; lat >= -90; lat -= 5 {
+		for lng := 0.0; lng < 360; lng += 5 {
+			raw := uint16(math.Round((testUndulation(lat, lng) - offset) / scale))
+			binary.Write(&buf, binary.BigEndian, raw)
+		}
+	}
+	filename := filepath.Join(t.TempDir(), "test.pgm")
+	if err := os.WriteFile(filename, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("error writing geoid file: %s", err)
+	}
+	return filename
+}
+
+func TestGeoid(t *testing.T) {
+	grd, err := coordconv.LoadGeoidGRD(writeTestGeoidGRD(t))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	pgm, err := coordconv.LoadGeoidPGM(writeTestGeoidPGM(t))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	testCases := []struct {
+		name      string
+		geoid     *coordconv.Geoid
+		tolerance float64
+	}{
+		{"grd", grd, 1e-9},
+		// the PGM format quantizes undulations
+		{"pgm", pgm, 0.002},
+	}
+	for _, tc := range testCases {
+		for _, interpolation := range []coordconv.GeoidInterpolation{coordconv.GeoidBilinear, coordconv.GeoidBicubic} {
+			tc.geoid.SetInterpolation(interpolation)
+			for _, pt := range [][2]float64{{0, 100}, {12.3, 145.6}, {-47.1, 201.9}, {61.25, 330.2}, {-80, -170}} {
+				lng := pt[1]
+				if lng < 0 {
+					lng += 360
+				}
+				expected := testUndulation(pt[0], lng)
+				geo := s2.LatLngFromDegrees(pt[0], pt[1])
+				n, err := tc.geoid.Undulation(geo)
+				if err != nil {
+					t.Fatalf("%s: expected no error, got %s", tc.name, err)
+				}
+				if math.Abs(n-expected) > tc.tolerance {
+					t.Errorf("%s %d: at %s expected %f, got %f", tc.name, interpolation, geo, expected, n)
+				}
+			}
+		}
+		tc.geoid.SetInterpolation(coordconv.GeoidBilinear)
+
+		// the grid wraps around the prime meridian
+		west, err := tc.geoid.Undulation(s2.LatLngFromDegrees(30, -2.5))
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		expected := (testUndulation(30, 355) + testUndulation(30, 0)) / 2
+		if math.Abs(west-expected) > tc.tolerance {
+			t.Errorf("%s: expected %f, got %f", tc.name, expected, west)
+		}
+		for _, lat := range []float64{90, -90} {
+			if _, err := tc.geoid.Undulation(s2.LatLngFromDegrees(lat, 10)); err != nil {
+				t.Errorf("%s: expected no error at the pole, got %s", tc.name, err)
+			}
+		}
+	}
+}
+
+func TestGeoidHeights(t *testing.T) {
+	g, err := coordconv.LoadGeoidGRD(writeTestGeoidGRD(t))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	geo, err := coordconv.DefaultMGRSConverter.ConvertToGeodetic("16SGC3855124838")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	n, err := g.Undulation(geo)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	msl, err := g.OrthometricHeight(geo, 250)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(msl-(250-n)) > 1e-9 {
+		t.Errorf("expected %f, got %f", 250-n, msl)
+	}
+	h, err := g.EllipsoidHeight(geo, msl)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(h-250) > 1e-9 {
+		t.Errorf("expected %f, got %f", 250.0, h)
+	}
+}
+
+func TestGeoidInvalid(t *testing.T) {
+	if _, err := coordconv.ReadGeoidGRD(bytes.NewReader([]byte("-90 90 0 360 5"))); err == nil {
+		t.Errorf("expected error for short header")
+	}
+	if _, err := coordconv.ReadGeoidGRD(bytes.NewReader([]byte("-90 90 0 360 5 5 1 2 3"))); err == nil {
+		t.Errorf("expected error for missing undulations")
+	}
+	if _, err := coordconv.ReadGeoidPGM(bytes.NewReader([]byte("P2\n2 2\n255\n"))); err == nil {
+		t.Errorf("expected error for ASCII PGM")
+	}
+	if _, err := coordconv.ReadGeoidPGM(bytes.NewReader([]byte("P5\n72 37\n65535\n"))); err == nil {
+		t.Errorf("expected error for missing offset and scale")
+	}
+
+	regional, err := coordconv.ReadGeoidGRD(bytes.NewReader([]byte("10 20 30 40 5 5\n1 2 3\n4 5 6\n7 8 9\n")))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	n, err := regional.Undulation(s2.LatLngFromDegrees(17.5, 32.5))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3, got %f", n)
+	}
+	if _, err := regional.Undulation(s2.LatLngFromDegrees(0, 35)); err == nil {
+		t.Errorf("expected error for point outside of grid")
+	}
+}
@@ -0,0 +1,156 @@
+package coordconv
+
+import (
+	"errors"
+	"math"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// Gnomonic provides conversions between geodetic coordinates (latitude and
+// longitude) and ellipsoidal Gnomonic projection coordinates (easting and
+// northing).  This is the generalization of the gnomonic projection due to
+// Karney, "Algorithms for geodesics", J. Geodesy 87, 43-55 (2013): geodesics
+// through the centre are straight lines, and other geodesics are very nearly
+// straight, so routes can be planned by drawing straight lines on the grid.
+// Only points less than about a quarter of a meridian from the centre can be
+// projected.
+type Gnomonic struct {
+	semiMajorAxis float64
+	flattening    float64
+	geodesic      *geodesic
+
+	// Gnomonic projection Parameters
+	gnomOriginLat     float64 // Latitude of the centre in radians
+	gnomOriginLong    float64 // Longitude of the centre in radians
+	gnomFalseEasting  float64 // False easting in meters
+	gnomFalseNorthing float64 // False northing in meters
+}
+
+// NewGnomonic constructs a Gnomonic converter centred on the given point.  The
+// false easting and northing are at the centre.
+func NewGnomonic(ellipsoidSemiMajorAxis, ellipsoidFlattening float64, center s2.LatLng,
+	falseEasting, falseNorthing float64) (*Gnomonic, error) {
+	if err := checkAzimuthalCenter(ellipsoidSemiMajorAxis, ellipsoidFlattening, center); err != nil {
+		return nil, err
+	}
+	return &Gnomonic{
+		semiMajorAxis:     ellipsoidSemiMajorAxis,
+		flattening:        ellipsoidFlattening,
+		geodesic:          newGeodesic(ellipsoidSemiMajorAxis, ellipsoidFlattening),
+		gnomOriginLat:     center.Lat.Radians(),
+		gnomOriginLong:    normalizeOriginLongitude(center.Lng.Radians()),
+		gnomFalseEasting:  falseEasting,
+		gnomFalseNorthing: falseNorthing,
+	}, nil
+}
+
+// NewGnomonicEllipsoid constructs a Gnomonic converter for the given
+// ellipsoid.
+func NewGnomonicEllipsoid(ellipsoid Ellipsoid, center s2.LatLng,
+	falseEasting, falseNorthing float64) (*Gnomonic, error) {
+	return NewGnomonic(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, center,
+		falseEasting, falseNorthing)
+}
+
+// NewGnomonicUTM constructs a Gnomonic converter centred on a UTM coordinate
+// on the given ellipsoid.
+func NewGnomonicUTM(ellipsoid Ellipsoid, center UTMCoord,
+	falseEasting, falseNorthing float64) (*Gnomonic, error) {
+	geodeticCenter, err := utmAzimuthalCenter(ellipsoid, center)
+	if err != nil {
+		return nil, err
+	}
+	return NewGnomonicEllipsoid(ellipsoid, geodeticCenter, falseEasting, falseNorthing)
+}
+
+// NewGnomonicMGRS constructs a Gnomonic converter centred on an MGRS
+// coordinate string on the given ellipsoid.
+func NewGnomonicMGRS(ellipsoid Ellipsoid, center string,
+	falseEasting, falseNorthing float64) (*Gnomonic, error) {
+	geodeticCenter, err := mgrsAzimuthalCenter(ellipsoid, center)
+	if err != nil {
+		return nil, err
+	}
+	return NewGnomonicEllipsoid(ellipsoid, geodeticCenter, falseEasting, falseNorthing)
+}
+
+// ConvertFromGeodetic converts geodetic coordinates (latitude and longitude) to
+// Gnomonic coordinates (easting and northing), according to the current
+// ellipsoid and Gnomonic projection parameters.
+func (g *Gnomonic) ConvertFromGeodetic(geodeticCoordinates s2.LatLng) (MapCoords, error) {
+	longitude := geodeticCoordinates.Lng.Radians()
+	latitude := geodeticCoordinates.Lat.Radians()
+
+	if (latitude < -math.Pi/2) || (latitude > math.Pi/2) {
+		return MapCoords{}, errors.New("latitude out of range")
+	}
+	if (longitude < -math.Pi) || (longitude > 2*math.Pi) {
+		return MapCoords{}, errors.New("longitude out of range")
+	}
+
+	_, _, azi1, _, m12, M12, _ := g.geodesic.inverse(g.gnomOriginLat/degree, g.gnomOriginLong/degree,
+		latitude/degree, longitude/degree)
+	if M12 <= 0 {
+		return MapCoords{}, errors.New("Point projects into infinity")
+	}
+	rho := m12 / M12
+	sinAzi, cosAzi := sincosd(azi1)
+	return MapCoords{
+		Easting:  g.gnomFalseEasting + rho*sinAzi,
+		Northing: g.gnomFalseNorthing + rho*cosAzi,
+	}, nil
+}
+
+// ConvertToGeodetic converts Gnomonic coordinates (easting and northing) to
+// geodetic coordinates (latitude and longitude) according to the current
+// ellipsoid and Gnomonic projection parameters.
+func (g *Gnomonic) ConvertToGeodetic(mapProjectionCoordinates MapCoords) (s2.LatLng, error) {
+	const maxIterations = 10
+	dx := mapProjectionCoordinates.Easting - g.gnomFalseEasting
+	dy := mapProjectionCoordinates.Northing - g.gnomFalseNorthing
+
+	rho := math.Hypot(dx, dy)
+	if math.IsNaN(rho) || math.IsInf(rho, 0) {
+		return s2.LatLng{}, errors.New("Point is outside of projection area")
+	}
+	a := g.semiMajorAxis
+	s12 := a * math.Atan(rho/a)
+	// Solve rho(s12) = rho near the centre, where drho/ds = 1/M^2, and
+	// 1/rho(s12) = 1/rho further out, where d(1/rho)/ds = -1/m^2.
+	little := rho <= a
+	if !little {
+		rho = 1 / rho
+	}
+	line := g.geodesic.line(g.gnomOriginLat/degree, g.gnomOriginLong/degree, atan2d(dx, dy))
+	for count := 0; count < maxIterations; count++ {
+		_, _, _, m12, M12, _ := line.position(s12)
+		var ds float64
+		if little {
+			ds = (m12 - rho*M12) * M12
+		} else {
+			ds = (rho*m12 - M12) * m12
+		}
+		s12 -= ds
+		// Reversed test to allow escape with NaNs
+		if !(math.Abs(ds) >= 0.01*math.Sqrt(epsilon64)*a) {
+			lat2, lon2, _, _, _, _ := line.position(s12)
+			return s2.LatLng{Lat: s1.Angle(lat2 * degree), Lng: s1.Angle(lon2 * degree)}, nil
+		}
+	}
+	return s2.LatLng{}, errors.New("Point is outside of projection area")
+}
+
+// Parameters returns the ellipsoid and Gnomonic projection parameters.
+func (g *Gnomonic) Parameters() ProjectionParameters {
+	return ProjectionParameters{
+		SemiMajorAxis:   g.semiMajorAxis,
+		Flattening:      g.flattening,
+		CentralMeridian: g.gnomOriginLong,
+		OriginLatitude:  g.gnomOriginLat,
+		FalseEasting:    g.gnomFalseEasting,
+		FalseNorthing:   g.gnomFalseNorthing,
+		ScaleFactor:     1.0,
+	}
+}
@@ -0,0 +1,106 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestGnomonicRoundTrip(t *testing.T) {
+	for _, center := range []s2.LatLng{
+		s2.LatLngFromDegrees(38.8895, -77.0352),
+		s2.LatLngFromDegrees(-33.9, 151.2),
+		s2.LatLngFromDegrees(0, 179),
+		s2.LatLngFromDegrees(-90, 0),
+	} {
+		gnom, err := coordconv.NewGnomonic(ellipsoidSemiMajorAxis, ellipsoidFlattening, center, 100, 200)
+		if err != nil {
+			t.Fatalf("error creating gnomonic converter: %s", err)
+		}
+		for lng := -180.0; lng < 180; lng += 10 {
+			for lat := -90.0; lat <= 90; lat += 10 {
+				geo := s2.LatLngFromDegrees(lat, lng)
+				mc, err := gnom.ConvertFromGeodetic(geo)
+				if geo.Distance(center).Degrees() > 90.5 {
+					if err == nil {
+						t.Errorf("%s: expected an error projecting %s", center, geo)
+					}
+					continue
+				}
+				if geo.Distance(center).Degrees() > 80 {
+					continue
+				}
+				if err != nil {
+					t.Fatalf("%s: expected no error at %s, got %s", center, geo, err)
+				}
+				geo2, err := gnom.ConvertToGeodetic(mc)
+				if err != nil {
+					t.Fatalf("%s: expected no error in round trip at %s, got %s", center, geo, err)
+				}
+				if math.Abs(lat) < 90 && geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > 1e-6 {
+					t.Errorf("%s: expected %s, got %s", center, geo, geo2)
+				}
+			}
+		}
+
+		mc, err := gnom.ConvertFromGeodetic(center)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", center, err)
+		}
+		if mc.Easting != 100 || mc.Northing != 200 {
+			t.Errorf("%s: expected the centre at the false easting and northing, got %v", center, mc)
+		}
+	}
+}
+
+func TestGnomonicGeodesics(t *testing.T) {
+	center := s2.LatLngFromDegrees(45, 10)
+	gnom, err := coordconv.NewGnomonic(ellipsoidSemiMajorAxis, ellipsoidFlattening, center, 0, 0)
+	if err != nil {
+		t.Fatalf("error creating gnomonic converter: %s", err)
+	}
+
+	// An azimuthal equidistant projection maps geodesics from its centre to
+	// straight lines, so use it to trace geodesics.
+	for _, tc := range []struct {
+		name      string
+		start     s2.LatLng
+		azimuth   float64 // degrees
+		tolerance float64 // meters from the chord
+	}{
+		{name: "through the centre", start: center, azimuth: 63, tolerance: 1e-6},
+		{name: "across the map", start: s2.LatLngFromDegrees(30, -10), azimuth: 50, tolerance: 10},
+	} {
+		azeq, err := coordconv.NewAzimuthalEquidistant(ellipsoidSemiMajorAxis, ellipsoidFlattening, tc.start, 0, 0)
+		if err != nil {
+			t.Fatalf("error creating azimuthal equidistant converter: %s", err)
+		}
+		var points []coordconv.MapCoords
+		sinAzi, cosAzi := math.Sincos(tc.azimuth * math.Pi / 180)
+		for s := 0.0; s <= 4e6; s += 5e5 {
+			geo, err := azeq.ConvertToGeodetic(coordconv.MapCoords{Easting: s * sinAzi, Northing: s * cosAzi})
+			if err != nil {
+				t.Fatalf("%s: expected no error, got %s", tc.name, err)
+			}
+			mc, err := gnom.ConvertFromGeodetic(geo)
+			if err != nil {
+				t.Fatalf("%s: expected no error, got %s", tc.name, err)
+			}
+			points = append(points, mc)
+		}
+		first := points[0]
+		last := points[len(points)-1]
+		chord := math.Hypot(last.Easting-first.Easting, last.Northing-first.Northing)
+		maxOffset := 0.0
+		for _, p := range points {
+			offset := math.Abs((last.Easting-first.Easting)*(p.Northing-first.Northing)-
+				(last.Northing-first.Northing)*(p.Easting-first.Easting)) / chord
+			maxOffset = math.Max(maxOffset, offset)
+		}
+		if maxOffset > tc.tolerance {
+			t.Errorf("%s: expected a straight line, got an offset of %f m", tc.name, maxOffset)
+		}
+	}
+}
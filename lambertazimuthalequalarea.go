@@ -0,0 +1,197 @@
+package coordconv
+
+import (
+	"errors"
+	"math"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// LambertAzimuthalEqualArea provides conversions between geodetic coordinates
+// (latitude and longitude) and Lambert Azimuthal Equal Area projection
+// coordinates (easting and northing).  The formulas are those of EPSG Guidance
+// Note 7-2, with the polar aspects from Snyder.
+type LambertAzimuthalEqualArea struct {
+	semiMajorAxis float64
+	flattening    float64
+	authalic      authalic
+
+	// Lambert Azimuthal Equal Area projection Parameters
+	laeaOriginLat     float64 // Latitude of origin in radians
+	laeaOriginLong    float64 // Longitude of origin in radians
+	laeaFalseEasting  float64 // False easting in meters
+	laeaFalseNorthing float64 // False northing in meters
+	laeaRq            float64 // Radius of the sphere with the area of the ellipsoid
+	laeaD             float64 // Snyder's D
+	laeaSinBeta0      float64 // Sine of the authalic latitude of origin
+	laeaCosBeta0      float64 // Cosine of the authalic latitude of origin
+	laeaPole          float64 // 1 or -1 for the polar aspects, 0 otherwise
+}
+
+// NewLambertAzimuthalEqualArea constructs a Lambert Azimuthal Equal Area
+// converter centred on the latitude and longitude of origin.
+func NewLambertAzimuthalEqualArea(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+	originLatitude, falseEasting, falseNorthing float64) (*LambertAzimuthalEqualArea, error) {
+	invF := 1 / ellipsoidFlattening
+	if ellipsoidSemiMajorAxis <= 0.0 {
+		return nil, errors.New("Semi-major axis must be greater than zero")
+	}
+	if (invF < 250) || (invF > 350) {
+		return nil, errors.New("Inverse flattening must be between 250 and 350")
+	}
+	if (originLatitude < -math.Pi/2) || (originLatitude > math.Pi/2) {
+		return nil, errors.New("Origin Latitude out of range")
+	}
+	if (centralMeridian < -math.Pi) || (centralMeridian > 2*math.Pi) {
+		return nil, errors.New("Origin Longitude out of range")
+	}
+
+	if centralMeridian > math.Pi {
+		centralMeridian -= 2 * math.Pi
+	}
+	l := &LambertAzimuthalEqualArea{
+		semiMajorAxis:     ellipsoidSemiMajorAxis,
+		flattening:        ellipsoidFlattening,
+		authalic:          newAuthalic(ellipsoidFlattening),
+		laeaOriginLat:     originLatitude,
+		laeaOriginLong:    centralMeridian,
+		laeaFalseEasting:  falseEasting,
+		laeaFalseNorthing: falseNorthing,
+	}
+
+	l.laeaRq = l.semiMajorAxis * math.Sqrt(l.authalic.qp/2)
+	switch originLatitude {
+	case math.Pi / 2:
+		l.laeaPole = 1
+	case -math.Pi / 2:
+		l.laeaPole = -1
+	default:
+		beta0 := l.authalic.beta(originLatitude)
+		l.laeaSinBeta0 = math.Sin(beta0)
+		l.laeaCosBeta0 = math.Cos(beta0)
+		l.laeaD = l.semiMajorAxis * l.authalic.m(originLatitude) / (l.laeaRq * l.laeaCosBeta0)
+	}
+	return l, nil
+}
+
+// NewLambertAzimuthalEqualAreaEllipsoid constructs a Lambert Azimuthal Equal
+// Area converter for the given ellipsoid.
+func NewLambertAzimuthalEqualAreaEllipsoid(ellipsoid Ellipsoid, centralMeridian,
+	originLatitude, falseEasting, falseNorthing float64) (*LambertAzimuthalEqualArea, error) {
+	return NewLambertAzimuthalEqualArea(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, centralMeridian,
+		originLatitude, falseEasting, falseNorthing)
+}
+
+// ConvertFromGeodetic converts geodetic coordinates (latitude and longitude) to
+// Lambert Azimuthal Equal Area coordinates (easting and northing), according
+// to the current ellipsoid and Lambert Azimuthal Equal Area projection
+// parameters.  The point opposite the origin projects onto a circle and is
+// rejected.
+func (l *LambertAzimuthalEqualArea) ConvertFromGeodetic(geodeticCoordinates s2.LatLng) (MapCoords, error) {
+	longitude := geodeticCoordinates.Lng.Radians()
+	latitude := geodeticCoordinates.Lat.Radians()
+
+	if (latitude < -math.Pi/2) || (latitude > math.Pi/2) {
+		return MapCoords{}, errors.New("latitude out of range")
+	}
+	if (longitude < -math.Pi) || (longitude > 2*math.Pi) {
+		return MapCoords{}, errors.New("longitude out of range")
+	}
+
+	dlam := longitude - l.laeaOriginLong
+	if dlam > math.Pi {
+		dlam -= 2 * math.Pi
+	}
+	if dlam < -math.Pi {
+		dlam += 2 * math.Pi
+	}
+	q := l.authalic.q(latitude)
+
+	if l.laeaPole != 0 {
+		if latitude == -l.laeaPole*math.Pi/2 {
+			return MapCoords{}, errors.New("Point projects into a circle")
+		}
+		rho := l.semiMajorAxis * math.Sqrt(math.Max(0, l.authalic.qp-l.laeaPole*q))
+		return MapCoords{
+			Easting:  l.laeaFalseEasting + rho*math.Sin(dlam),
+			Northing: l.laeaFalseNorthing - l.laeaPole*rho*math.Cos(dlam),
+		}, nil
+	}
+
+	beta := math.Asin(math.Max(-1, math.Min(1, q/l.authalic.qp)))
+	sinBeta := math.Sin(beta)
+	cosBeta := math.Cos(beta)
+	denom := 1 + l.laeaSinBeta0*sinBeta + l.laeaCosBeta0*cosBeta*math.Cos(dlam)
+	if denom <= 1.0e-12 {
+		return MapCoords{}, errors.New("Point projects into a circle")
+	}
+	b := l.laeaRq * math.Sqrt(2/denom)
+	return MapCoords{
+		Easting:  l.laeaFalseEasting + b*l.laeaD*cosBeta*math.Sin(dlam),
+		Northing: l.laeaFalseNorthing + (b/l.laeaD)*(l.laeaCosBeta0*sinBeta-l.laeaSinBeta0*cosBeta*math.Cos(dlam)),
+	}, nil
+}
+
+// ConvertToGeodetic converts Lambert Azimuthal Equal Area coordinates (easting
+// and northing) to geodetic coordinates (latitude and longitude) according to
+// the current ellipsoid and Lambert Azimuthal Equal Area projection
+// parameters.
+func (l *LambertAzimuthalEqualArea) ConvertToGeodetic(mapProjectionCoordinates MapCoords) (s2.LatLng, error) {
+	dx := mapProjectionCoordinates.Easting - l.laeaFalseEasting
+	dy := mapProjectionCoordinates.Northing - l.laeaFalseNorthing
+
+	var beta, longitude float64
+	if l.laeaPole != 0 {
+		rho := math.Hypot(dx, dy)
+		rhoOverA := rho / l.semiMajorAxis
+		q := l.laeaPole * (l.authalic.qp - rhoOverA*rhoOverA)
+		if math.IsNaN(q) || (q < -l.authalic.qp*(1+1.0e-12)) || (q > l.authalic.qp*(1+1.0e-12)) {
+			return s2.LatLng{}, errors.New("Point is outside of projection area")
+		}
+		beta = math.Asin(math.Max(-1, math.Min(1, q/l.authalic.qp)))
+		longitude = l.laeaOriginLong
+		if rho != 0 {
+			longitude += math.Atan2(dx, -l.laeaPole*dy)
+		}
+	} else {
+		x := dx / l.laeaD
+		y := dy * l.laeaD
+		rho := math.Hypot(x, y)
+		if math.IsNaN(rho) || (rho > 2*l.laeaRq*(1+1.0e-12)) {
+			return s2.LatLng{}, errors.New("Point is outside of projection area")
+		}
+		longitude = l.laeaOriginLong
+		if rho == 0 {
+			beta = math.Asin(l.laeaSinBeta0)
+		} else {
+			c := 2 * math.Asin(math.Min(1, rho/(2*l.laeaRq)))
+			sinC := math.Sin(c)
+			cosC := math.Cos(c)
+			beta = math.Asin(math.Max(-1, math.Min(1, cosC*l.laeaSinBeta0+y*sinC*l.laeaCosBeta0/rho)))
+			longitude += math.Atan2(x*sinC, rho*l.laeaCosBeta0*cosC-y*l.laeaSinBeta0*sinC)
+		}
+	}
+
+	latitude := l.authalic.latitude(beta)
+	if longitude > math.Pi {
+		longitude -= 2 * math.Pi
+	} else if longitude < -math.Pi {
+		longitude += 2 * math.Pi
+	}
+	return s2.LatLng{Lat: s1.Angle(latitude), Lng: s1.Angle(longitude)}, nil
+}
+
+// Parameters returns the ellipsoid and Lambert Azimuthal Equal Area projection
+// parameters.
+func (l *LambertAzimuthalEqualArea) Parameters() ProjectionParameters {
+	return ProjectionParameters{
+		SemiMajorAxis:   l.semiMajorAxis,
+		Flattening:      l.flattening,
+		CentralMeridian: l.laeaOriginLong,
+		OriginLatitude:  l.laeaOriginLat,
+		FalseEasting:    l.laeaFalseEasting,
+		FalseNorthing:   l.laeaFalseNorthing,
+		ScaleFactor:     1.0,
+	}
+}
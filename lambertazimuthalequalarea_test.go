@@ -0,0 +1,85 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestLambertAzimuthalEqualArea(t *testing.T) {
+	grs80, _ := coordconv.EllipsoidByCode("RF")
+
+	// EPSG Guidance Note 7-2, ETRS89 / LAEA Europe
+	laea, err := coordconv.NewLambertAzimuthalEqualAreaEllipsoid(grs80,
+		10*math.Pi/180, 52*math.Pi/180, 4321000, 3210000)
+	if err != nil {
+		t.Fatalf("error creating lambert azimuthal equal area converter: %s", err)
+	}
+	geo := s2.LatLngFromDegrees(50, 5)
+	expected := coordconv.MapCoords{Easting: 3962799.45, Northing: 2999718.85}
+
+	const epsilon = 0.01
+	mc, err := laea.ConvertFromGeodetic(geo)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(mc.Easting-expected.Easting) > epsilon ||
+		math.Abs(mc.Northing-expected.Northing) > epsilon {
+		t.Errorf("expected %v, got %v", expected, mc)
+	}
+	geo2, err := laea.ConvertToGeodetic(expected)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > epsilon {
+		t.Errorf("expected %s, got %s", geo, geo2)
+	}
+}
+
+func TestLambertAzimuthalEqualAreaRoundTrip(t *testing.T) {
+	for _, originLat := range []float64{-90, -45, 0, 52, 90} {
+		laea, err := coordconv.NewLambertAzimuthalEqualArea(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+			10*math.Pi/180, originLat*math.Pi/180, 4321000, 3210000)
+		if err != nil {
+			t.Fatalf("error creating lambert azimuthal equal area converter: %s", err)
+		}
+		origin := s2.LatLngFromDegrees(originLat, 10)
+		for lng := -170.0; lng < 180; lng += 10 {
+			for lat := -80.0; lat <= 80; lat += 10 {
+				if lat == -originLat && lng == -170 {
+					continue
+				}
+				geo := s2.LatLngFromDegrees(lat, lng)
+				mc, err := laea.ConvertFromGeodetic(geo)
+				if err != nil {
+					t.Fatalf("origin %g: expected no error at %s, got %s", originLat, geo, err)
+				}
+				geo2, err := laea.ConvertToGeodetic(mc)
+				if err != nil {
+					t.Fatalf("origin %g: expected no error in round trip at %s, got %s", originLat, geo, err)
+				}
+				if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > 1e-3 {
+					t.Errorf("origin %g: expected %s, got %s", originLat, geo, geo2)
+				}
+				// finite differences lose precision close to the antipode
+				if geo.Distance(origin).Degrees() < 150 {
+					checkEqualArea(t, "lambert azimuthal equal area", laea.ConvertFromGeodetic,
+						ellipsoidSemiMajorAxis, ellipsoidFlattening, geo)
+				}
+			}
+		}
+
+		mc, err := laea.ConvertFromGeodetic(origin)
+		if err != nil {
+			t.Fatalf("origin %g: expected no error, got %s", originLat, err)
+		}
+		if math.Abs(mc.Easting-4321000) > 1e-6 || math.Abs(mc.Northing-3210000) > 1e-6 {
+			t.Errorf("origin %g: expected the origin at the false easting and northing, got %v", originLat, mc)
+		}
+		if _, err := laea.ConvertFromGeodetic(s2.LatLngFromDegrees(-originLat, -170)); err == nil {
+			t.Errorf("origin %g: expected an error projecting the antipode of the origin", originLat)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package coordconv
+
+import (
+	"errors"
+	"math"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// LambertConformalConic provides conversions between geodetic coordinates
+// (latitude and longitude) and Lambert Conformal Conic projection coordinates
+// (easting and northing).  The formulas are those of EPSG Guidance Note 7-2,
+// for both the one standard parallel (1SP) and two standard parallel (2SP)
+// variants.
+type LambertConformalConic struct {
+	semiMajorAxis float64
+	flattening    float64
+	es            float64 // Eccentricity of ellipsoid
+	esOverTwo     float64 // es / 2.0
+
+	// Lambert Conformal Conic projection Parameters
+	lambertOriginLat       float64 // Latitude of origin in radians
+	lambertCentralMeridian float64 // Longitude of origin in radians
+	lambertStdParallel1    float64 // First standard parallel in radians, 2SP only
+	lambertStdParallel2    float64 // Second standard parallel in radians, 2SP only
+	lambertFalseEasting    float64 // False easting in meters
+	lambertFalseNorthing   float64 // False northing in meters
+	lambertScaleFactor     float64 // Scale factor at the origin, 1SP only
+	lambertN               float64 // Cone constant
+	lambertAF              float64 // Semi-major axis * scale factor * F
+	lambertRho0            float64 // Radius to the origin
+
+	// Maximum variance for easting and northing values
+	lambertDeltaEasting  float64
+	lambertDeltaNorthing float64
+}
+
+// NewLambertConformalConic1SP constructs a Lambert Conformal Conic converter
+// with a single standard parallel at the latitude of origin, where the scale
+// is scaleFactor.
+func NewLambertConformalConic1SP(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+	originLatitude, falseEasting, falseNorthing, scaleFactor float64) (*LambertConformalConic, error) {
+	if err := checkLambertEllipsoid(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian); err != nil {
+		return nil, err
+	}
+	if (originLatitude <= -math.Pi/2) || (originLatitude >= math.Pi/2) || (originLatitude == 0) {
+		return nil, errors.New("Origin Latitude out of range")
+	}
+	const minScaleFactor = 0.1
+	const maxScaleFactor = 3.0
+	if (scaleFactor < minScaleFactor) || (scaleFactor > maxScaleFactor) {
+		return nil, errors.New("Scale factor out of range")
+	}
+
+	l := newLambertConformalConic(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+		originLatitude, falseEasting, falseNorthing)
+	l.lambertScaleFactor = scaleFactor
+
+	m0 := l.lambertM(originLatitude)
+	t0 := l.lambertT(originLatitude)
+	l.lambertN = math.Sin(originLatitude)
+	l.lambertAF = l.semiMajorAxis * scaleFactor * m0 / (l.lambertN * math.Pow(t0, l.lambertN))
+	l.lambertRho0 = l.lambertRho(originLatitude)
+	return l, nil
+}
+
+// NewLambertConformalConic1SPEllipsoid constructs a Lambert Conformal Conic
+// (1SP) converter for the given ellipsoid.
+func NewLambertConformalConic1SPEllipsoid(ellipsoid Ellipsoid, centralMeridian,
+	originLatitude, falseEasting, falseNorthing, scaleFactor float64) (*LambertConformalConic, error) {
+	return NewLambertConformalConic1SP(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, centralMeridian,
+		originLatitude, falseEasting, falseNorthing, scaleFactor)
+}
+
+// NewLambertConformalConic2SP constructs a Lambert Conformal Conic converter
+// with true scale along two standard parallels.  The false easting and
+// northing are at the latitude of origin (the false origin) on the central
+// meridian.
+func NewLambertConformalConic2SP(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+	originLatitude, standardParallel1, standardParallel2,
+	falseEasting, falseNorthing float64) (*LambertConformalConic, error) {
+	if err := checkLambertEllipsoid(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian); err != nil {
+		return nil, err
+	}
+	if (originLatitude <= -math.Pi/2) || (originLatitude >= math.Pi/2) {
+		return nil, errors.New("Origin Latitude out of range")
+	}
+	if (standardParallel1 <= -math.Pi/2) || (standardParallel1 >= math.Pi/2) {
+		return nil, errors.New("Standard Parallel 1 out of range")
+	}
+	if (standardParallel2 <= -math.Pi/2) || (standardParallel2 >= math.Pi/2) {
+		return nil, errors.New("Standard Parallel 2 out of range")
+	}
+	if (standardParallel1 == 0) && (standardParallel2 == 0) {
+		return nil, errors.New("Standard Parallels cannot both be zero")
+	}
+	if standardParallel1 == -standardParallel2 {
+		return nil, errors.New("Standard Parallels cannot be opposite latitudes")
+	}
+
+	l := newLambertConformalConic(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+		originLatitude, falseEasting, falseNorthing)
+	l.lambertStdParallel1 = standardParallel1
+	l.lambertStdParallel2 = standardParallel2
+	l.lambertScaleFactor = 1.0
+
+	m1 := l.lambertM(standardParallel1)
+	t1 := l.lambertT(standardParallel1)
+	if standardParallel1 == standardParallel2 {
+		l.lambertN = math.Sin(standardParallel1)
+	} else {
+		m2 := l.lambertM(standardParallel2)
+		t2 := l.lambertT(standardParallel2)
+		l.lambertN = (math.Log(m1) - math.Log(m2)) / (math.Log(t1) - math.Log(t2))
+	}
+	l.lambertAF = l.semiMajorAxis * m1 / (l.lambertN * math.Pow(t1, l.lambertN))
+	l.lambertRho0 = l.lambertRho(originLatitude)
+	return l, nil
+}
+
+// NewLambertConformalConic2SPEllipsoid constructs a Lambert Conformal Conic
+// (2SP) converter for the given ellipsoid.
+func NewLambertConformalConic2SPEllipsoid(ellipsoid Ellipsoid, centralMeridian,
+	originLatitude, standardParallel1, standardParallel2,
+	falseEasting, falseNorthing float64) (*LambertConformalConic, error) {
+	return NewLambertConformalConic2SP(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, centralMeridian,
+		originLatitude, standardParallel1, standardParallel2, falseEasting, falseNorthing)
+}
+
+func checkLambertEllipsoid(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian float64) error {
+	invF := 1 / ellipsoidFlattening
+	if ellipsoidSemiMajorAxis <= 0.0 {
+		return errors.New("Semi-major axis must be greater than zero")
+	}
+	if (invF < 250) || (invF > 350) {
+		return errors.New("Inverse flattening must be between 250 and 350")
+	}
+	if (centralMeridian < -math.Pi) || (centralMeridian > 2*math.Pi) {
+		return errors.New("Origin Longitude out of range")
+	}
+	return nil
+}
+
+func newLambertConformalConic(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+	originLatitude, falseEasting, falseNorthing float64) *LambertConformalConic {
+	if centralMeridian > math.Pi {
+		centralMeridian -= 2 * math.Pi
+	}
+	l := &LambertConformalConic{
+		semiMajorAxis:          ellipsoidSemiMajorAxis,
+		flattening:             ellipsoidFlattening,
+		lambertOriginLat:       originLatitude,
+		lambertCentralMeridian: centralMeridian,
+		lambertFalseEasting:    falseEasting,
+		lambertFalseNorthing:   falseNorthing,
+		lambertDeltaEasting:    40000000.0,
+		lambertDeltaNorthing:   40000000.0,
+	}
+	es2 := 2*l.flattening - l.flattening*l.flattening
+	l.es = math.Sqrt(es2)
+	l.esOverTwo = l.es / 2.0
+	return l
+}
+
+// ConvertFromGeodetic converts geodetic coordinates (latitude and longitude) to
+// Lambert Conformal Conic coordinates (easting and northing), according to the
+// current ellipsoid and Lambert Conformal Conic projection parameters.
+func (l *LambertConformalConic) ConvertFromGeodetic(geodeticCoordinates s2.LatLng) (MapCoords, error) {
+	longitude := geodeticCoordinates.Lng.Radians()
+	latitude := geodeticCoordinates.Lat.Radians()
+
+	if (latitude < -math.Pi/2) || (latitude > math.Pi/2) {
+		return MapCoords{}, errors.New("latitude out of range")
+	}
+	if (longitude < -math.Pi) || (longitude > 2*math.Pi) {
+		return MapCoords{}, errors.New("longitude out of range")
+	}
+	// the pole opposite the apex of the cone projects to infinity
+	if math.Abs(latitude) == math.Pi/2 && (latitude < 0) == (l.lambertN > 0) {
+		return MapCoords{}, errors.New("Point projects into infinity")
+	}
+
+	rho := l.lambertRho(latitude)
+	theta := l.lambertN * l.deltaLongitude(longitude)
+	return MapCoords{
+		Easting:  l.lambertFalseEasting + rho*math.Sin(theta),
+		Northing: l.lambertFalseNorthing + l.lambertRho0 - rho*math.Cos(theta),
+	}, nil
+}
+
+// ConvertToGeodetic converts Lambert Conformal Conic coordinates (easting and
+// northing) to geodetic coordinates (latitude and longitude) according to the
+// current ellipsoid and Lambert Conformal Conic projection parameters.
+func (l *LambertConformalConic) ConvertToGeodetic(mapProjectionCoordinates MapCoords) (s2.LatLng, error) {
+	easting := mapProjectionCoordinates.Easting
+	northing := mapProjectionCoordinates.Northing
+
+	if (easting < (l.lambertFalseEasting - l.lambertDeltaEasting)) ||
+		(easting > (l.lambertFalseEasting + l.lambertDeltaEasting)) {
+		return s2.LatLng{}, errors.New("easting out of range")
+	}
+	if (northing < (l.lambertFalseNorthing - l.lambertDeltaNorthing)) ||
+		(northing > (l.lambertFalseNorthing + l.lambertDeltaNorthing)) {
+		return s2.LatLng{}, errors.New("northing out of range")
+	}
+
+	dx := easting - l.lambertFalseEasting
+	dy := l.lambertRho0 - (northing - l.lambertFalseNorthing)
+	if l.lambertN < 0 {
+		dx = -dx
+		dy = -dy
+	}
+	rho := math.Copysign(math.Hypot(dx, dy), l.lambertN)
+
+	var latitude, longitude float64
+	if rho == 0 {
+		// the apex of the cone
+		latitude = math.Copysign(math.Pi/2, l.lambertN)
+		longitude = l.lambertCentralMeridian
+	} else {
+		t := math.Pow(rho/l.lambertAF, 1/l.lambertN)
+		latitude = math.Pi/2 - 2*math.Atan(t)
+		tempLat := 0.0
+		for count := 30; math.Abs(latitude-tempLat) > 1.0e-12 && count != 0; count-- {
+			tempLat = latitude
+			latitude = math.Pi/2 - 2*math.Atan(t*l.lambertPow(math.Sin(latitude)))
+		}
+		longitude = l.lambertCentralMeridian + math.Atan2(dx, dy)/l.lambertN
+	}
+
+	if longitude > math.Pi {
+		longitude -= 2 * math.Pi
+	} else if longitude < -math.Pi {
+		longitude += 2 * math.Pi
+	}
+	if (longitude > math.Pi) || (longitude < -math.Pi) {
+		return s2.LatLng{}, errors.New("easting out of range")
+	}
+	return s2.LatLng{Lat: s1.Angle(latitude), Lng: s1.Angle(longitude)}, nil
+}
+
+// ConvertFromGeodeticFactors converts geodetic coordinates (latitude and
+// longitude) to Lambert Conformal Conic coordinates (easting and northing) and
+// also returns the meridian convergence and point scale factor at the point.
+func (l *LambertConformalConic) ConvertFromGeodeticFactors(geodeticCoordinates s2.LatLng) (MapCoords, GridFactors, error) {
+	mapCoords, err := l.ConvertFromGeodetic(geodeticCoordinates)
+	if err != nil {
+		return MapCoords{}, GridFactors{}, err
+	}
+	return mapCoords, l.gridFactors(geodeticCoordinates.Lat.Radians(), geodeticCoordinates.Lng.Radians()), nil
+}
+
+// ConvertToGeodeticFactors converts Lambert Conformal Conic coordinates
+// (easting and northing) to geodetic coordinates (latitude and longitude) and
+// also returns the meridian convergence and point scale factor at the point.
+func (l *LambertConformalConic) ConvertToGeodeticFactors(mapProjectionCoordinates MapCoords) (s2.LatLng, GridFactors, error) {
+	geodeticCoordinates, err := l.ConvertToGeodetic(mapProjectionCoordinates)
+	if err != nil {
+		return s2.LatLng{}, GridFactors{}, err
+	}
+	return geodeticCoordinates, l.gridFactors(geodeticCoordinates.Lat.Radians(), geodeticCoordinates.Lng.Radians()), nil
+}
+
+// gridFactors computes the meridian convergence and point scale factor at
+// the given latitude and longitude.  The meridians are straight lines through
+// the apex of the cone, so the convergence is the cone constant times the
+// longitude difference.  The scale is the ratio of the radius on the grid to
+// the radius of the parallel on the ellipsoid.
+func (l *LambertConformalConic) gridFactors(latitude, longitude float64) GridFactors {
+	gamma := l.lambertN * l.deltaLongitude(longitude)
+	// The scale is infinite at the apex of the cone
+	k := math.Inf(1)
+	if math.Abs(latitude) != math.Pi/2 {
+		k = l.lambertRho(latitude) * l.lambertN / (l.semiMajorAxis * l.lambertM(latitude))
+	}
+	return GridFactors{Convergence: s1.Angle(gamma), PointScale: k}
+}
+
+// deltaLongitude returns the longitude from the central meridian in (-Pi, Pi].
+func (l *LambertConformalConic) deltaLongitude(longitude float64) float64 {
+	dlam := longitude - l.lambertCentralMeridian
+	if dlam > math.Pi {
+		dlam -= 2 * math.Pi
+	}
+	if dlam < -math.Pi {
+		dlam += 2 * math.Pi
+	}
+	return dlam
+}
+
+// lambertM returns the radius of the parallel at the given latitude, divided
+// by the semi-major axis.
+func (l *LambertConformalConic) lambertM(latitude float64) float64 {
+	essin := l.es * math.Sin(latitude)
+	return math.Cos(latitude) / math.Sqrt(1.0-essin*essin)
+}
+
+// lambertT returns the tangent of half the conformal colatitude.
+func (l *LambertConformalConic) lambertT(latitude float64) float64 {
+	return math.Tan(math.Pi/4-latitude/2.0) / l.lambertPow(math.Sin(latitude))
+}
+
+// lambertRho returns the distance on the grid from the apex of the cone to a
+// point at the given latitude.
+func (l *LambertConformalConic) lambertRho(latitude float64) float64 {
+	if math.Abs(latitude) == math.Pi/2 {
+		return 0
+	}
+	return l.lambertAF * math.Pow(l.lambertT(latitude), l.lambertN)
+}
+
+func (l *LambertConformalConic) lambertPow(sinLat float64) float64 {
+	esSin := l.es * sinLat
+	return math.Pow((1.0-esSin)/(1.0+esSin), l.esOverTwo)
+}
+
+// Parameters returns the ellipsoid and Lambert Conformal Conic projection
+// parameters.
+func (l *LambertConformalConic) Parameters() ProjectionParameters {
+	return ProjectionParameters{
+		SemiMajorAxis:     l.semiMajorAxis,
+		Flattening:        l.flattening,
+		CentralMeridian:   l.lambertCentralMeridian,
+		OriginLatitude:    l.lambertOriginLat,
+		StandardParallel:  l.lambertStdParallel1,
+		StandardParallel2: l.lambertStdParallel2,
+		FalseEasting:      l.lambertFalseEasting,
+		FalseNorthing:     l.lambertFalseNorthing,
+		ScaleFactor:       l.lambertScaleFactor,
+	}
+}
@@ -0,0 +1,150 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+const usSurveyFoot = 1200.0 / 3937.0
+
+func TestLambertConformalConic(t *testing.T) {
+	clarke1866, _ := coordconv.EllipsoidByCode("CC")
+
+	// EPSG Guidance Note 7-2, JAD69 / Jamaica National Grid
+	jamaica, err := coordconv.NewLambertConformalConic1SPEllipsoid(clarke1866,
+		-77*math.Pi/180, 18*math.Pi/180, 250000, 150000, 1)
+	if err != nil {
+		t.Fatalf("error creating lambert conformal conic converter: %s", err)
+	}
+	// EPSG Guidance Note 7-2, NAD27 / Texas South Central
+	texas, err := coordconv.NewLambertConformalConic2SPEllipsoid(clarke1866,
+		-99*math.Pi/180, (27+50.0/60)*math.Pi/180, (28+23.0/60)*math.Pi/180, (30+17.0/60)*math.Pi/180,
+		2000000*usSurveyFoot, 0)
+	if err != nil {
+		t.Fatalf("error creating lambert conformal conic converter: %s", err)
+	}
+
+	testCases := []struct {
+		name       string
+		projection *coordconv.LambertConformalConic
+		geo        s2.LatLng
+		expected   coordconv.MapCoords
+	}{
+		{
+			name:       "jamaica",
+			projection: jamaica,
+			geo:        s2.LatLngFromDegrees(17+55.0/60+55.80/3600, -(76 + 56.0/60 + 37.26/3600)),
+			expected:   coordconv.MapCoords{Easting: 255966.58, Northing: 142493.51},
+		},
+		{
+			name:       "texas",
+			projection: texas,
+			geo:        s2.LatLngFromDegrees(28.5, -96),
+			expected:   coordconv.MapCoords{Easting: 2963503.91 * usSurveyFoot, Northing: 254759.80 * usSurveyFoot},
+		},
+	}
+
+	const epsilon = 0.01
+	for _, tc := range testCases {
+		mc, err := tc.projection.ConvertFromGeodetic(tc.geo)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if math.Abs(mc.Easting-tc.expected.Easting) > epsilon ||
+			math.Abs(mc.Northing-tc.expected.Northing) > epsilon {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, mc)
+		}
+
+		geo, err := tc.projection.ConvertToGeodetic(tc.expected)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if geo.Distance(tc.geo).Radians()*ellipsoidSemiMajorAxis > epsilon {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.geo, geo)
+		}
+	}
+}
+
+func TestLambertConformalConicRoundTrip(t *testing.T) {
+	for _, sign := range []float64{-1, 1} {
+		lcc, err := coordconv.NewLambertConformalConic2SP(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+			3*math.Pi/180, sign*46.5*math.Pi/180, sign*49*math.Pi/180, sign*44*math.Pi/180, 700000, 6600000)
+		if err != nil {
+			t.Fatalf("error creating lambert conformal conic converter: %s", err)
+		}
+		for lng := -180.0; lng < 180; lng += 10 {
+			for lat := -60.0; lat <= 90; lat += 10 {
+				geo := s2.LatLngFromDegrees(sign*lat, lng)
+				mc, factors, err := lcc.ConvertFromGeodeticFactors(geo)
+				if err != nil {
+					t.Fatalf("expected no error at %s, got %s", geo, err)
+				}
+				geo2, err := lcc.ConvertToGeodetic(mc)
+				if err != nil {
+					t.Fatalf("expected no error in round trip at %s, got %s", geo, err)
+				}
+				if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > 1e-3 {
+					t.Errorf("expected %s, got %s", geo, geo2)
+				}
+				if math.Abs(lat) < 90 {
+					checkGridFactors(t, "lambert conformal conic", lcc.ConvertFromGeodetic,
+						ellipsoidSemiMajorAxis, ellipsoidFlattening, geo, factors)
+				}
+			}
+		}
+
+		// true scale along the standard parallels
+		for _, sp := range []float64{44, 49} {
+			_, factors, err := lcc.ConvertFromGeodeticFactors(s2.LatLngFromDegrees(sign*sp, 10))
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+			if math.Abs(factors.PointScale-1) > 1e-12 {
+				t.Errorf("expected a point scale of 1 at the standard parallel, got %f", factors.PointScale)
+			}
+		}
+
+		// the opposite pole is at infinity
+		if _, err := lcc.ConvertFromGeodetic(s2.LatLngFromDegrees(-sign*90, 0)); err == nil {
+			t.Errorf("expected an error projecting the pole opposite the apex")
+		}
+	}
+
+	lcc, err := coordconv.NewLambertConformalConic1SP(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		-77*math.Pi/180, 18*math.Pi/180, 250000, 150000, 0.9996)
+	if err != nil {
+		t.Fatalf("error creating lambert conformal conic converter: %s", err)
+	}
+	mc, factors, err := lcc.ConvertFromGeodeticFactors(s2.LatLngFromDegrees(18, -77))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(mc.Easting-250000) > 1e-6 || math.Abs(mc.Northing-150000) > 1e-6 {
+		t.Errorf("expected the origin at the false easting and northing, got %v", mc)
+	}
+	if factors.Convergence != 0 || math.Abs(factors.PointScale-0.9996) > 1e-12 {
+		t.Errorf("expected no convergence and the origin scale factor, got %v", factors)
+	}
+}
+
+func TestLambertConformalConicInvalid(t *testing.T) {
+	if _, err := coordconv.NewLambertConformalConic1SP(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		0, 0, 0, 0, 1); err == nil {
+		t.Errorf("expected an error for an origin on the equator")
+	}
+	if _, err := coordconv.NewLambertConformalConic1SP(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		0, math.Pi/4, 0, 0, 0); err == nil {
+		t.Errorf("expected an error for a zero scale factor")
+	}
+	if _, err := coordconv.NewLambertConformalConic2SP(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		0, 0, math.Pi/6, -math.Pi/6, 0, 0); err == nil {
+		t.Errorf("expected an error for opposite standard parallels")
+	}
+	if _, err := coordconv.NewLambertConformalConic2SP(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		0, 0, math.Pi/2, math.Pi/6, 0, 0); err == nil {
+		t.Errorf("expected an error for a standard parallel at the pole")
+	}
+}
@@ -0,0 +1,197 @@
+package coordconv
+
+import (
+	"math"
+
+	"github.com/golang/geo/s2"
+)
+
+// ENUCoord is a local east, north and up offset in meters.
+type ENUCoord struct {
+	East  float64
+	North float64
+	Up    float64
+}
+
+// NEDCoord is a local north, east and down offset in meters.
+type NEDCoord struct {
+	North float64
+	East  float64
+	Down  float64
+}
+
+// NED returns the offset in north, east and down order.
+func (c ENUCoord) NED() NEDCoord {
+	return NEDCoord{North: c.North, East: c.East, Down: -c.Up}
+}
+
+// ENU returns the offset in east, north and up order.
+func (c NEDCoord) ENU() ENUCoord {
+	return ENUCoord{East: c.East, North: c.North, Up: -c.Down}
+}
+
+// LocalCartesian is a coordinate converter to and from a local tangent plane
+// at an origin point.  East and north lie in the plane tangent to the
+// ellipsoid at the origin and up is along the ellipsoid normal.
+type LocalCartesian struct {
+	geocentric   *Geocentric
+	mgrs         *MGRS
+	origin       s2.LatLng
+	originHeight float64
+	originX      float64
+	originY      float64
+	originZ      float64
+	sinLat       float64
+	cosLat       float64
+	sinLon       float64
+	cosLon       float64
+}
+
+// NewLocalCartesian constructs a local cartesian converter with ellipsoid
+// parameters, about an origin at the given height above the ellipsoid.  The
+// ellipsoid code selects the MGRS lettering scheme, as with NewMGRS.
+func NewLocalCartesian(ellipsoidSemiMajorAxis, ellipsoidFlattening float64, ellipsoidCode string,
+	origin s2.LatLng, originHeight float64) (*LocalCartesian, error) {
+	mgrs, err := NewMGRS(ellipsoidSemiMajorAxis, ellipsoidFlattening, ellipsoidCode)
+	if err != nil {
+		return nil, err
+	}
+	return newLocalCartesian(mgrs, origin, originHeight)
+}
+
+// NewLocalCartesianEllipsoid constructs a local cartesian converter for the
+// given ellipsoid, about an origin at the given height above the ellipsoid.
+func NewLocalCartesianEllipsoid(ellipsoid Ellipsoid, origin s2.LatLng, originHeight float64) (*LocalCartesian, error) {
+	mgrs, err := NewMGRSEllipsoid(ellipsoid)
+	if err != nil {
+		return nil, err
+	}
+	return newLocalCartesian(mgrs, origin, originHeight)
+}
+
+// NewLocalCartesianUTM constructs a local cartesian converter about an origin
+// given as a UTM coordinate, using the ellipsoid of the UTM converter.
+func NewLocalCartesianUTM(utm *UTM, origin UTMCoord, originHeight float64) (*LocalCartesian, error) {
+	geodeticCoordinates, err := utm.ConvertToGeodetic(origin)
+	if err != nil {
+		return nil, err
+	}
+	mgrs, err := NewMGRS(utm.semiMajorAxis, utm.flattening, utm.ellipsCode)
+	if err != nil {
+		return nil, err
+	}
+	return newLocalCartesian(mgrs, geodeticCoordinates, originHeight)
+}
+
+// NewLocalCartesianMGRS constructs a local cartesian converter about an origin
+// at the centre of the cell named by an MGRS coordinate string, using the
+// ellipsoid of the MGRS converter.
+func NewLocalCartesianMGRS(mgrs *MGRS, origin string, originHeight float64) (*LocalCartesian, error) {
+	cell, err := mgrs.ConvertToCell(origin)
+	if err != nil {
+		return nil, err
+	}
+	return newLocalCartesian(mgrs, cell.Center, originHeight)
+}
+
+func newLocalCartesian(mgrs *MGRS, origin s2.LatLng, originHeight float64) (*LocalCartesian, error) {
+	l := &LocalCartesian{
+		geocentric:   mgrs.geocentric,
+		mgrs:         mgrs,
+		origin:       origin,
+		originHeight: originHeight,
+	}
+	c, err := l.geocentric.ConvertFromGeodetic(origin, originHeight)
+	if err != nil {
+		return nil, err
+	}
+	l.originX, l.originY, l.originZ = c.X, c.Y, c.Z
+	l.sinLat, l.cosLat = math.Sincos(origin.Lat.Radians())
+	l.sinLon, l.cosLon = math.Sincos(origin.Lng.Radians())
+	return l, nil
+}
+
+// Origin returns the origin of the local tangent plane and its height above
+// the ellipsoid.
+func (l *LocalCartesian) Origin() (s2.LatLng, float64) {
+	return l.origin, l.originHeight
+}
+
+// ConvertFromGeodetic converts geodetic coordinates and height above the
+// ellipsoid to an east, north and up offset from the origin.
+func (l *LocalCartesian) ConvertFromGeodetic(geodeticCoordinates s2.LatLng, height float64) (ENUCoord, error) {
+	c, err := l.geocentric.ConvertFromGeodetic(geodeticCoordinates, height)
+	if err != nil {
+		return ENUCoord{}, err
+	}
+	return l.fromGeocentric(c), nil
+}
+
+// ConvertToGeodetic converts an east, north and up offset from the origin to
+// geodetic coordinates and height above the ellipsoid.
+func (l *LocalCartesian) ConvertToGeodetic(enuCoordinates ENUCoord) (s2.LatLng, float64, error) {
+	return l.geocentric.ConvertToGeodetic(l.toGeocentric(enuCoordinates))
+}
+
+// ConvertFromGeodeticNED converts geodetic coordinates and height above the
+// ellipsoid to a north, east and down offset from the origin.
+func (l *LocalCartesian) ConvertFromGeodeticNED(geodeticCoordinates s2.LatLng, height float64) (NEDCoord, error) {
+	enu, err := l.ConvertFromGeodetic(geodeticCoordinates, height)
+	if err != nil {
+		return NEDCoord{}, err
+	}
+	return enu.NED(), nil
+}
+
+// ConvertToGeodeticNED converts a north, east and down offset from the origin
+// to geodetic coordinates and height above the ellipsoid.
+func (l *LocalCartesian) ConvertToGeodeticNED(nedCoordinates NEDCoord) (s2.LatLng, float64, error) {
+	return l.ConvertToGeodetic(nedCoordinates.ENU())
+}
+
+// ConvertFromMGRS converts an MGRS coordinate string and height above the
+// ellipsoid to an east, north and up offset from the origin.
+func (l *LocalCartesian) ConvertFromMGRS(mgrsorUSNGCoordinates string, height float64) (ENUCoord, error) {
+	geodeticCoordinates, err := l.mgrs.ConvertToGeodetic(mgrsorUSNGCoordinates)
+	if err != nil {
+		return ENUCoord{}, err
+	}
+	return l.ConvertFromGeodetic(geodeticCoordinates, height)
+}
+
+// ConvertToMGRS converts an east, north and up offset from the origin to an
+// MGRS coordinate string and height above the ellipsoid.
+func (l *LocalCartesian) ConvertToMGRS(enuCoordinates ENUCoord, precision int) (string, float64, error) {
+	geodeticCoordinates, height, err := l.ConvertToGeodetic(enuCoordinates)
+	if err != nil {
+		return "", 0, err
+	}
+	mgrs, err := l.mgrs.ConvertFromGeodetic(geodeticCoordinates, precision)
+	if err != nil {
+		return "", 0, err
+	}
+	return mgrs, height, nil
+}
+
+// fromGeocentric rotates a geocentric coordinate into the local frame.
+func (l *LocalCartesian) fromGeocentric(c GeocentricCoord) ENUCoord {
+	dx := c.X - l.originX
+	dy := c.Y - l.originY
+	dz := c.Z - l.originZ
+	t := l.cosLon*dx + l.sinLon*dy
+	return ENUCoord{
+		East:  -l.sinLon*dx + l.cosLon*dy,
+		North: -l.sinLat*t + l.cosLat*dz,
+		Up:    l.cosLat*t + l.sinLat*dz,
+	}
+}
+
+// toGeocentric rotates a local offset back into geocentric coordinates.
+func (l *LocalCartesian) toGeocentric(c ENUCoord) GeocentricCoord {
+	t := -l.sinLat*c.North + l.cosLat*c.Up
+	return GeocentricCoord{
+		X: l.originX - l.sinLon*c.East + l.cosLon*t,
+		Y: l.originY + l.cosLon*c.East + l.sinLon*t,
+		Z: l.originZ + l.cosLat*c.North + l.sinLat*c.Up,
+	}
+}
@@ -0,0 +1,123 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestLocalCartesian(t *testing.T) {
+	// EPSG guidance note 7-2 example for geographic/topocentric conversions
+	origin := s2.LatLngFromDegrees(55, 5)
+	l, err := coordconv.NewLocalCartesianEllipsoid(coordconv.EllipsoidWGS84, origin, 200)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	geo := s2.LatLngFromDegrees(53+48.0/60+33.820/3600, 2+7.0/60+46.380/3600)
+	expected := coordconv.ENUCoord{East: -189013.869, North: -128642.040, Up: -4220.171}
+
+	enu, err := l.ConvertFromGeodetic(geo, 73)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(enu.East-expected.East) > 1e-3 || math.Abs(enu.North-expected.North) > 1e-3 ||
+		math.Abs(enu.Up-expected.Up) > 1e-3 {
+		t.Errorf("expected %+v, got %+v", expected, enu)
+	}
+	back, height, err := l.ConvertToGeodetic(expected)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if back.Distance(geo).Radians()*ellipsoidSemiMajorAxis > 1e-3 || math.Abs(height-73) > 1e-3 {
+		t.Errorf("expected %s at 73m, got %s at %fm", geo, back, height)
+	}
+
+	ned, err := l.ConvertFromGeodeticNED(geo, 73)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if ned.North != enu.North || ned.East != enu.East || ned.Down != -enu.Up {
+		t.Errorf("expected %+v, got %+v", enu.NED(), ned)
+	}
+	if ned.ENU() != enu {
+		t.Errorf("expected %+v, got %+v", enu, ned.ENU())
+	}
+	back, height, err = l.ConvertToGeodeticNED(ned)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if back.Distance(geo).Radians()*ellipsoidSemiMajorAxis > 1e-3 || math.Abs(height-73) > 1e-3 {
+		t.Errorf("expected %s at 73m, got %s at %fm", geo, back, height)
+	}
+
+	enu, err = l.ConvertFromGeodetic(origin, 300)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(enu.East) > 1e-6 || math.Abs(enu.North) > 1e-6 || math.Abs(enu.Up-100) > 1e-6 {
+		t.Errorf("expected straight up 100m, got %+v", enu)
+	}
+}
+
+func TestLocalCartesianOrigins(t *testing.T) {
+	const origin = "16SGC3855124838"
+	fromMGRS, err := coordconv.NewLocalCartesianMGRS(coordconv.DefaultMGRSConverter, origin, 300)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	originGeo, originHeight := fromMGRS.Origin()
+	if originHeight != 300 {
+		t.Errorf("expected origin height 300, got %f", originHeight)
+	}
+
+	utm, err := coordconv.DefaultUTMConverter.ConvertFromGeodetic(originGeo, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	fromUTM, err := coordconv.NewLocalCartesianUTM(coordconv.DefaultUTMConverter, utm, 300)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	fromGeodetic, err := coordconv.NewLocalCartesian(ellipsoidSemiMajorAxis, ellipsoidFlattening, "WE", originGeo, 300)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	target := "16SGC4855134838"
+	for _, l := range []*coordconv.LocalCartesian{fromMGRS, fromUTM, fromGeodetic} {
+		enu, err := l.ConvertFromMGRS(target, 300)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		// 10km east and north in the grid, less the grid convergence
+		if math.Abs(math.Hypot(enu.East, enu.North)-math.Sqrt2*10000) > 20 || enu.Up > -10 || enu.Up < -20 {
+			t.Errorf("expected about 10km east and north, got %+v", enu)
+		}
+		mgrs, height, err := l.ConvertToMGRS(coordconv.ENUCoord{East: enu.East + 0.5, North: enu.North + 0.5, Up: enu.Up}, 5)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if mgrs != target || math.Abs(height-300) > 1e-2 {
+			t.Errorf("expected %s at 300m, got %s at %fm", target, mgrs, height)
+		}
+	}
+
+	// the origin is the centre of a coarser cell, not its south west corner
+	fromCell, err := coordconv.NewLocalCartesianMGRS(coordconv.DefaultMGRSConverter, "16SGC3824", 300)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	enu, err := fromCell.ConvertFromMGRS("16SGC3800024000", 300)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(enu.East+500) > 20 || math.Abs(enu.North+500) > 20 || math.Abs(math.Hypot(enu.East, enu.North)-500*math.Sqrt2) > 0.5 {
+		t.Errorf("expected the south west corner 500m west and south, got %+v", enu)
+	}
+
+	if _, err := coordconv.NewLocalCartesianMGRS(coordconv.DefaultMGRSConverter, "bogus", 0); err == nil {
+		t.Errorf("expected error for invalid MGRS origin")
+	}
+}
@@ -1,7 +1,20 @@
 package coordconv
 
+import "github.com/golang/geo/s1"
+
 // MapCoords are projected map coordinates
 type MapCoords struct {
 	Easting  float64
 	Northing float64
 }
+
+// GridFactors are the meridian convergence and point scale factor of a map
+// projection at a point.
+type GridFactors struct {
+	// Convergence is the bearing of grid north measured clockwise from true
+	// north.
+	Convergence s1.Angle
+	// PointScale is the ratio of a small distance on the grid to the
+	// corresponding distance on the ellipsoid.
+	PointScale float64
+}
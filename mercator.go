@@ -0,0 +1,243 @@
+package coordconv
+
+import (
+	"errors"
+	"math"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// mercatorMaxLat is the largest latitude, in radians, that Mercator will
+// project.
+const mercatorMaxLat = (math.Pi * 89.5) / 180.0
+
+// Mercator provides conversions between geodetic coordinates (latitude and
+// longitude) and ellipsoidal Mercator projection coordinates (easting and
+// northing).  The formulas are those of EPSG Guidance Note 7-2, for both
+// variant A (scale factor at the equator) and variant B (latitude of true
+// scale).
+type Mercator struct {
+	semiMajorAxis float64
+	flattening    float64
+	es            float64 // Eccentricity of ellipsoid
+	esOverTwo     float64 // es / 2.0
+
+	// Mercator projection Parameters
+	mercStdParallel   float64 // Latitude of true scale in radians, variant B only
+	mercOriginLong    float64 // Longitude of origin in radians
+	mercFalseEasting  float64 // False easting in meters
+	mercFalseNorthing float64 // False northing in meters
+	mercScaleFactor   float64 // Scale factor at the equator
+	mercAK0           float64 // Semi-major axis * scale factor
+
+	// Maximum variance for easting and northing values
+	mercDeltaEasting  float64
+	mercDeltaNorthing float64
+}
+
+// NewMercator constructs a Mercator (variant B) converter with true scale
+// along the standard parallel and its mirror in the other hemisphere.
+func NewMercator(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+	standardParallel, falseEasting, falseNorthing float64) (*Mercator, error) {
+	if (standardParallel < -mercatorMaxLat) || (standardParallel > mercatorMaxLat) {
+		return nil, errors.New("Standard Parallel out of range")
+	}
+	m, err := newMercator(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+		falseEasting, falseNorthing)
+	if err != nil {
+		return nil, err
+	}
+	sinLat := math.Sin(standardParallel)
+	m.mercStdParallel = standardParallel
+	m.mercScaleFactor = math.Cos(standardParallel) / math.Sqrt(1.0-m.es*m.es*sinLat*sinLat)
+	m.setScaleFactor()
+	return m, nil
+}
+
+// NewMercatorEllipsoid constructs a Mercator (variant B) converter for the
+// given ellipsoid.
+func NewMercatorEllipsoid(ellipsoid Ellipsoid, centralMeridian,
+	standardParallel, falseEasting, falseNorthing float64) (*Mercator, error) {
+	return NewMercator(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, centralMeridian,
+		standardParallel, falseEasting, falseNorthing)
+}
+
+// NewMercatorScaleFactor constructs a Mercator (variant A) converter with the
+// given scale factor along the equator.
+func NewMercatorScaleFactor(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+	scaleFactor, falseEasting, falseNorthing float64) (*Mercator, error) {
+	const minScaleFactor = 0.1
+	const maxScaleFactor = 3.0
+	if (scaleFactor < minScaleFactor) || (scaleFactor > maxScaleFactor) {
+		return nil, errors.New("Scale factor out of range")
+	}
+	m, err := newMercator(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+		falseEasting, falseNorthing)
+	if err != nil {
+		return nil, err
+	}
+	m.mercScaleFactor = scaleFactor
+	m.setScaleFactor()
+	return m, nil
+}
+
+// NewMercatorScaleFactorEllipsoid constructs a Mercator (variant A) converter
+// for the given ellipsoid.
+func NewMercatorScaleFactorEllipsoid(ellipsoid Ellipsoid, centralMeridian,
+	scaleFactor, falseEasting, falseNorthing float64) (*Mercator, error) {
+	return NewMercatorScaleFactor(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, centralMeridian,
+		scaleFactor, falseEasting, falseNorthing)
+}
+
+func newMercator(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+	falseEasting, falseNorthing float64) (*Mercator, error) {
+	invF := 1 / ellipsoidFlattening
+	if ellipsoidSemiMajorAxis <= 0.0 {
+		return nil, errors.New("Semi-major axis must be greater than zero")
+	}
+	if (invF < 250) || (invF > 350) {
+		return nil, errors.New("Inverse flattening must be between 250 and 350")
+	}
+	if (centralMeridian < -math.Pi) || (centralMeridian > 2*math.Pi) {
+		return nil, errors.New("Origin Longitude out of range")
+	}
+	if centralMeridian > math.Pi {
+		centralMeridian -= 2 * math.Pi
+	}
+	m := &Mercator{
+		semiMajorAxis:     ellipsoidSemiMajorAxis,
+		flattening:        ellipsoidFlattening,
+		mercOriginLong:    centralMeridian,
+		mercFalseEasting:  falseEasting,
+		mercFalseNorthing: falseNorthing,
+	}
+	es2 := 2*m.flattening - m.flattening*m.flattening
+	m.es = math.Sqrt(es2)
+	m.esOverTwo = m.es / 2.0
+	return m, nil
+}
+
+// setScaleFactor computes the values that depend on the scale factor.
+func (m *Mercator) setScaleFactor() {
+	m.mercAK0 = m.semiMajorAxis * m.mercScaleFactor
+	m.mercDeltaEasting = m.mercAK0 * math.Pi
+	m.mercDeltaNorthing = m.mercAK0 * m.isometricLatitude(mercatorMaxLat)
+}
+
+// ConvertFromGeodetic converts geodetic coordinates (latitude and longitude) to
+// Mercator coordinates (easting and northing), according to the current
+// ellipsoid and Mercator projection parameters.
+func (m *Mercator) ConvertFromGeodetic(geodeticCoordinates s2.LatLng) (MapCoords, error) {
+	longitude := geodeticCoordinates.Lng.Radians()
+	latitude := geodeticCoordinates.Lat.Radians()
+
+	if (latitude < -mercatorMaxLat) || (latitude > mercatorMaxLat) {
+		return MapCoords{}, errors.New("latitude out of range")
+	}
+	if (longitude < -math.Pi) || (longitude > 2*math.Pi) {
+		return MapCoords{}, errors.New("longitude out of range")
+	}
+
+	return MapCoords{
+		Easting:  m.mercFalseEasting + m.mercAK0*m.deltaLongitude(longitude),
+		Northing: m.mercFalseNorthing + m.mercAK0*m.isometricLatitude(latitude),
+	}, nil
+}
+
+// ConvertToGeodetic converts Mercator coordinates (easting and northing) to
+// geodetic coordinates (latitude and longitude) according to the current
+// ellipsoid and Mercator projection parameters.
+func (m *Mercator) ConvertToGeodetic(mapProjectionCoordinates MapCoords) (s2.LatLng, error) {
+	easting := mapProjectionCoordinates.Easting
+	northing := mapProjectionCoordinates.Northing
+
+	if (easting < (m.mercFalseEasting - m.mercDeltaEasting)) ||
+		(easting > (m.mercFalseEasting + m.mercDeltaEasting)) {
+		return s2.LatLng{}, errors.New("easting out of range")
+	}
+	if (northing < (m.mercFalseNorthing - m.mercDeltaNorthing)) ||
+		(northing > (m.mercFalseNorthing + m.mercDeltaNorthing)) {
+		return s2.LatLng{}, errors.New("northing out of range")
+	}
+
+	t := math.Exp((m.mercFalseNorthing - northing) / m.mercAK0)
+	latitude := math.Pi/2 - 2*math.Atan(t)
+	tempLat := 0.0
+	for count := 30; math.Abs(latitude-tempLat) > 1.0e-12 && count != 0; count-- {
+		tempLat = latitude
+		esSin := m.es * math.Sin(latitude)
+		latitude = math.Pi/2 - 2*math.Atan(t*math.Pow((1.0-esSin)/(1.0+esSin), m.esOverTwo))
+	}
+
+	longitude := m.mercOriginLong + (easting-m.mercFalseEasting)/m.mercAK0
+	if longitude > math.Pi {
+		longitude -= 2 * math.Pi
+	} else if longitude < -math.Pi {
+		longitude += 2 * math.Pi
+	}
+	return s2.LatLng{Lat: s1.Angle(latitude), Lng: s1.Angle(longitude)}, nil
+}
+
+// ConvertFromGeodeticFactors converts geodetic coordinates (latitude and
+// longitude) to Mercator coordinates (easting and northing) and also returns
+// the meridian convergence and point scale factor at the point.
+func (m *Mercator) ConvertFromGeodeticFactors(geodeticCoordinates s2.LatLng) (MapCoords, GridFactors, error) {
+	mapCoords, err := m.ConvertFromGeodetic(geodeticCoordinates)
+	if err != nil {
+		return MapCoords{}, GridFactors{}, err
+	}
+	return mapCoords, m.gridFactors(geodeticCoordinates.Lat.Radians()), nil
+}
+
+// ConvertToGeodeticFactors converts Mercator coordinates (easting and
+// northing) to geodetic coordinates (latitude and longitude) and also returns
+// the meridian convergence and point scale factor at the point.
+func (m *Mercator) ConvertToGeodeticFactors(mapProjectionCoordinates MapCoords) (s2.LatLng, GridFactors, error) {
+	geodeticCoordinates, err := m.ConvertToGeodetic(mapProjectionCoordinates)
+	if err != nil {
+		return s2.LatLng{}, GridFactors{}, err
+	}
+	return geodeticCoordinates, m.gridFactors(geodeticCoordinates.Lat.Radians()), nil
+}
+
+// gridFactors computes the meridian convergence and point scale factor at
+// the given latitude.  The meridians are parallel to grid north, and the scale
+// is the ratio of the equator to the parallel on the ellipsoid.
+func (m *Mercator) gridFactors(latitude float64) GridFactors {
+	esSin := m.es * math.Sin(latitude)
+	k := m.mercScaleFactor * math.Sqrt(1.0-esSin*esSin) / math.Cos(latitude)
+	return GridFactors{Convergence: 0, PointScale: k}
+}
+
+// deltaLongitude returns the longitude from the central meridian in (-Pi, Pi].
+func (m *Mercator) deltaLongitude(longitude float64) float64 {
+	dlam := longitude - m.mercOriginLong
+	if dlam > math.Pi {
+		dlam -= 2 * math.Pi
+	}
+	if dlam < -math.Pi {
+		dlam += 2 * math.Pi
+	}
+	return dlam
+}
+
+// isometricLatitude returns the isometric latitude, the northing on a unit
+// Mercator projection, of the given latitude.
+func (m *Mercator) isometricLatitude(latitude float64) float64 {
+	esSin := m.es * math.Sin(latitude)
+	return math.Log(math.Tan(math.Pi/4+latitude/2)) - m.esOverTwo*math.Log((1.0+esSin)/(1.0-esSin))
+}
+
+// Parameters returns the ellipsoid and Mercator projection parameters.
+func (m *Mercator) Parameters() ProjectionParameters {
+	return ProjectionParameters{
+		SemiMajorAxis:    m.semiMajorAxis,
+		Flattening:       m.flattening,
+		CentralMeridian:  m.mercOriginLong,
+		StandardParallel: m.mercStdParallel,
+		FalseEasting:     m.mercFalseEasting,
+		FalseNorthing:    m.mercFalseNorthing,
+		ScaleFactor:      m.mercScaleFactor,
+	}
+}
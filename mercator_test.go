@@ -0,0 +1,105 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestMercator(t *testing.T) {
+	bessel, _ := coordconv.EllipsoidByCode("BR")
+	krassovsky, _ := coordconv.EllipsoidByCode("KA")
+
+	// EPSG Guidance Note 7-2, Makassar / NEIEZ
+	variantA, err := coordconv.NewMercatorScaleFactorEllipsoid(bessel, 110*math.Pi/180, 0.997, 3900000, 900000)
+	if err != nil {
+		t.Fatalf("error creating mercator converter: %s", err)
+	}
+	// EPSG Guidance Note 7-2, Pulkovo 1942 / Caspian Sea Mercator
+	variantB, err := coordconv.NewMercatorEllipsoid(krassovsky, 51*math.Pi/180, 42*math.Pi/180, 0, 0)
+	if err != nil {
+		t.Fatalf("error creating mercator converter: %s", err)
+	}
+
+	testCases := []struct {
+		name       string
+		projection *coordconv.Mercator
+		geo        s2.LatLng
+		expected   coordconv.MapCoords
+	}{
+		{
+			name:       "variant a",
+			projection: variantA,
+			geo:        s2.LatLngFromDegrees(-3, 120),
+			expected:   coordconv.MapCoords{Easting: 5009726.58, Northing: 569150.82},
+		},
+		{
+			name:       "variant b",
+			projection: variantB,
+			geo:        s2.LatLngFromDegrees(53, 53),
+			expected:   coordconv.MapCoords{Easting: 165704.29, Northing: 5171848.07},
+		},
+	}
+
+	const epsilon = 0.01
+	for _, tc := range testCases {
+		mc, err := tc.projection.ConvertFromGeodetic(tc.geo)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if math.Abs(mc.Easting-tc.expected.Easting) > epsilon ||
+			math.Abs(mc.Northing-tc.expected.Northing) > epsilon {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, mc)
+		}
+
+		geo, err := tc.projection.ConvertToGeodetic(tc.expected)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if geo.Distance(tc.geo).Radians()*ellipsoidSemiMajorAxis > epsilon {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.geo, geo)
+		}
+	}
+
+	// true scale along the standard parallel in both hemispheres
+	for _, lat := range []float64{-42, 42} {
+		_, factors, err := variantB.ConvertFromGeodeticFactors(s2.LatLngFromDegrees(lat, 10))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if math.Abs(factors.PointScale-1) > 1e-12 {
+			t.Errorf("expected a point scale of 1 at the standard parallel, got %f", factors.PointScale)
+		}
+	}
+	if _, err := variantB.ConvertFromGeodetic(s2.LatLngFromDegrees(90, 0)); err == nil {
+		t.Errorf("expected an error projecting the pole")
+	}
+}
+
+func TestMercatorRoundTrip(t *testing.T) {
+	m, err := coordconv.NewMercatorScaleFactor(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		-100*math.Pi/180, 0.9996, 500000, 10000000)
+	if err != nil {
+		t.Fatalf("error creating mercator converter: %s", err)
+	}
+	for lng := -180.0; lng < 180; lng += 10 {
+		for lat := -89.0; lat <= 89; lat += 4 {
+			geo := s2.LatLngFromDegrees(lat, lng)
+			mc, factors, err := m.ConvertFromGeodeticFactors(geo)
+			if err != nil {
+				t.Fatalf("expected no error at %s, got %s", geo, err)
+			}
+			geo2, err := m.ConvertToGeodetic(mc)
+			if err != nil {
+				t.Fatalf("expected no error in round trip at %s, got %s", geo, err)
+			}
+			if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > 1e-3 {
+				t.Errorf("expected %s, got %s", geo, geo2)
+			}
+			checkGridFactors(t, "mercator", m.ConvertFromGeodetic,
+				ellipsoidSemiMajorAxis, ellipsoidFlattening, geo, factors)
+		}
+	}
+}
@@ -17,7 +17,24 @@ type MGRS struct {
 	flattening    float64
 	ups           *UPS
 	utm           *UTM
+	geocentric    *Geocentric
 	ellipsoidCode string
+	datum         Datum
+	usngPattern   bool // Letter with the AA pattern whatever the ellipsoid, as USNG does
+}
+
+// MGRSCoord is an MGRS coordinate broken down into its component parts.  The
+// letters are upper case ASCII and the easting and northing are the offsets in
+// meters of the south west corner of the cell within the 100,000 meter
+// square, truncated to the precision.
+type MGRSCoord struct {
+	Zone      int     // UTM zone, 1 to 60, or 0 in the UPS polar regions
+	Band      byte    // Latitude band letter, or the UPS hemisphere letter A, B, Y or Z
+	Column    byte    // 100,000 meter square column letter
+	Row       byte    // 100,000 meter square row letter
+	Easting   float64 // Easting within the 100,000 meter square
+	Northing  float64 // Northing within the 100,000 meter square
+	Precision int     // Digits of each of the easting and northing, 0 to 5
 }
 
 const espilon2 = 4.99e-4
@@ -123,6 +140,7 @@ func NewMGRS(ellipsoidSemiMajorAxis, ellipsoidFlattening float64,
 	m.semiMajorAxis = ellipsoidSemiMajorAxis
 	m.flattening = ellipsoidFlattening
 	m.ellipsoidCode = ellipsoidCode
+	m.datum = ellipsoidDatum(ellipsoidSemiMajorAxis, ellipsoidFlattening, ellipsoidCode)
 
 	var err error
 	m.ups, err = NewUPS(m.semiMajorAxis, m.flattening)
@@ -134,28 +152,75 @@ func NewMGRS(ellipsoidSemiMajorAxis, ellipsoidFlattening float64,
 	if err != nil {
 		return nil, err
 	}
+
+	m.geocentric, err = NewGeocentric(m.semiMajorAxis, m.flattening)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// NewMGRSEllipsoid constructs an MGRS converter for the given ellipsoid.
+func NewMGRSEllipsoid(ellipsoid Ellipsoid) (*MGRS, error) {
+	m, err := NewMGRS(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, ellipsoid.Code)
+	if err != nil {
+		return nil, err
+	}
+	m.datum.Ellipsoid = ellipsoid
+	m.utm.datum.Ellipsoid = ellipsoid
+	m.ups.datum.Ellipsoid = ellipsoid
+	return m, nil
+}
+
+// NewMGRSDatum constructs an MGRS converter for coordinates on the given
+// datum.
+func NewMGRSDatum(datum Datum) (*MGRS, error) {
+	m, err := NewMGRSEllipsoid(datum.Ellipsoid)
+	if err != nil {
+		return nil, err
+	}
+	m.datum = datum
+	m.utm.datum = datum
+	m.ups.datum = datum
 	return m, nil
 }
 
+// Datum returns the datum of the converter's MGRS coordinates.  Converters
+// constructed from ellipsoid parameters alone are treated as having no shift
+// to WGS84.
+func (m *MGRS) Datum() Datum {
+	return m.datum
+}
+
 // ConvertFromGeodetic converts Geodetic (latitude and longitude) coordinates to
 // an MGRS coordinate string, according to the current ellipsoid parameters.
 func (m *MGRS) ConvertFromGeodetic(geodeticCoordinates s2.LatLng, precision int) (string, error) {
+	mgrsCoordinates, err := m.ConvertFromGeodeticCoord(geodeticCoordinates, precision)
+	if err != nil {
+		return "", err
+	}
+	return mgrsCoordinates.format()
+}
+
+// ConvertFromGeodeticCoord converts Geodetic (latitude and longitude)
+// coordinates to MGRS coordinates, according to the current ellipsoid
+// parameters.
+func (m *MGRS) ConvertFromGeodeticCoord(geodeticCoordinates s2.LatLng, precision int) (MGRSCoord, error) {
 	latitude := geodeticCoordinates.Lat.Radians()
 	longitude := geodeticCoordinates.Lng.Radians()
 
-	if (latitude < -math.Pi/2) ||
-		(latitude > math.Pi/2) {
-		return "", errors.New("latitude out of range")
+	if !(latitude >= -math.Pi/2) ||
+		!(latitude <= math.Pi/2) {
+		return MGRSCoord{}, errors.New("latitude out of range")
 	}
-	if (longitude < (-math.Pi - epsilonRadians)) ||
-		(longitude > (2*math.Pi + epsilonRadians)) {
-		return "", errors.New("longitude out of range")
+	if !(longitude >= (-math.Pi - epsilonRadians)) ||
+		!(longitude <= (2*math.Pi + epsilonRadians)) {
+		return MGRSCoord{}, errors.New("longitude out of range")
 	}
 	if (precision < 0) || (precision > mgrsMaxPrecision) {
-		return "", errors.New("precision out of range")
+		return MGRSCoord{}, errors.New("precision out of range")
 	}
 
-	var mgrsCoords string
 	// If the latitude is within the valid mgrs non polar range [-80, 84),
 	// convert to mgrs using the utm path,
 	// otherwise convert to mgrs using the ups path
@@ -163,86 +228,78 @@ func (m *MGRS) ConvertFromGeodetic(geodeticCoordinates s2.LatLng, precision int)
 		(latitude < maxMGRSNonPolarLat+epsilonRadians) {
 		utmCoordinates, err := m.utm.ConvertFromGeodetic(geodeticCoordinates, 0)
 		if err != nil {
-			return "", err
-		}
-		mgrsCoords, err = m.fromUTM(utmCoordinates, longitude, latitude, precision)
-		if err != nil {
-			return "", err
+			return MGRSCoord{}, err
 		}
-	} else {
+		return m.fromUTM(utmCoordinates, longitude, latitude, precision)
+	}
 
-		upsCoordinates, err := m.ups.ConvertFromGeodetic(geodeticCoordinates)
-		if err != nil {
-			return "", err
-		}
-		mgrsCoords, err = m.fromUPS(upsCoordinates, precision)
-		if err != nil {
-			return "", err
-		}
+	upsCoordinates, err := m.ups.ConvertFromGeodetic(geodeticCoordinates)
+	if err != nil {
+		return MGRSCoord{}, err
 	}
-	return mgrsCoords, nil
+	return m.fromUPS(upsCoordinates, precision)
 }
 
 // ConvertFromUTM converts UTM (zone, easting, and northing) coordinates to an
 // MGRS coordinate string, according to the current ellipsoid parameters.  If
 // any errors occur, an exception is thrown with a description of the error.
 func (m *MGRS) ConvertFromUTM(utmCoordinates UTMCoord, precision int) (string, error) {
+	mgrsCoordinates, err := m.ConvertFromUTMCoord(utmCoordinates, precision)
+	if err != nil {
+		return "", err
+	}
+	return mgrsCoordinates.format()
+}
+
+// ConvertFromUTMCoord converts UTM (zone, easting, and northing) coordinates
+// to MGRS coordinates, according to the current ellipsoid parameters.
+func (m *MGRS) ConvertFromUTMCoord(utmCoordinates UTMCoord, precision int) (MGRSCoord, error) {
 	zone := utmCoordinates.Zone
 	hemisphere := utmCoordinates.Hemisphere
 	easting := utmCoordinates.Easting
 	northing := utmCoordinates.Northing
 
 	if (zone < 1) || (zone > 60) {
-		return "", errors.New("zone out of range")
+		return MGRSCoord{}, errors.New("zone out of range")
 	}
 	if (hemisphere != HemisphereSouth) && (hemisphere != HemisphereNorth) {
-		return "", errors.New("hemisphere out of range")
+		return MGRSCoord{}, errors.New("hemisphere out of range")
 	}
 	if (easting < mgrsMinEasting) || (easting > mgrsMaxEasting) {
-		return "", errors.New("esating out of range")
+		return MGRSCoord{}, errors.New("esating out of range")
 	}
 	if (northing < mgrsMinNorthing) || (northing > mgrsMaxNorthing) {
-		return "", errors.New("northing out of range")
+		return MGRSCoord{}, errors.New("northing out of range")
 	}
 	if (precision < 0) || (precision > mgrsMaxPrecision) {
-		return "", errors.New("precision out of range")
+		return MGRSCoord{}, errors.New("precision out of range")
 	}
 
 	geodeticCoordinates, err := m.utm.ConvertToGeodetic(utmCoordinates)
 	if err != nil {
-		return "", err
+		return MGRSCoord{}, err
 	}
 
 	// If the latitude is within the valid mgrs non polar range [-80, 84),
 	// convert to mgrs using the utm path,
 	// otherwise convert to mgrs using the ups path
 	latitude := geodeticCoordinates.Lat.Radians()
-
-	var mgrsCoord string
 	if (latitude >= (minMGRSNonPolarLat - epsilonRadians)) &&
 		(latitude < (maxMGRSNonPolarLat + epsilonRadians)) {
-		mgrsCoord, err = m.fromUTM(utmCoordinates, geodeticCoordinates.Lng.Radians(),
+		return m.fromUTM(utmCoordinates, geodeticCoordinates.Lng.Radians(),
 			latitude, precision)
-		if err != nil {
-			return "", err
-		}
-	} else {
-		upsCoordinates, err := m.ups.ConvertFromGeodetic(geodeticCoordinates)
-		if err != nil {
-			return "", err
-		}
-		mgrsCoord, err = m.fromUPS(upsCoordinates, precision)
-		if err != nil {
-			return "", err
-		}
 	}
 
-	return mgrsCoord, nil
+	upsCoordinates, err := m.ups.ConvertFromGeodetic(geodeticCoordinates)
+	if err != nil {
+		return MGRSCoord{}, err
+	}
+	return m.fromUPS(upsCoordinates, precision)
 }
 
 // fromUPS converts UPS (hemisphere, easting, and northing) coordinates to an
-// MGRS coordinate string according to the current ellipsoid parameters.
-func (m *MGRS) fromUPS(upsCoordinates UPSCoord, precision int) (string, error) {
+// MGRS coordinate according to the current ellipsoid parameters.
+func (m *MGRS) fromUPS(upsCoordinates UPSCoord, precision int) (MGRSCoord, error) {
 	hemisphere := upsCoordinates.Hemisphere
 	easting := upsCoordinates.Easting
 	northing := upsCoordinates.Northing
@@ -314,17 +371,12 @@ func (m *MGRS) fromUPS(upsCoordinates UPSCoord, precision int) (string, error) {
 		}
 	}
 
-	MGRSString, err := makeMGRSString(0, letters[:], easting, northing, precision)
-	if err != nil {
-		return "", err
-	}
-
-	return MGRSString, nil
+	return newMGRSCoord(0, letters[:], easting, northing, precision), nil
 }
 
-// fromUTM calculates an MGRS coordinate string based on the zone, latitude,
-// easting and northing.
-func (m *MGRS) fromUTM(utmCoordinates UTMCoord, longitude, latitude float64, precision int) (string, error) {
+// fromUTM calculates an MGRS coordinate based on the zone, latitude, easting
+// and northing.
+func (m *MGRS) fromUTM(utmCoordinates UTMCoord, longitude, latitude float64, precision int) (MGRSCoord, error) {
 	var letters [3]byte
 	zone := utmCoordinates.Zone
 	easting := utmCoordinates.Easting
@@ -333,7 +385,7 @@ func (m *MGRS) fromUTM(utmCoordinates UTMCoord, longitude, latitude float64, pre
 	var err error
 	letters[0], err = getLatitudeLetter(latitude)
 	if err != nil {
-		return "", nil
+		return MGRSCoord{}, err
 	}
 
 	const Lat6 = (6.0 * (math.Pi / 180.0))
@@ -353,12 +405,12 @@ func (m *MGRS) fromUTM(utmCoordinates UTMCoord, longitude, latitude float64, pre
 	if zone != naturalZone { // reconvert to override zone
 		utmOverride, err := NewUTM2(m.semiMajorAxis, m.flattening, m.ellipsoidCode, naturalZone)
 		if err != nil {
-			return "", err
+			return MGRSCoord{}, err
 		}
 		geodeticCoordinates := s2.LatLng{Lng: s1.Angle(longitude), Lat: s1.Angle(latitude)}
 		utmCoordinatesOverride, err := utmOverride.ConvertFromGeodetic(geodeticCoordinates, 0)
 		if err != nil {
-			return "", err
+			return MGRSCoord{}, err
 		}
 		zone = utmCoordinatesOverride.Zone
 		easting = utmCoordinatesOverride.Easting
@@ -394,12 +446,12 @@ func (m *MGRS) fromUTM(utmCoordinates UTMCoord, longitude, latitude float64, pre
 	if override != 0 { // reconvert to override zone
 		utmOverride, err := NewUTM2(m.semiMajorAxis, m.flattening, m.ellipsoidCode, override)
 		if err != nil {
-			return "", err
+			return MGRSCoord{}, err
 		}
 		geodeticCoordinates := s2.LatLng{Lng: s1.Angle(longitude), Lat: s1.Angle(latitude)}
 		utmCoordinatesOverride, err := utmOverride.ConvertFromGeodetic(geodeticCoordinates, 0)
 		if err != nil {
-			return "", err
+			return MGRSCoord{}, err
 		}
 
 		zone = utmCoordinatesOverride.Zone
@@ -444,11 +496,7 @@ func (m *MGRS) fromUTM(utmCoordinates UTMCoord, longitude, latitude float64, pre
 		letters[1] = letters[1] + 1
 	}
 
-	MGRSString, err := makeMGRSString(zone, letters[:], easting, northing, precision)
-	if err != nil {
-		return "", err
-	}
-	return MGRSString, nil
+	return newMGRSCoord(zone, letters[:], easting, northing, precision), nil
 }
 
 func computeScale(prec int) float64 {
@@ -523,14 +571,14 @@ func breakMGRSString(MGRSString string) (zone int, letters []byte,
 	}
 	tempMGRSString := buf.String()
 	i := 0
-	for isdigit(tempMGRSString[i]) {
+	for i < len(tempMGRSString) && isdigit(tempMGRSString[i]) {
 		i++
 	}
 	numDigits := i
 	if numDigits <= 2 {
 		if numDigits > 0 {
 			// get zone
-			zoneString := tempMGRSString[:2]
+			zoneString := tempMGRSString[:numDigits]
 			fmt.Sscanf(zoneString, "%d", &zone)
 			if (zone < 1) || (zone > 60) {
 				err = errors.New("zone out of range")
@@ -597,9 +645,82 @@ func breakMGRSString(MGRSString string) (zone int, letters []byte,
 		err = errors.New("wrong number of digits")
 		return
 	}
+	if i != len(tempMGRSString) {
+		err = errors.New("invalid character")
+		return
+	}
 	return
 }
 
+// ParseMGRS breaks down an MGRS coordinate string into its component parts.
+func ParseMGRS(mgrsString string) (MGRSCoord, error) {
+	zone, letters, easting, northing, precision, err := breakMGRSString(mgrsString)
+	if err != nil {
+		return MGRSCoord{}, err
+	}
+	return newMGRSCoord(zone, letters, easting, northing, precision), nil
+}
+
+// String returns the MGRS coordinate string, or an empty string if the
+// letters aren't valid.
+func (c MGRSCoord) String() string {
+	mgrsString, err := c.format()
+	if err != nil {
+		return ""
+	}
+	return mgrsString
+}
+
+// format returns the MGRS coordinate string, or an error if the coordinates
+// aren't valid.
+func (c MGRSCoord) format() (string, error) {
+	zone, letters, easting, northing, precision, err := c.parts()
+	if err != nil {
+		return "", err
+	}
+	return makeMGRSString(zone, letters, easting, northing, precision)
+}
+
+// newMGRSCoord constructs an MGRSCoord from the zone, the letter indices and
+// the easting and northing, which may include the 100,000 meter square.
+func newMGRSCoord(zone int, letters []byte, easting, northing float64, precision int) MGRSCoord {
+	return MGRSCoord{
+		Zone:      zone,
+		Band:      'A' + letters[0],
+		Column:    'A' + letters[1],
+		Row:       'A' + letters[2],
+		Easting:   math.Mod(easting, 100000.0),
+		Northing:  math.Mod(northing, 100000.0),
+		Precision: precision,
+	}
+}
+
+// parts validates the MGRS coordinate and returns its component parts with the
+// letters as indices, as used by breakMGRSString and makeMGRSString.
+func (c MGRSCoord) parts() (zone int, letters []byte, easting, northing float64, precision int, err error) {
+	if (c.Zone < 0) || (c.Zone > 60) {
+		return 0, nil, 0, 0, 0, errors.New("zone out of range")
+	}
+	letters = make([]byte, 3)
+	for i, l := range [3]byte{c.Band, c.Column, c.Row} {
+		l = toupper(l)
+		if !isalpha(l) || (l == 'I') || (l == 'O') {
+			return 0, nil, 0, 0, 0, errors.New("invalid letters")
+		}
+		letters[i] = l - 'A'
+	}
+	if (c.Precision < 0) || (c.Precision > mgrsMaxPrecision) {
+		return 0, nil, 0, 0, 0, errors.New("precision out of range")
+	}
+	if !(c.Easting >= 0) || (c.Easting >= 100000) {
+		return 0, nil, 0, 0, 0, errors.New("easting out of range")
+	}
+	if !(c.Northing >= 0) || (c.Northing >= 100000) {
+		return 0, nil, 0, 0, 0, errors.New("northing out of range")
+	}
+	return c.Zone, letters, c.Easting, c.Northing, c.Precision, nil
+}
+
 // getGridValues sets the letter range used for the 2nd letter in the MGRS
 // coordinate string, based on the set number of the utm zone. It also sets the
 // pattern offset using a value of A for the second letter of the grid square,
@@ -613,10 +734,10 @@ func (m *MGRS) getGridValues(zone int) (ltr2LowValue, ltr2HighValue int, pattern
 
 	// Pattern based on ellipsoid code
 	var aaPattern bool
-	if m.ellipsoidCode == clarke1886 ||
+	if !m.usngPattern && (m.ellipsoidCode == clarke1886 ||
 		m.ellipsoidCode == clarke1880 ||
 		m.ellipsoidCode == bessel1841 ||
-		m.ellipsoidCode == bessel1841Namibia {
+		m.ellipsoidCode == bessel1841Namibia) {
 		aaPattern = false
 	} else {
 		aaPattern = true
@@ -653,32 +774,116 @@ func (m *MGRS) getGridValues(zone int) (ltr2LowValue, ltr2HighValue int, pattern
 // ConvertToGeodetic converts an MGRS coordinate string to Geodetic (latitude
 // and longitude) coordinates according to the current ellipsoid parameters.
 func (m *MGRS) ConvertToGeodetic(mgrsorUSNGCoordinates string) (s2.LatLng, error) {
-	zone, letters, mgrsEasting, mgrsNorthing, precision, err := breakMGRSString(mgrsorUSNGCoordinates)
+	mgrsCoordinates, err := ParseMGRS(mgrsorUSNGCoordinates)
+	if err != nil {
+		return s2.LatLng{}, err
+	}
+	return m.ConvertToGeodeticCoord(mgrsCoordinates)
+}
+
+// ConvertToGeodeticCoord converts MGRS coordinates to Geodetic (latitude and
+// longitude) coordinates according to the current ellipsoid parameters.
+func (m *MGRS) ConvertToGeodeticCoord(mgrsCoordinates MGRSCoord) (s2.LatLng, error) {
+	zone, letters, mgrsEasting, mgrsNorthing, precision, err := mgrsCoordinates.parts()
 	if err != nil {
 		return s2.LatLng{}, err
 	}
-	var geodeticCoordinates s2.LatLng
 	if zone != 0 {
 		utmCoordinates, err := m.toUTM(zone, letters, mgrsEasting, mgrsNorthing, precision)
 		if err != nil {
 			return s2.LatLng{}, err
 		}
+		return m.utm.ConvertToGeodetic(utmCoordinates)
+	}
 
-		geodeticCoordinates, err = m.utm.ConvertToGeodetic(utmCoordinates)
-		if err != nil {
-			return s2.LatLng{}, err
-		}
-	} else {
-		upsCoordinates, err := m.toUPS(letters, mgrsEasting, mgrsNorthing)
-		if err != nil {
-			return s2.LatLng{}, err
-		}
-		geodeticCoordinates, err = m.ups.ConvertToGeodetic(upsCoordinates)
-		if err != nil {
-			return s2.LatLng{}, err
-		}
+	upsCoordinates, err := m.toUPS(letters, mgrsEasting, mgrsNorthing)
+	if err != nil {
+		return s2.LatLng{}, err
+	}
+	return m.ups.ConvertToGeodetic(upsCoordinates)
+}
+
+// ConvertFromGeocentric converts earth centered, earth fixed coordinates to an
+// MGRS coordinate string, according to the current ellipsoid parameters.  The
+// height of the point above the ellipsoid is discarded.
+func (m *MGRS) ConvertFromGeocentric(geocentricCoordinates GeocentricCoord, precision int) (string, error) {
+	geodeticCoordinates, _, err := m.geocentric.ConvertToGeodetic(geocentricCoordinates)
+	if err != nil {
+		return "", err
+	}
+	return m.ConvertFromGeodetic(geodeticCoordinates, precision)
+}
+
+// ConvertToGeocentric converts an MGRS coordinate string to earth centered,
+// earth fixed coordinates at the given height above the ellipsoid, according
+// to the current ellipsoid parameters.
+func (m *MGRS) ConvertToGeocentric(mgrsorUSNGCoordinates string, height float64) (GeocentricCoord, error) {
+	geodeticCoordinates, err := m.ConvertToGeodetic(mgrsorUSNGCoordinates)
+	if err != nil {
+		return GeocentricCoord{}, err
+	}
+	return m.geocentric.ConvertFromGeodetic(geodeticCoordinates, height)
+}
+
+// ConvertFromGeodeticDatum converts geodetic coordinates on the given datum
+// to an MGRS coordinate string on the converter's datum.
+func (m *MGRS) ConvertFromGeodeticDatum(geodeticCoordinates s2.LatLng, datum Datum, precision int) (string, error) {
+	geodeticCoordinates, err := shiftDatum(geodeticCoordinates, datum, m.datum)
+	if err != nil {
+		return "", err
+	}
+	return m.ConvertFromGeodetic(geodeticCoordinates, precision)
+}
+
+// ConvertToGeodeticDatum converts an MGRS coordinate string on the
+// converter's datum to geodetic coordinates on the given datum.
+func (m *MGRS) ConvertToGeodeticDatum(mgrsorUSNGCoordinates string, datum Datum) (s2.LatLng, error) {
+	geodeticCoordinates, err := m.ConvertToGeodetic(mgrsorUSNGCoordinates)
+	if err != nil {
+		return s2.LatLng{}, err
+	}
+	return shiftDatum(geodeticCoordinates, m.datum, datum)
+}
+
+// ConvertFromUTMDatum converts UTM coordinates on the datum of the given UTM
+// converter, e.g. NAD27, to an MGRS coordinate string on the converter's
+// datum.
+func (m *MGRS) ConvertFromUTMDatum(utmCoordinates UTMCoord, utm *UTM, precision int) (string, error) {
+	geodeticCoordinates, err := utm.ConvertToGeodetic(utmCoordinates)
+	if err != nil {
+		return "", err
+	}
+	return m.ConvertFromGeodeticDatum(geodeticCoordinates, utm.datum, precision)
+}
+
+// ConvertFromUPSDatum converts UPS coordinates on the datum of the given UPS
+// converter to an MGRS coordinate string on the converter's datum.
+func (m *MGRS) ConvertFromUPSDatum(upsCoordinates UPSCoord, ups *UPS, precision int) (string, error) {
+	geodeticCoordinates, err := ups.ConvertToGeodetic(upsCoordinates)
+	if err != nil {
+		return "", err
+	}
+	return m.ConvertFromGeodeticDatum(geodeticCoordinates, ups.datum, precision)
+}
+
+// ConvertFromGeodeticGridShift converts geodetic coordinates on the grid
+// shift's source datum to an MGRS coordinate string on its target datum.
+func (m *MGRS) ConvertFromGeodeticGridShift(geodeticCoordinates s2.LatLng, gridShift *GridShift, precision int) (string, error) {
+	geodeticCoordinates, err := gridShift.Forward(geodeticCoordinates)
+	if err != nil {
+		return "", err
+	}
+	return m.ConvertFromGeodetic(geodeticCoordinates, precision)
+}
+
+// ConvertToGeodeticGridShift converts an MGRS coordinate string on the grid
+// shift's target datum to geodetic coordinates on its source datum.
+func (m *MGRS) ConvertToGeodeticGridShift(mgrsorUSNGCoordinates string, gridShift *GridShift) (s2.LatLng, error) {
+	geodeticCoordinates, err := m.ConvertToGeodetic(mgrsorUSNGCoordinates)
+	if err != nil {
+		return s2.LatLng{}, err
 	}
-	return geodeticCoordinates, err
+	return gridShift.Inverse(geodeticCoordinates)
 }
 
 // toUTM converts an MGRS coordinate string to UTM projection (zone, hemisphere,
@@ -0,0 +1,409 @@
+package coordconv
+
+import (
+	"errors"
+	"math"
+
+	"github.com/golang/geo/r1"
+	"github.com/golang/geo/r2"
+	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// mgrsCellEdgeSegments is the number of segments each side of an MGRS cell is
+// divided into, so that the geodesic edges of its footprint follow the grid
+// lines.
+const mgrsCellEdgeSegments = 8
+
+// mgrsCellParallelStep is the longest step in longitude along a parallel
+// bounding the footprint of an MGRS cell, which keeps the geodesic edges
+// within a few centimeters of the parallel.
+const mgrsCellParallelStep = 0.02 * math.Pi / 180
+
+// mgrsCellMinArea is the least area in square meters of a cell's footprint,
+// the square of the espilon2 that conversions to MGRS round up by.  A square
+// which only touches its grid zone designation, such as one rounded across
+// the equator, names no cell.
+const mgrsCellMinArea = espilon2 * espilon2
+
+// MGRSCell is the area named by an MGRS coordinate at its precision: a square
+// of the UTM or UPS grid, clipped to its grid zone designation.
+type MGRSCell struct {
+	Coord   MGRSCoord
+	Center  s2.LatLng    // Centre of the square, or of the footprint if the centre is clipped off
+	Corners [4]s2.LatLng // South west, south east, north east and north west corners of the square
+	Loop    *s2.Loop     // Footprint, clipped at the zone and latitude band boundaries
+}
+
+// Rect returns the latitude and longitude bounds of the cell's footprint.
+func (c MGRSCell) Rect() s2.Rect {
+	return c.Loop.RectBound()
+}
+
+// ConvertToCell converts an MGRS coordinate string to the cell it names,
+// according to the current ellipsoid parameters.
+func (m *MGRS) ConvertToCell(mgrsorUSNGCoordinates string) (MGRSCell, error) {
+	mgrsCoordinates, err := ParseMGRS(mgrsorUSNGCoordinates)
+	if err != nil {
+		return MGRSCell{}, err
+	}
+	return m.ConvertToCellCoord(mgrsCoordinates)
+}
+
+// ConvertToCellCoord converts MGRS coordinates to the cell they name,
+// according to the current ellipsoid parameters.
+func (m *MGRS) ConvertToCellCoord(mgrsCoordinates MGRSCoord) (MGRSCell, error) {
+	zone, letters, easting, northing, precision, err := mgrsCoordinates.parts()
+	if err != nil {
+		return MGRSCell{}, err
+	}
+	gridZone, err := m.gridZone(zone, letters[0])
+	if err != nil {
+		return MGRSCell{}, err
+	}
+	if zone != 0 {
+		utmCoordinates, err := m.toUTM(zone, letters, easting, northing, precision)
+		if err != nil {
+			return MGRSCell{}, err
+		}
+		easting = utmCoordinates.Easting
+		northing = utmCoordinates.Northing
+	} else {
+		upsCoordinates, err := m.toUPS(letters, easting, northing)
+		if err != nil {
+			return MGRSCell{}, err
+		}
+		easting = upsCoordinates.Easting
+		northing = upsCoordinates.Northing
+	}
+
+	size := computeScale(precision)
+	cell := MGRSCell{Coord: mgrsCoordinates}
+	corners := [4]r2.Point{
+		{X: easting, Y: northing},
+		{X: easting + size, Y: northing},
+		{X: easting + size, Y: northing + size},
+		{X: easting, Y: northing + size},
+	}
+	for i, corner := range corners {
+		if cell.Corners[i], err = gridZone.toGeodetic(corner); err != nil {
+			return MGRSCell{}, err
+		}
+	}
+
+	ring, err := gridZone.trace(gridSquare(corners), true)
+	if err != nil {
+		return MGRSCell{}, err
+	}
+	clipped := clipRing(ring, gridZone.bound)
+	if m.footprintArea(clipped) < mgrsCellMinArea {
+		// Conversions to MGRS take the UTM path to epsilonRadians beyond 84
+		// and -80 degrees, so a cell may lie just outside its grid zone
+		// designation
+		clipped = clipRing(ring, gridZone.utmPathBound())
+	}
+	if m.footprintArea(clipped) < mgrsCellMinArea {
+		return MGRSCell{}, errors.New("MGRS cell is outside its grid zone")
+	}
+	points := gridZone.points(clipped, true)
+	cell.Loop = s2.LoopFromPoints(points)
+
+	if cell.Center, err = gridZone.toGeodetic(r2.Point{X: easting + size/2, Y: northing + size/2}); err != nil {
+		return MGRSCell{}, err
+	}
+	if !cell.Loop.ContainsPoint(s2.PointFromLatLng(cell.Center)) {
+		// the mean of the vertices, as the centroid of a footprint of a few
+		// centimeters is lost to rounding
+		var sum r3.Vector
+		for _, p := range points {
+			sum = sum.Add(p.Vector)
+		}
+		cell.Center = s2.LatLngFromPoint(s2.Point{Vector: sum.Normalize()})
+	}
+	return cell, nil
+}
+
+// mgrsGridZone is the UTM or UPS grid of a grid zone designation.  It is
+// projected without the UTM and UPS latitude limits, as the squares at the
+// edges of the grid zones extend beyond them.
+type mgrsGridZone struct {
+	zone          int
+	letter        byte
+	bound         r2.Rect    // Longitude (X) and latitude (Y) bounds in radians
+	projection    Projection // Transverse Mercator or Polar Stereographic projection of the grid
+	falseNorthing float64    // Northing of the projection's origin on the grid
+	pole          r2.Point   // Grid coordinates of the pole, if any
+}
+
+// gridZone returns the grid of the grid zone designation of the zone, 0 for
+// UPS, and the latitude band letter.
+func (m *MGRS) gridZone(zone int, letter byte) (mgrsGridZone, error) {
+	bound, err := gridZoneBounds(zone, letter)
+	if err != nil {
+		return mgrsGridZone{}, err
+	}
+	g := mgrsGridZone{zone: zone, letter: letter, bound: bound}
+	if zone != 0 {
+		g.projection = m.utm.transverseMercatorMap[zone]
+		if letter < letterN {
+			g.falseNorthing = 10000000
+		}
+		g.pole = r2.Point{X: math.NaN(), Y: math.NaN()}
+	} else {
+		if letter <= letterB {
+			g.projection = m.ups.polarStereographic(HemisphereSouth)
+		} else {
+			g.projection = m.ups.polarStereographic(HemisphereNorth)
+		}
+		g.pole = r2.Point{X: upsFalseEasting, Y: upsFalseNorthing}
+	}
+	return g, nil
+}
+
+// utmPathBound returns the bound of the grid zone extended to where
+// conversions to MGRS take the UTM path, epsilonRadians beyond 84 and -80
+// degrees.
+func (g mgrsGridZone) utmPathBound() r2.Rect {
+	bound := g.bound
+	if g.zone != 0 {
+		if g.letter == letterX {
+			bound.Y.Hi += epsilonRadians
+		} else if g.letter == letterC {
+			bound.Y.Lo -= epsilonRadians
+		}
+	}
+	return bound
+}
+
+// footprintArea returns the approximate area in square meters of a ring of
+// longitudes and latitudes in radians.
+func (m *MGRS) footprintArea(ring []r2.Point) float64 {
+	if len(ring) < 3 {
+		return 0
+	}
+	// relative to the first point, as footprints of a few centimeters are
+	// lost to rounding far from the origin
+	var area, latitude float64
+	for i, p := range ring {
+		if i+1 < len(ring) {
+			p, next := p.Sub(ring[0]), ring[i+1].Sub(ring[0])
+			area += p.X*next.Y - next.X*p.Y
+		}
+		latitude += p.Y
+	}
+	latitude /= float64(len(ring))
+	return math.Abs(area) / 2 * m.semiMajorAxis * m.semiMajorAxis * math.Cos(latitude)
+}
+
+// toGeodetic converts grid coordinates to geodetic coordinates.
+func (g mgrsGridZone) toGeodetic(p r2.Point) (s2.LatLng, error) {
+	return g.projection.ConvertToGeodetic(MapCoords{Easting: p.X, Northing: p.Y - g.falseNorthing})
+}
+
+// fromGeodetic converts geodetic coordinates to grid coordinates.
+func (g mgrsGridZone) fromGeodetic(geodeticCoordinates s2.LatLng) (r2.Point, error) {
+	mapCoords, err := g.projection.ConvertFromGeodetic(geodeticCoordinates)
+	if err != nil {
+		return r2.Point{}, err
+	}
+	return r2.Point{X: mapCoords.Easting, Y: mapCoords.Northing + g.falseNorthing}, nil
+}
+
+// trace converts a ring or a line of grid coordinates to longitudes and
+// latitudes in radians, continuous across the middle of the grid zone.  A pole
+// is spread along the parallel between the longitudes of its neighbours.
+func (g mgrsGridZone) trace(line []r2.Point, closed bool) ([]r2.Point, error) {
+	centerLongitude := g.bound.X.Center()
+	poleLatitude := math.Copysign(math.Pi/2, g.bound.Y.Center())
+	traced := make([]r2.Point, 0, len(line)+1)
+	poleIndex := -1
+	for i, p := range line {
+		if p == g.pole {
+			poleIndex = i
+			continue
+		}
+		geodeticCoordinates, err := g.toGeodetic(p)
+		if err != nil {
+			return nil, err
+		}
+		traced = append(traced, r2.Point{
+			X: centerLongitude + math.Remainder(geodeticCoordinates.Lng.Radians()-centerLongitude, 2*math.Pi),
+			Y: geodeticCoordinates.Lat.Radians(),
+		})
+	}
+	if (poleIndex < 0) || (len(traced) == 0) {
+		return traced, nil
+	}
+
+	var spread []r2.Point
+	if (poleIndex > 0) || closed {
+		previous := traced[(poleIndex+len(traced)-1)%len(traced)]
+		spread = append(spread, r2.Point{X: previous.X, Y: poleLatitude})
+	}
+	if (poleIndex < len(line)-1) || closed {
+		next := traced[poleIndex%len(traced)]
+		spread = append(spread, r2.Point{X: next.X, Y: poleLatitude})
+	}
+	return append(traced[:poleIndex], append(spread, traced[poleIndex:]...)...), nil
+}
+
+// points converts a ring or a line of longitudes and latitudes in radians to
+// points, following the parallels bounding the grid zone and dropping
+// repeated points.
+func (g mgrsGridZone) points(line []r2.Point, closed bool) []s2.Point {
+	var points []s2.Point
+	addPoint := func(p r2.Point) {
+		point := s2.PointFromLatLng(s2.LatLng{Lat: s1.Angle(p.Y), Lng: s1.Angle(p.X)})
+		if len(points) == 0 || !points[len(points)-1].ApproxEqual(point) {
+			points = append(points, point)
+		}
+	}
+	for i, p := range line {
+		addPoint(p)
+		if (i == len(line)-1) && !closed {
+			break
+		}
+		next := line[(i+1)%len(line)]
+		if (p.Y == next.Y) && ((p.Y == g.bound.Y.Lo) || (p.Y == g.bound.Y.Hi)) {
+			segments := math.Ceil(math.Abs(next.X-p.X) / mgrsCellParallelStep)
+			for j := 1.0; j < segments; j++ {
+				addPoint(r2.Point{X: p.X + (next.X-p.X)*j/segments, Y: p.Y})
+			}
+		}
+	}
+	for closed && len(points) > 1 && points[0].ApproxEqual(points[len(points)-1]) {
+		points = points[:len(points)-1]
+	}
+	return points
+}
+
+// gridSquare returns a ring around the square of grid coordinates with the
+// given corners, with each side divided into mgrsCellEdgeSegments.
+func gridSquare(corners [4]r2.Point) []r2.Point {
+	var ring []r2.Point
+	for i, corner := range corners {
+		step := corners[(i+1)%4].Sub(corner).Mul(1.0 / mgrsCellEdgeSegments)
+		for j := 0; j < mgrsCellEdgeSegments; j++ {
+			ring = append(ring, corner.Add(step.Mul(float64(j))))
+		}
+	}
+	return ring
+}
+
+// gridZoneBounds returns the longitude (X) and latitude (Y) bounds in radians
+// of the grid zone designation of the zone, 0 for UPS, and the latitude band
+// letter, including the Norway and Svalbard exceptions.
+func gridZoneBounds(zone int, letter byte) (r2.Rect, error) {
+	const deg = math.Pi / 180
+	if zone == 0 {
+		var bound r2.Rect
+		switch letter {
+		case letterA, letterB:
+			bound.Y = r1.Interval{Lo: -math.Pi / 2, Hi: minMGRSNonPolarLat}
+		case letterY, letterZ:
+			bound.Y = r1.Interval{Lo: maxMGRSNonPolarLat, Hi: math.Pi / 2}
+		default:
+			return r2.Rect{}, errors.New("invalid letters")
+		}
+		if (letter == letterA) || (letter == letterY) {
+			bound.X = r1.Interval{Lo: -math.Pi, Hi: 0}
+		} else {
+			bound.X = r1.Interval{Lo: 0, Hi: math.Pi}
+		}
+		return bound, nil
+	}
+
+	var band latitudeBand
+	if (letter >= letterC) && (letter <= letterH) {
+		band = latitudeBands[letter-2]
+	} else if (letter >= letterJ) && (letter <= letterN) {
+		band = latitudeBands[letter-3]
+	} else if (letter >= letterP) && (letter <= letterX) {
+		band = latitudeBands[letter-4]
+	} else {
+		return r2.Rect{}, errors.New("invalid letters")
+	}
+	bound := r2.Rect{
+		X: r1.Interval{Lo: float64(6*zone-186) * deg, Hi: float64(6*zone-180) * deg},
+		Y: r1.Interval{
+			Lo: math.Max(band.south*deg, minMGRSNonPolarLat),
+			Hi: math.Min(band.north*deg, maxMGRSNonPolarLat),
+		},
+	}
+
+	// UTM special cases
+	if letter == letterV {
+		if zone == 31 {
+			bound.X.Hi = 3 * deg
+		} else if zone == 32 {
+			bound.X.Lo = 3 * deg
+		}
+	} else if letter == letterX {
+		switch zone {
+		case 31:
+			bound.X = r1.Interval{Lo: 0, Hi: 9 * deg}
+		case 33:
+			bound.X = r1.Interval{Lo: 9 * deg, Hi: 21 * deg}
+		case 35:
+			bound.X = r1.Interval{Lo: 21 * deg, Hi: 33 * deg}
+		case 37:
+			bound.X = r1.Interval{Lo: 33 * deg, Hi: 42 * deg}
+		case 32, 34, 36:
+			return r2.Rect{}, errors.New("invalid letters")
+		}
+	}
+	return bound, nil
+}
+
+// clipRing clips a ring of points to a rectangle, one side at a time
+// (Sutherland-Hodgman), placing the points where it crosses exactly on the
+// side.
+func clipRing(ring []r2.Point, bound r2.Rect) []r2.Point {
+	sides := []struct {
+		y    bool    // Side is a line of constant Y rather than X
+		edge float64 // Value of X or Y along the side
+		sign float64 // 1 if the inside is above the side, -1 if below
+	}{
+		{false, bound.X.Lo, 1},
+		{false, bound.X.Hi, -1},
+		{true, bound.Y.Lo, 1},
+		{true, bound.Y.Hi, -1},
+	}
+	for _, side := range sides {
+		if len(ring) == 0 {
+			break
+		}
+		distance := func(p r2.Point) float64 {
+			if side.y {
+				return side.sign * (p.Y - side.edge)
+			}
+			return side.sign * (p.X - side.edge)
+		}
+		crossing := func(a, b r2.Point) r2.Point {
+			p := a.Add(b.Sub(a).Mul(distance(a) / (distance(a) - distance(b))))
+			if side.y {
+				p.Y = side.edge
+			} else {
+				p.X = side.edge
+			}
+			return p
+		}
+
+		var clipped []r2.Point
+		previous := ring[len(ring)-1]
+		for _, p := range ring {
+			if distance(p) >= 0 {
+				if distance(previous) < 0 {
+					clipped = append(clipped, crossing(previous, p))
+				}
+				clipped = append(clipped, p)
+			} else if distance(previous) >= 0 {
+				clipped = append(clipped, crossing(previous, p))
+			}
+			previous = p
+		}
+		ring = clipped
+	}
+	return ring
+}
@@ -0,0 +1,233 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestMGRSCell(t *testing.T) {
+	mgrs := coordconv.DefaultMGRSConverter
+	cell, err := mgrs.ConvertToCell("16SGC385248")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	sw, err := mgrs.ConvertToGeodetic("16SGC3855124838")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if d := sw.Distance(cell.Corners[0]).Radians() * ellipsoidSemiMajorAxis; d > 70 {
+		t.Errorf("expected south west corner near %s, got %s", sw, cell.Corners[0])
+	}
+	center, err := mgrs.ConvertToGeodetic("16SGC3855024850")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if d := center.Distance(cell.Center).Radians() * ellipsoidSemiMajorAxis; d > 0.01 {
+		t.Errorf("expected centre %s, got %s", center, cell.Center)
+	}
+	for i := 0; i < 4; i++ {
+		side := cell.Corners[i].Distance(cell.Corners[(i+1)%4]).Radians() * ellipsoidSemiMajorAxis
+		// on the sphere of the semi-major axis
+		if math.Abs(side-100) > 0.5 {
+			t.Errorf("expected a 100 m side, got %f m", side)
+		}
+	}
+	if area := cell.Loop.Area() * ellipsoidSemiMajorAxis * ellipsoidSemiMajorAxis; math.Abs(area-1.0e4) > 50 {
+		t.Errorf("expected an area of 10000 m^2, got %f", area)
+	}
+	if !cell.Rect().ContainsLatLng(cell.Center) {
+		t.Errorf("expected %s to contain %s", cell.Rect(), cell.Center)
+	}
+
+	// including squares of band M north of the equator, which only touch the band
+	for _, s := range []string{"16SGC1", "32XAA", "16SGZ", "CAA", "18MWF", "30MZF", "31MDA6531200000"} {
+		if _, err := mgrs.ConvertToCell(s); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
+
+func TestMGRSCellClipping(t *testing.T) {
+	mgrs := coordconv.DefaultMGRSConverter
+	const epsilon = 1e-6
+	for _, tc := range []struct {
+		name     string
+		geo      s2.LatLng
+		bound    s2.Rect // The cell is clipped to the bound
+		clipped  bool    // The bound cuts the cell
+		clipSide string
+	}{
+		{"zone edge", s2.LatLngFromDegrees(0.5, 0.2), rectFromDegrees(0, 0, 8, 6), true, "west"},
+		{"band edge", s2.LatLngFromDegrees(7.9, 3.1), rectFromDegrees(0, 0, 8, 6), true, "north"},
+		{"Norway", s2.LatLngFromDegrees(60.0, 2.9), rectFromDegrees(56, 0, 64, 3), true, "east"},
+		{"Svalbard", s2.LatLngFromDegrees(78.0, 8.9), rectFromDegrees(72, 0, 84, 9), true, "east"},
+		{"band X", s2.LatLngFromDegrees(83.9, 30.0), rectFromDegrees(72, 21, 84, 33), true, "north"},
+		{"band C", s2.LatLngFromDegrees(-79.9, -100.0), rectFromDegrees(-80, -102, -72, -96), true, "south"},
+		{"antimeridian", s2.LatLngFromDegrees(-0.5, 179.9), rectFromDegrees(-8, 174, 0, 180), true, "east"},
+		{"UPS north", s2.LatLngFromDegrees(84.05, 10), rectFromDegrees(84, 0, 90, 180), true, "south"},
+		{"UPS south", s2.LatLngFromDegrees(-80.05, -10), rectFromDegrees(-90, -180, -80, 0), true, "north"},
+		{"UPS meridian", s2.LatLngFromDegrees(89.0, -179.9), rectFromDegrees(84, -180, 90, 0), false, ""},
+	} {
+		c, err := mgrs.ConvertFromGeodeticCoord(tc.geo, 0)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		cell, err := mgrs.ConvertToCellCoord(c)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if err := cell.Loop.Validate(); err != nil {
+			t.Errorf("%s: invalid loop %s", tc.name, err)
+		}
+		if !cell.Loop.ContainsPoint(s2.PointFromLatLng(tc.geo)) {
+			t.Errorf("%s: expected %s to contain %s", tc.name, c, tc.geo)
+		}
+		if !cell.Loop.ContainsPoint(s2.PointFromLatLng(cell.Center)) {
+			t.Errorf("%s: expected %s to contain its centre %s", tc.name, c, cell.Center)
+		}
+		rect := cell.Rect()
+		if (rect.Lo().Lat.Degrees() < tc.bound.Lo().Lat.Degrees()-epsilon) ||
+			(rect.Hi().Lat.Degrees() > tc.bound.Hi().Lat.Degrees()+epsilon) ||
+			(rect.Lo().Lng.Degrees() < tc.bound.Lo().Lng.Degrees()-epsilon) ||
+			(rect.Hi().Lng.Degrees() > tc.bound.Hi().Lng.Degrees()+epsilon) {
+			t.Errorf("%s: expected %s inside %s", tc.name, rect, tc.bound)
+		}
+		if !tc.clipped {
+			continue
+		}
+		var got, expected float64
+		switch tc.clipSide {
+		case "west":
+			got, expected = rect.Lo().Lng.Degrees(), tc.bound.Lo().Lng.Degrees()
+		case "east":
+			got, expected = rect.Hi().Lng.Degrees(), tc.bound.Hi().Lng.Degrees()
+		case "south":
+			got, expected = rect.Lo().Lat.Degrees(), tc.bound.Lo().Lat.Degrees()
+		case "north":
+			got, expected = rect.Hi().Lat.Degrees(), tc.bound.Hi().Lat.Degrees()
+		}
+		if math.Abs(got-expected) > epsilon {
+			t.Errorf("%s: expected %s clipped at %f, got %f", tc.name, c, expected, got)
+		}
+	}
+}
+
+func TestMGRSCellPoles(t *testing.T) {
+	mgrs := coordconv.DefaultMGRSConverter
+	for _, lat := range []float64{89.99, -89.99} {
+		for _, lng := range []float64{-135, -45, 45, 135} {
+			geo := s2.LatLngFromDegrees(lat, lng)
+			c, err := mgrs.ConvertFromGeodeticCoord(geo, 0)
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+			cell, err := mgrs.ConvertToCellCoord(c)
+			if err != nil {
+				t.Fatalf("%s: expected no error, got %s", c, err)
+			}
+			if err := cell.Loop.Validate(); err != nil {
+				t.Errorf("%s: invalid loop %s", c, err)
+			}
+			if !cell.Loop.ContainsPoint(s2.PointFromLatLng(geo)) {
+				t.Errorf("expected %s to contain %s", c, geo)
+			}
+			// UPS has a scale of 0.994 at the pole
+			area := cell.Loop.Area() * ellipsoidSemiMajorAxis * ellipsoidSemiMajorAxis
+			if math.Abs(area/1.0e10-1/(0.994*0.994)) > 0.01 {
+				t.Errorf("%s: expected an area of 1.012e10 m^2, got %g", c, area)
+			}
+			if pole := math.Abs(cell.Rect().Hi().Lat.Degrees()) + math.Abs(cell.Rect().Lo().Lat.Degrees()); pole < 90 {
+				t.Errorf("%s: expected %s to reach the pole", c, cell.Rect())
+			}
+		}
+	}
+}
+
+func TestMGRSCellContains(t *testing.T) {
+	mgrs := coordconv.DefaultMGRSConverter
+	for lat := -89.7; lat < 90; lat += 3.7 {
+		for lng := -179.3; lng < 180; lng += 4.9 {
+			geo := s2.LatLngFromDegrees(lat, lng)
+			for precision := 0; precision <= 2; precision++ {
+				c, err := mgrs.ConvertFromGeodeticCoord(geo, precision)
+				if err != nil {
+					t.Fatalf("%s: expected no error, got %s", geo, err)
+				}
+				cell, err := mgrs.ConvertToCellCoord(c)
+				if err != nil {
+					t.Fatalf("%s: expected no error, got %s", c, err)
+				}
+				if !cell.Loop.ContainsPoint(s2.PointFromLatLng(geo)) {
+					t.Errorf("expected %s to contain %s", c, geo)
+				}
+				size := 1.0e5 / math.Pow(10, float64(precision))
+				area := cell.Loop.Area() * ellipsoidSemiMajorAxis * ellipsoidSemiMajorAxis
+				if area > 1.02*size*size {
+					t.Errorf("%s: expected an area less than %g m^2, got %g", c, size*size, area)
+				}
+			}
+		}
+	}
+}
+
+func TestMGRSCellEdges(t *testing.T) {
+	mgrs := coordconv.DefaultMGRSConverter
+	if _, err := mgrs.ConvertToCell("33XVP4172230625"); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	}
+
+	// every string converted to MGRS names a cell, including those rounded
+	// across a grid zone boundary and those on the UTM path beyond 84 and -80
+	// degrees
+	for _, offset := range []float64{-9e-6, -4e-6, -1e-6, 1e-6, 4e-6, 9e-6} {
+		for _, geo := range []s2.LatLng{
+			s2.LatLngFromDegrees(84+offset, 10),
+			s2.LatLngFromDegrees(84+offset, 4.7115),
+			s2.LatLngFromDegrees(-80+offset, -82.2102),
+			s2.LatLngFromDegrees(-80+offset, 147.7893),
+			s2.LatLngFromDegrees(40+offset, -77),
+			s2.LatLngFromDegrees(40+offset, 179.99999),
+			s2.LatLngFromDegrees(64+offset, 4),
+			s2.LatLngFromDegrees(60, 3+offset),
+			s2.LatLngFromDegrees(78, 21+offset),
+			s2.LatLngFromDegrees(45, 6+offset),
+			s2.LatLngFromDegrees(87, offset),
+		} {
+			for precision := 3; precision <= 5; precision++ {
+				s, err := mgrs.ConvertFromGeodetic(geo, precision)
+				if err != nil {
+					t.Fatalf("%s: expected no error, got %s", geo, err)
+				}
+				cell, err := mgrs.ConvertToCell(s)
+				if err != nil {
+					t.Errorf("%s: %s: expected no error, got %s", geo, s, err)
+					continue
+				}
+				if err := cell.Loop.Validate(); err != nil {
+					t.Errorf("%s: invalid loop %s", s, err)
+				}
+				if !cell.Loop.ContainsPoint(s2.PointFromLatLng(cell.Center)) {
+					t.Errorf("%s: expected the footprint to contain its centre %s", s, cell.Center)
+				}
+			}
+		}
+	}
+
+	// the centre of a footprint of a few centimeters names the cell
+	for _, s := range []string{"60SYK5609832069", "01SBE4390132069"} {
+		cell, err := mgrs.ConvertToCell(s)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", s, err)
+		}
+		if c, err := mgrs.ConvertFromGeodetic(cell.Center, 5); (err != nil) || (c != s) {
+			t.Errorf("%s: expected the centre %s to name the cell, got %s %v", s, cell.Center, c, err)
+		}
+	}
+}
+
+func rectFromDegrees(latLo, lngLo, latHi, lngHi float64) s2.Rect {
+	return s2.RectFromLatLng(s2.LatLngFromDegrees(latLo, lngLo)).AddPoint(s2.LatLngFromDegrees(latHi, lngHi))
+}
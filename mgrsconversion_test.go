@@ -154,3 +154,116 @@ func fuzzMGRS(data []byte) int {
 	}
 	return 1
 }
+
+func TestParseMGRS(t *testing.T) {
+	for _, tc := range []struct {
+		mgrs     string
+		expected coordconv.MGRSCoord
+	}{
+		{"16SGC3855124838", coordconv.MGRSCoord{Zone: 16, Band: 'S', Column: 'G', Row: 'C', Easting: 38551, Northing: 24838, Precision: 5}},
+		{"31NAA660021", coordconv.MGRSCoord{Zone: 31, Band: 'N', Column: 'A', Row: 'A', Easting: 66000, Northing: 2100, Precision: 3}},
+		{"04QFJ", coordconv.MGRSCoord{Zone: 4, Band: 'Q', Column: 'F', Row: 'J'}},
+		{"ZGC1234", coordconv.MGRSCoord{Band: 'Z', Column: 'G', Row: 'C', Easting: 12000, Northing: 34000, Precision: 2}},
+	} {
+		got, err := coordconv.ParseMGRS(tc.mgrs)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.mgrs, err)
+		}
+		if got != tc.expected {
+			t.Errorf("%s: expected %+v, got %+v", tc.mgrs, tc.expected, got)
+		}
+		if got.String() != tc.mgrs {
+			t.Errorf("expected %s, got %s", tc.mgrs, got.String())
+		}
+	}
+
+	for _, s := range []string{"16SGI3855124838", "61SGC3855124838", "16SGC385512483", "16SG3855124838",
+		"18SUJ2348006470junk", "18SUJ2348006470A", "18SUJ23480064701234"} {
+		if _, err := coordconv.ParseMGRS(s); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
+
+func TestMGRSCoordString(t *testing.T) {
+	c := coordconv.MGRSCoord{Zone: 16, Band: 'S', Column: 'G', Row: 'C', Easting: 38551, Northing: 24838, Precision: 5}
+	c.Precision = 2
+	if got := c.String(); got != "16SGC3824" {
+		t.Errorf("expected 16SGC3824, got %s", got)
+	}
+	c.Row = 'D'
+	if got := c.String(); got != "16SGD3824" {
+		t.Errorf("expected 16SGD3824, got %s", got)
+	}
+
+	for _, c := range []coordconv.MGRSCoord{
+		{Zone: 61, Band: 'S', Column: 'G', Row: 'C'},
+		{Zone: 16, Band: 'O', Column: 'G', Row: 'C'},
+		{Zone: 16, Band: 'S', Column: '1', Row: 'C'},
+		{Zone: 16, Band: 'S', Column: 'G', Row: 'C', Precision: 6},
+		{Zone: 16, Band: 'S', Column: 'G', Row: 'C', Easting: 100000, Precision: 5},
+		{Zone: 16, Band: 'S', Column: 'G', Row: 'C', Northing: -1, Precision: 5},
+	} {
+		if got := c.String(); got != "" {
+			t.Errorf("%+v: expected an empty string, got %s", c, got)
+		}
+	}
+}
+
+func TestMGRSCoordConversion(t *testing.T) {
+	mgrs, err := coordconv.NewMGRS(ellipsoidSemiMajorAxis, ellipsoidFlattening, "WE")
+	if err != nil {
+		t.Fatalf("error creating MGRS converter: %s", err)
+	}
+
+	c, err := mgrs.ConvertFromGeodeticCoord(s2.LatLngFromDegrees(0, 0), 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	expected := coordconv.MGRSCoord{Zone: 31, Band: 'N', Column: 'A', Row: 'A', Easting: 66021, Northing: 0, Precision: 5}
+	if c != expected {
+		t.Errorf("expected %+v, got %+v", expected, c)
+	}
+
+	for _, geo := range []s2.LatLng{
+		s2.LatLngFromDegrees(37.0, -84.0),
+		s2.LatLngFromDegrees(-33.9, 151.2),
+		s2.LatLngFromDegrees(60.5, 5.5),
+		s2.LatLngFromDegrees(89.0, 45.0),
+		s2.LatLngFromDegrees(-85.0, -120.0),
+	} {
+		c, err := mgrs.ConvertFromGeodeticCoord(geo, 4)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", geo, err)
+		}
+		s, err := mgrs.ConvertFromGeodetic(geo, 4)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", geo, err)
+		}
+		if c.String() != s {
+			t.Errorf("%s: expected %s, got %s", geo, s, c.String())
+		}
+		geo2, err := mgrs.ConvertToGeodeticCoord(c)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", geo, err)
+		}
+		if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > 15 {
+			t.Errorf("expected %s, got %s", geo, geo2)
+		}
+	}
+
+	utm := coordconv.UTMCoord{Zone: 16, Hemisphere: coordconv.HemisphereNorth, Easting: 738551, Northing: 4124838}
+	c, err = mgrs.ConvertFromUTMCoord(utm, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	expected = coordconv.MGRSCoord{Zone: 16, Band: 'S', Column: 'G', Row: 'G', Easting: 38551, Northing: 24838, Precision: 5}
+	if c != expected {
+		t.Errorf("expected %+v, got %+v", expected, c)
+	}
+
+	c.Row = 'Z'
+	if _, err := mgrs.ConvertToGeodeticCoord(c); err == nil {
+		t.Errorf("expected an error for %+v", c)
+	}
+}
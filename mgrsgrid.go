@@ -0,0 +1,375 @@
+package coordconv
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r1"
+	"github.com/golang/geo/r2"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// mgrsGridMaxLines is the most 100,000 meter square, easting and northing lines
+// a grid is generated with, to bound the work for a viewport that is large for
+// the spacing.
+const mgrsGridMaxLines = 20000
+
+// mgrsGridLineStep is the longest distance in meters between the vertices of
+// a grid line.
+const mgrsGridLineStep = 10000.0
+
+// mgrsGridExtentSamples is the number of points sampled along each side of
+// the visible part of a grid zone to find the extent of its grid.
+const mgrsGridExtentSamples = 64
+
+// MGRSGridKind identifies the lines and labels of an MGRS grid.
+type MGRSGridKind int
+
+// MGRSGridKind constants
+const (
+	// MGRSGridZone lines bound the grid zone designations, whose labels are
+	// the designations, e.g. "18S".
+	MGRSGridZone MGRSGridKind = iota
+	// MGRSGridSquare lines bound the 100,000 meter squares, whose labels are
+	// the square letters, e.g. "UJ".
+	MGRSGridSquare
+	// MGRSGridEasting and MGRSGridNorthing lines are the eastings and
+	// northings at the grid spacing within the 100,000 meter squares.
+	MGRSGridEasting
+	MGRSGridNorthing
+)
+
+// MGRSGridLine is a line of an MGRS grid.  The lines of the UTM and UPS grids
+// are labelled with the digits of their easting or northing at the precision
+// of the grid spacing, e.g. "23" for 23,000 meters on a 1,000 meter grid, and
+// the grid zone designation boundaries are unlabelled.
+type MGRSGridLine struct {
+	Kind     MGRSGridKind
+	Polyline s2.Polyline
+	Label    string
+}
+
+// MGRSGridLabel labels a grid zone designation or a 100,000 meter square at
+// the centre of its visible part.
+type MGRSGridLabel struct {
+	Kind     MGRSGridKind
+	Position s2.LatLng
+	Label    string
+}
+
+// MGRSGrid is the MGRS grid of a viewport, for drawing over a map.
+type MGRSGrid struct {
+	Lines  []MGRSGridLine
+	Labels []MGRSGridLabel
+}
+
+// Grid generates the MGRS grid of the viewport according to the current
+// ellipsoid parameters: the grid zone designation boundaries, including the
+// Norway and Svalbard exceptions and the UPS polar regions, the 100,000 meter
+// square boundaries and the easting and northing lines at the spacing, which is
+// a power of ten from 1 to 100,000 meters, or zero for only the grid zone
+// designations, as for a view of the world.  Lines are clipped to the viewport
+// and to the grid zone designations.
+func (m *MGRS) Grid(viewport s2.Rect, spacing float64) (MGRSGrid, error) {
+	precision := -1
+	for p := 0; p <= mgrsMaxPrecision; p++ {
+		if spacing == computeScale(p) {
+			precision = p
+		}
+	}
+	if (precision < 0) && (spacing != 0) {
+		return MGRSGrid{}, errors.New("spacing must be zero or a power of ten from 1 to 100000 meters")
+	}
+	if !viewport.IsValid() || viewport.IsEmpty() {
+		return MGRSGrid{}, errors.New("invalid viewport")
+	}
+
+	var grid MGRSGrid
+	lines := 0
+	for _, gzd := range gridZoneDesignations() {
+		gridZone, err := m.gridZone(gzd.zone, gzd.letter)
+		if err != nil {
+			return MGRSGrid{}, err
+		}
+		visible, ok := visibleBound(gridZone.bound, viewport)
+		if !ok {
+			continue
+		}
+		grid.addGridZone(gridZone, visible)
+		if spacing == 0 {
+			continue
+		}
+		before := len(grid.Lines)
+		if err := grid.addGridSquares(m, gridZone, visible, spacing, precision, mgrsGridMaxLines-lines); err != nil {
+			return MGRSGrid{}, err
+		}
+		if lines += len(grid.Lines) - before; lines > mgrsGridMaxLines {
+			return MGRSGrid{}, errors.New("too many grid lines for the viewport")
+		}
+	}
+	return grid, nil
+}
+
+// addGridZone adds the boundaries and label of the visible part of a grid zone
+// designation.
+func (grid *MGRSGrid) addGridZone(gridZone mgrsGridZone, visible r2.Rect) {
+	// Each grid zone designation draws its west and south boundaries, which
+	// between them cover every boundary once
+	if visible.X.Lo == gridZone.bound.X.Lo {
+		grid.addLine(MGRSGridZone, gridZone.points([]r2.Point{
+			{X: visible.X.Lo, Y: visible.Y.Lo},
+			{X: visible.X.Lo, Y: visible.Y.Hi}}, false), "")
+	}
+	if (visible.Y.Lo == gridZone.bound.Y.Lo) && (visible.Y.Lo > -math.Pi/2) {
+		grid.addLine(MGRSGridZone, gridZone.points([]r2.Point{
+			{X: visible.X.Lo, Y: visible.Y.Lo},
+			{X: visible.X.Hi, Y: visible.Y.Lo}}, false), "")
+	}
+	designation := string('A' + gridZone.letter)
+	if gridZone.zone != 0 {
+		designation = fmt.Sprintf("%2.2d%s", gridZone.zone, designation)
+	}
+	grid.Labels = append(grid.Labels, MGRSGridLabel{
+		Kind:     MGRSGridZone,
+		Position: s2.LatLng{Lat: s1.Angle(visible.Y.Center()), Lng: s1.Angle(visible.X.Center())},
+		Label:    designation,
+	})
+}
+
+// addGridSquares adds the 100,000 meter square labels and the easting and
+// northing lines of the visible part of a grid zone designation, of which
+// there may be at most maxLines.
+func (grid *MGRSGrid) addGridSquares(m *MGRS, gridZone mgrsGridZone, visible r2.Rect,
+	spacing float64, precision int, maxLines int) error {
+	extent, err := gridZone.extent(visible)
+	if err != nil {
+		return err
+	}
+	lines := (extent.X.Length() + extent.Y.Length()) / spacing
+	if lines > float64(maxLines) {
+		return errors.New("too many grid lines for the viewport")
+	}
+
+	// 100,000 meter square labels
+	for e := math.Floor(extent.X.Lo/100000) * 100000; e < extent.X.Hi; e += 100000 {
+		for n := math.Floor(extent.Y.Lo/100000) * 100000; n < extent.Y.Hi; n += 100000 {
+			ring, err := gridZone.trace(gridSquare([4]r2.Point{
+				{X: e, Y: n}, {X: e + 100000, Y: n},
+				{X: e + 100000, Y: n + 100000}, {X: e, Y: n + 100000}}), true)
+			if err != nil {
+				return err
+			}
+			ring = clipRing(ring, visible)
+			if len(ring) < 3 {
+				continue
+			}
+			var center r2.Point
+			for _, p := range ring {
+				center = center.Add(p)
+			}
+			center = center.Mul(1 / float64(len(ring)))
+			position := s2.LatLng{Lat: s1.Angle(center.Y), Lng: s1.Angle(center.X)}
+			mgrsCoordinates, err := m.ConvertFromGeodeticCoord(position.Normalized(), 0)
+			if err != nil {
+				return err
+			}
+			grid.Labels = append(grid.Labels, MGRSGridLabel{
+				Kind:     MGRSGridSquare,
+				Position: position,
+				Label:    string([]byte{mgrsCoordinates.Column, mgrsCoordinates.Row}),
+			})
+		}
+	}
+
+	// Easting and northing lines, of which those at multiples of 100,000
+	// meters bound the squares
+	for _, northing := range []bool{false, true} {
+		from, across := extent.X, extent.Y
+		kind := MGRSGridEasting
+		if northing {
+			from, across = extent.Y, extent.X
+			kind = MGRSGridNorthing
+		}
+		segments := math.Max(1, math.Ceil(across.Length()/mgrsGridLineStep))
+		for value := math.Ceil(from.Lo/spacing) * spacing; value <= from.Hi; value += spacing {
+			if (gridZone.zone == 0) && !northing && (value == upsFalseEasting) {
+				// on the boundary between the grid zone designations
+				continue
+			}
+			line := make([]r2.Point, 0, int(segments)+1)
+			for j := 0.0; j <= segments; j++ {
+				p := r2.Point{X: value, Y: across.Lo + across.Length()*j/segments}
+				if northing {
+					p = r2.Point{X: p.Y, Y: p.X}
+				}
+				line = append(line, p)
+			}
+			traced, err := gridZone.trace(line, false)
+			if err != nil {
+				return err
+			}
+
+			lineKind := kind
+			if math.Mod(value, 100000) == 0 {
+				lineKind = MGRSGridSquare
+			}
+			label := ""
+			if precision > 0 {
+				label = fmt.Sprintf("%*.*d", precision, precision,
+					int(math.Mod(value, 100000)/spacing))
+			}
+			for _, piece := range clipPolyline(traced, visible) {
+				grid.addLine(lineKind, gridZone.points(piece, false), label)
+			}
+		}
+	}
+	return nil
+}
+
+// addLine adds a line of at least two points to the grid.
+func (grid *MGRSGrid) addLine(kind MGRSGridKind, points []s2.Point, label string) {
+	if len(points) < 2 {
+		return
+	}
+	grid.Lines = append(grid.Lines, MGRSGridLine{Kind: kind, Polyline: s2.Polyline(points), Label: label})
+}
+
+// extent returns the range of grid eastings (X) and northings (Y) covering the
+// visible part of the grid zone, found from points around its edge.
+func (g mgrsGridZone) extent(visible r2.Rect) (r2.Rect, error) {
+	extent := r2.EmptyRect()
+	corners := visible.Vertices()
+	for i, corner := range corners {
+		step := corners[(i+1)%4].Sub(corner).Mul(1.0 / mgrsGridExtentSamples)
+		for j := 0; j < mgrsGridExtentSamples; j++ {
+			p := corner.Add(step.Mul(float64(j)))
+			if math.Abs(p.Y) == math.Pi/2 {
+				extent = extent.AddPoint(g.pole)
+				continue
+			}
+			gridCoordinates, err := g.fromGeodetic(s2.LatLng{Lat: s1.Angle(p.Y), Lng: s1.Angle(p.X)}.Normalized())
+			if err != nil {
+				return r2.Rect{}, err
+			}
+			extent = extent.AddPoint(gridCoordinates)
+		}
+	}
+	// Along a parallel, the northing is least on the central meridian
+	if zoneCenter := g.bound.X.Center(); (g.zone != 0) && visible.X.Contains(zoneCenter) {
+		for _, latitude := range []float64{visible.Y.Lo, visible.Y.Hi} {
+			gridCoordinates, err := g.fromGeodetic(s2.LatLng{Lat: s1.Angle(latitude), Lng: s1.Angle(g.projection.Parameters().CentralMeridian)})
+			if err != nil {
+				return r2.Rect{}, err
+			}
+			extent = extent.AddPoint(gridCoordinates)
+		}
+	}
+	return extent, nil
+}
+
+// mgrsGridZoneDesignation identifies a grid zone designation by its zone, 0
+// for UPS, and latitude band letter.
+type mgrsGridZoneDesignation struct {
+	zone   int
+	letter byte
+}
+
+// gridZoneDesignations returns every grid zone designation.
+func gridZoneDesignations() []mgrsGridZoneDesignation {
+	designations := []mgrsGridZoneDesignation{{0, letterA}, {0, letterB}}
+	for _, band := range latitudeBands {
+		for zone := 1; zone <= 60; zone++ {
+			if (band.letter == letterX) && ((zone == 32) || (zone == 34) || (zone == 36)) {
+				continue
+			}
+			designations = append(designations, mgrsGridZoneDesignation{zone, byte(band.letter)})
+		}
+	}
+	return append(designations, mgrsGridZoneDesignation{0, letterY}, mgrsGridZoneDesignation{0, letterZ})
+}
+
+// visibleBound returns the part of the grid zone bound inside the viewport,
+// with longitudes continuous across the grid zone, and whether it has any
+// area.
+func visibleBound(bound r2.Rect, viewport s2.Rect) (r2.Rect, bool) {
+	latitudes := bound.Y.Intersection(r1.Interval{Lo: viewport.Lat.Lo, Hi: viewport.Lat.Hi})
+	longitudes := viewport.Lng.Intersection(s1.IntervalFromEndpoints(bound.X.Lo, bound.X.Hi))
+	if latitudes.IsEmpty() || (latitudes.Length() == 0) || longitudes.IsEmpty() || (longitudes.Length() == 0) {
+		return r2.Rect{}, false
+	}
+	visible := r2.Rect{X: r1.Interval{Lo: longitudes.Lo, Hi: longitudes.Hi}, Y: latitudes}
+	if visible.X.Lo > visible.X.Hi {
+		visible.X.Lo -= 2 * math.Pi
+	}
+	if visible.X.Lo < bound.X.Lo {
+		visible.X.Lo += 2 * math.Pi
+		visible.X.Hi += 2 * math.Pi
+	}
+	// Snap to the bound, which is tested for the boundaries to draw
+	if math.Abs(visible.X.Lo-bound.X.Lo) < 1.0e-15 {
+		visible.X.Lo = bound.X.Lo
+	}
+	if math.Abs(visible.X.Hi-bound.X.Hi) < 1.0e-15 {
+		visible.X.Hi = bound.X.Hi
+	}
+	return visible, true
+}
+
+// clipPolyline clips a line to a rectangle, returning the pieces of it inside
+// the rectangle.
+func clipPolyline(line []r2.Point, bound r2.Rect) [][]r2.Point {
+	var pieces [][]r2.Point
+	var piece []r2.Point
+	for i := 0; i+1 < len(line); i++ {
+		a, b := line[i], line[i+1]
+		t0, t1, ok := clipSegment(a, b, bound)
+		if !ok {
+			if len(piece) > 1 {
+				pieces = append(pieces, piece)
+			}
+			piece = nil
+			continue
+		}
+		if len(piece) == 0 {
+			piece = append(piece, a.Add(b.Sub(a).Mul(t0)))
+		}
+		piece = append(piece, a.Add(b.Sub(a).Mul(t1)))
+		if t1 < 1 {
+			pieces = append(pieces, piece)
+			piece = nil
+		}
+	}
+	if len(piece) > 1 {
+		pieces = append(pieces, piece)
+	}
+	return pieces
+}
+
+// clipSegment returns the range of the parameter t of the segment a + t(b-a),
+// 0 <= t <= 1, inside the rectangle (Liang-Barsky), and whether there is any.
+func clipSegment(a, b r2.Point, bound r2.Rect) (t0, t1 float64, ok bool) {
+	t0, t1 = 0, 1
+	d := b.Sub(a)
+	for _, side := range [4]struct{ p, q float64 }{
+		{-d.X, a.X - bound.X.Lo},
+		{d.X, bound.X.Hi - a.X},
+		{-d.Y, a.Y - bound.Y.Lo},
+		{d.Y, bound.Y.Hi - a.Y},
+	} {
+		if side.p == 0 {
+			if side.q < 0 {
+				return 0, 0, false
+			}
+			continue
+		}
+		t := side.q / side.p
+		if side.p < 0 {
+			t0 = math.Max(t0, t)
+		} else {
+			t1 = math.Min(t1, t)
+		}
+	}
+	return t0, t1, t0 < t1
+}
@@ -0,0 +1,197 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestMGRSGrid(t *testing.T) {
+	mgrs := coordconv.DefaultMGRSConverter
+	viewport := rectFromDegrees(38.7, -77.2, 39.0, -76.9)
+	grid, err := mgrs.Grid(viewport, 10000)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	kinds := map[coordconv.MGRSGridKind]int{}
+	for _, line := range grid.Lines {
+		kinds[line.Kind]++
+		if len(line.Polyline) < 2 {
+			t.Errorf("expected a line of at least two points, got %d", len(line.Polyline))
+		}
+		for _, p := range line.Polyline {
+			ll := s2.LatLngFromPoint(p)
+			if (ll.Lat.Degrees() < 38.7-1e-6) || (ll.Lat.Degrees() > 39.0+1e-6) ||
+				(ll.Lng.Degrees() < -77.2-1e-6) || (ll.Lng.Degrees() > -76.9+1e-6) {
+				t.Errorf("expected %s inside the viewport", ll)
+			}
+		}
+		switch line.Kind {
+		case coordconv.MGRSGridEasting, coordconv.MGRSGridNorthing:
+			if (len(line.Label) != 1) || (line.Label == "0") {
+				t.Errorf("expected a single non-zero digit label, got %q", line.Label)
+			}
+		case coordconv.MGRSGridSquare:
+			if line.Label != "0" {
+				t.Errorf("expected a square boundary labelled 0, got %q", line.Label)
+			}
+		}
+	}
+	// 27 km across and 33 km high: two or three lines of each
+	if (kinds[coordconv.MGRSGridEasting] < 2) || (kinds[coordconv.MGRSGridNorthing] < 2) {
+		t.Errorf("expected easting and northing lines, got %v", kinds)
+	}
+	if kinds[coordconv.MGRSGridZone] != 0 {
+		t.Errorf("expected no grid zone lines, got %d", kinds[coordconv.MGRSGridZone])
+	}
+
+	labels := map[string]bool{}
+	for _, label := range grid.Labels {
+		labels[label.Label] = true
+		if !viewport.ContainsLatLng(label.Position) {
+			t.Errorf("expected label %s at %s inside the viewport", label.Label, label.Position)
+		}
+	}
+	for _, label := range []string{"18S", "UJ", "UH"} {
+		if !labels[label] {
+			t.Errorf("expected label %s, got %v", label, labels)
+		}
+	}
+}
+
+func TestMGRSGridZones(t *testing.T) {
+	mgrs := coordconv.DefaultMGRSConverter
+	for _, tc := range []struct {
+		name         string
+		viewport     s2.Rect
+		meridians    []float64 // longitudes of the grid zone boundaries
+		notMeridians []float64
+		parallels    []float64 // latitudes of the grid zone boundaries
+		gridZones    []string
+		notGridZone  string
+	}{
+		{"Norway", rectFromDegrees(57, 1, 63, 10), []float64{3}, []float64{6, 9}, []float64{}, []string{"31V", "32V"}, "31U"},
+		{"Svalbard", rectFromDegrees(71, 1, 83, 20), []float64{6, 9, 12, 18}, []float64{3, 15}, []float64{72}, []string{"31X", "33X", "31W", "33W"}, "32X"},
+		{"UPS north", rectFromDegrees(83, -20, 90, 20), []float64{-18, -12, -6, 0, 9}, []float64{6, 12}, []float64{84}, []string{"Y", "Z", "30X", "31X"}, ""},
+		{"UPS south", rectFromDegrees(-90, 170, -79, -170), []float64{174, 180, -174}, []float64{}, []float64{-80}, []string{"A", "B", "01C", "60C"}, ""},
+	} {
+		grid, err := mgrs.Grid(tc.viewport, 100000)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		labels := map[string]bool{}
+		for _, label := range grid.Labels {
+			if label.Kind == coordconv.MGRSGridZone {
+				labels[label.Label] = true
+			}
+		}
+		for _, label := range tc.gridZones {
+			if !labels[label] {
+				t.Errorf("%s: expected grid zone %s, got %v", tc.name, label, labels)
+			}
+		}
+		if labels[tc.notGridZone] {
+			t.Errorf("%s: expected no grid zone %s", tc.name, tc.notGridZone)
+		}
+
+		var meridians, parallels []float64
+		for _, line := range grid.Lines {
+			if line.Kind != coordconv.MGRSGridZone {
+				continue
+			}
+			first := s2.LatLngFromPoint(line.Polyline[0])
+			last := s2.LatLngFromPoint(line.Polyline[len(line.Polyline)-1])
+			if math.Abs(first.Lng.Degrees()-last.Lng.Degrees()) < 1e-9 {
+				meridians = append(meridians, first.Lng.Degrees())
+			} else if math.Abs(first.Lat.Degrees()-last.Lat.Degrees()) < 1e-9 {
+				parallels = append(parallels, first.Lat.Degrees())
+			} else {
+				t.Errorf("%s: expected a meridian or parallel from %s to %s", tc.name, first, last)
+			}
+		}
+		for _, expected := range tc.meridians {
+			if !containsDegrees(meridians, expected) {
+				t.Errorf("%s: expected a boundary along %f, got %v", tc.name, expected, meridians)
+			}
+		}
+		for _, unexpected := range tc.notMeridians {
+			if containsDegrees(meridians, unexpected) {
+				t.Errorf("%s: expected no boundary along %f, got %v", tc.name, unexpected, meridians)
+			}
+		}
+		for _, expected := range tc.parallels {
+			if !containsDegrees(parallels, expected) {
+				t.Errorf("%s: expected a boundary along %f, got %v", tc.name, expected, parallels)
+			}
+		}
+	}
+}
+
+func TestMGRSGridWorld(t *testing.T) {
+	mgrs := coordconv.DefaultMGRSConverter
+	grid, err := mgrs.Grid(s2.FullRect(), 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	for _, line := range grid.Lines {
+		if line.Kind != coordconv.MGRSGridZone {
+			t.Fatalf("expected only grid zone lines, got kind %d", line.Kind)
+		}
+	}
+	labels := map[string]bool{}
+	for _, label := range grid.Labels {
+		if label.Kind != coordconv.MGRSGridZone {
+			t.Fatalf("expected only grid zone labels, got %s", label.Label)
+		}
+		if labels[label.Label] {
+			t.Errorf("expected grid zone %s once", label.Label)
+		}
+		labels[label.Label] = true
+	}
+	// 60 zones of 20 latitude bands, less 32X, 34X and 36X, and the four
+	// UPS grid zone designations
+	if len(labels) != 60*20-3+4 {
+		t.Errorf("expected %d grid zones, got %d", 60*20-3+4, len(labels))
+	}
+	for _, label := range []string{"01C", "18S", "31U", "32V", "37X", "60X", "A", "B", "Y", "Z"} {
+		if !labels[label] {
+			t.Errorf("expected grid zone %s", label)
+		}
+	}
+
+	// the kilometer grid of the world is too many lines to draw
+	if _, err := mgrs.Grid(s2.FullRect(), 1000); err == nil {
+		t.Errorf("expected an error for too many lines")
+	}
+	// but the grid zone lines do not count towards the limit
+	if _, err := mgrs.Grid(rectFromDegrees(-80, -120, 84, -30), 100000); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	}
+}
+
+func TestMGRSGridErrors(t *testing.T) {
+	mgrs := coordconv.DefaultMGRSConverter
+	for _, spacing := range []float64{5, 250, 1e6, -100} {
+		if _, err := mgrs.Grid(rectFromDegrees(38, -78, 39, -77), spacing); err == nil {
+			t.Errorf("%f: expected an error", spacing)
+		}
+	}
+	if _, err := mgrs.Grid(s2.EmptyRect(), 1000); err == nil {
+		t.Errorf("expected an error for an empty viewport")
+	}
+	if _, err := mgrs.Grid(rectFromDegrees(30, -90, 50, -60), 1); err == nil {
+		t.Errorf("expected an error for too many lines")
+	}
+}
+
+func containsDegrees(values []float64, expected float64) bool {
+	for _, v := range values {
+		if math.Abs(math.Remainder(v-expected, 360)) < 1e-9 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+package coordconv
+
+import (
+	"errors"
+
+	"github.com/golang/geo/r2"
+)
+
+// MGRSDirection is a direction along the UTM or UPS grid of an MGRS cell.
+type MGRSDirection byte
+
+// MGRSDirection constants
+const (
+	MGRSNorth MGRSDirection = iota
+	MGRSEast
+	MGRSSouth
+	MGRSWest
+)
+
+// mgrsNeighborTolerance is the distance in meters to which the point where a
+// grid line leaves a grid zone designation is found, and beyond which the cell
+// across it is taken.  It is well over the espilon2 that conversions to MGRS
+// round up by and the 1.0e-9 radians south of the equator that UTM takes to
+// be on it, either of which would name a square only touching the grid zone
+// designation.
+const mgrsNeighborTolerance = 0.01
+
+// mgrsMaxOffset is the most cells east or west and north or south that an
+// offset steps through.
+const mgrsMaxOffset = 10000
+
+// Neighbor returns the MGRS coordinate string of the cell next to the cell
+// named by the MGRS coordinate string, in the direction along its grid and at
+// the same precision, according to the current ellipsoid parameters.  Across a
+// zone or latitude band boundary, or between UTM and UPS, the neighbour is the
+// first cell of the next grid zone designation along the grid line through the
+// cell.
+func (m *MGRS) Neighbor(mgrsorUSNGCoordinates string, direction MGRSDirection) (string, error) {
+	mgrsCoordinates, err := ParseMGRS(mgrsorUSNGCoordinates)
+	if err != nil {
+		return "", err
+	}
+	neighbor, err := m.NeighborCoord(mgrsCoordinates, direction)
+	if err != nil {
+		return "", err
+	}
+	return neighbor.format()
+}
+
+// NeighborCoord returns the MGRS coordinates of the cell next to the cell
+// named by the MGRS coordinates, as Neighbor.
+func (m *MGRS) NeighborCoord(mgrsCoordinates MGRSCoord, direction MGRSDirection) (MGRSCoord, error) {
+	zone, letters, easting, northing, precision, err := mgrsCoordinates.parts()
+	if err != nil {
+		return MGRSCoord{}, err
+	}
+	var step r2.Point
+	switch direction {
+	case MGRSNorth:
+		step = r2.Point{X: 0, Y: 1}
+	case MGRSEast:
+		step = r2.Point{X: 1, Y: 0}
+	case MGRSSouth:
+		step = r2.Point{X: 0, Y: -1}
+	case MGRSWest:
+		step = r2.Point{X: -1, Y: 0}
+	default:
+		return MGRSCoord{}, errors.New("invalid direction")
+	}
+
+	gridZone, err := m.gridZone(zone, letters[0])
+	if err != nil {
+		return MGRSCoord{}, err
+	}
+	if zone != 0 {
+		utmCoordinates, err := m.toUTM(zone, letters, easting, northing, precision)
+		if err != nil {
+			return MGRSCoord{}, err
+		}
+		easting = utmCoordinates.Easting
+		northing = utmCoordinates.Northing
+	} else {
+		upsCoordinates, err := m.toUPS(letters, easting, northing)
+		if err != nil {
+			return MGRSCoord{}, err
+		}
+		easting = upsCoordinates.Easting
+		northing = upsCoordinates.Northing
+	}
+
+	// Start inside the cell, at the centre of its square or, if that is
+	// clipped off by the grid zone designation, of its footprint
+	size := computeScale(precision)
+	center := r2.Point{X: easting + size/2, Y: northing + size/2}
+	start := center
+	_, inside, err := m.gridCell(gridZone, start, precision)
+	if err != nil {
+		return MGRSCoord{}, err
+	}
+	if !inside {
+		cell, err := m.ConvertToCellCoord(mgrsCoordinates)
+		if err != nil {
+			return MGRSCoord{}, err
+		}
+		if start, err = gridZone.fromGeodetic(cell.Center); err != nil {
+			return MGRSCoord{}, err
+		}
+		if _, inside, err = m.gridCell(gridZone, start, precision); err != nil {
+			return MGRSCoord{}, err
+		}
+	}
+
+	// The neighbour is the next square of the grid, unless the grid line
+	// leaves the grid zone designation before reaching it
+	next := start.Add(step.Mul(size))
+	neighbor, ok, err := m.gridCell(gridZone, next, precision)
+	if err != nil {
+		return MGRSCoord{}, err
+	}
+	if ok {
+		return neighbor, nil
+	}
+	if !inside {
+		// the cell lies just outside its grid zone designation, where
+		// conversions to MGRS round to it
+		return m.cellAt(gridZone, next, precision)
+	}
+
+	// Otherwise it is the next square if the grid line reaches it within the
+	// grid zone designation, or else the cell beyond where the line leaves
+	in, out := 0.0, size
+	for out-in > mgrsNeighborTolerance {
+		distance := (in + out) / 2
+		if _, ok, err := m.gridCell(gridZone, start.Add(step.Mul(distance)), precision); err != nil {
+			return MGRSCoord{}, err
+		} else if ok {
+			in = distance
+		} else {
+			out = distance
+		}
+	}
+	if in > center.Add(step.Mul(size/2)).Sub(start).Dot(step) {
+		neighbor, _, err := m.gridCell(gridZone, start.Add(step.Mul(in)), precision)
+		return neighbor, err
+	}
+	return m.cellAt(gridZone, start.Add(step.Mul(out+mgrsNeighborTolerance)), precision)
+}
+
+// gridCell returns the MGRS coordinates of the cell of the grid zone's grid at
+// the point of grid coordinates, and whether conversions to MGRS place the
+// point in the grid zone designation.
+func (m *MGRS) gridCell(g mgrsGridZone, p r2.Point, precision int) (MGRSCoord, bool, error) {
+	geodeticCoordinates, err := g.toGeodetic(p)
+	if err != nil {
+		return MGRSCoord{}, false, err
+	}
+	latitude := geodeticCoordinates.Lat.Radians()
+	utmPath := (latitude >= minMGRSNonPolarLat-epsilonRadians) &&
+		(latitude < maxMGRSNonPolarLat+epsilonRadians)
+	if (g.zone != 0) != utmPath {
+		return MGRSCoord{}, false, nil
+	}
+
+	var mgrsCoordinates MGRSCoord
+	if g.zone != 0 {
+		hemisphere := HemisphereNorth
+		if g.falseNorthing != 0 {
+			hemisphere = HemisphereSouth
+		}
+		mgrsCoordinates, err = m.fromUTM(UTMCoord{Zone: g.zone, Hemisphere: hemisphere, Easting: p.X, Northing: p.Y},
+			geodeticCoordinates.Lng.Radians(), latitude, precision)
+	} else {
+		hemisphere := HemisphereNorth
+		if g.letter <= letterB {
+			hemisphere = HemisphereSouth
+		}
+		mgrsCoordinates, err = m.fromUPS(UPSCoord{Hemisphere: hemisphere, Easting: p.X, Northing: p.Y}, precision)
+	}
+	if err != nil {
+		return MGRSCoord{}, false, err
+	}
+	return mgrsCoordinates, (mgrsCoordinates.Zone == g.zone) && (mgrsCoordinates.Band == 'A'+g.letter), nil
+}
+
+// cellAt returns the MGRS coordinates of the cell at the point of grid
+// coordinates of the grid zone, in whichever grid zone designation it lies.
+func (m *MGRS) cellAt(g mgrsGridZone, p r2.Point, precision int) (MGRSCoord, error) {
+	geodeticCoordinates, err := g.toGeodetic(p)
+	if err != nil {
+		return MGRSCoord{}, err
+	}
+	return m.ConvertFromGeodeticCoord(geodeticCoordinates.Normalized(), precision)
+}
+
+// Offset returns the MGRS coordinate string of the cell east cells east (west
+// if negative) and north cells north (south if negative) of the cell named by
+// the MGRS coordinate string, according to the current ellipsoid parameters.
+// It steps one neighbour at a time, east or west first, so that the path
+// follows the grid across grid zone designations, and so allows offsets of at
+// most 10,000 cells each way; further cells are better found by converting
+// from geodetic coordinates.
+func (m *MGRS) Offset(mgrsorUSNGCoordinates string, east, north int) (string, error) {
+	mgrsCoordinates, err := ParseMGRS(mgrsorUSNGCoordinates)
+	if err != nil {
+		return "", err
+	}
+	offset, err := m.OffsetCoord(mgrsCoordinates, east, north)
+	if err != nil {
+		return "", err
+	}
+	return offset.format()
+}
+
+// OffsetCoord returns the MGRS coordinates of the cell offset from the cell
+// named by the MGRS coordinates, as Offset.
+func (m *MGRS) OffsetCoord(mgrsCoordinates MGRSCoord, east, north int) (MGRSCoord, error) {
+	if _, _, _, _, _, err := mgrsCoordinates.parts(); err != nil {
+		return MGRSCoord{}, err
+	}
+	if (east < -mgrsMaxOffset) || (east > mgrsMaxOffset) ||
+		(north < -mgrsMaxOffset) || (north > mgrsMaxOffset) {
+		return MGRSCoord{}, errors.New("offset out of range")
+	}
+	steps := []struct {
+		count     int
+		direction MGRSDirection
+	}{
+		{east, MGRSEast},
+		{-east, MGRSWest},
+		{north, MGRSNorth},
+		{-north, MGRSSouth},
+	}
+	var err error
+	for _, s := range steps {
+		for i := 0; i < s.count; i++ {
+			if mgrsCoordinates, err = m.NeighborCoord(mgrsCoordinates, s.direction); err != nil {
+				return MGRSCoord{}, err
+			}
+		}
+	}
+	return mgrsCoordinates, nil
+}
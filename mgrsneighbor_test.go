@@ -0,0 +1,285 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestMGRSNeighbor(t *testing.T) {
+	mgrs := coordconv.DefaultMGRSConverter
+	for _, tc := range []struct {
+		mgrs     string
+		expected [4]string // north, east, south and west neighbours
+	}{
+		{"18SUJ2306", [4]string{"18SUJ2307", "18SUJ2406", "18SUJ2305", "18SUJ2206"}},
+		{"18SUJ9906", [4]string{"18SUJ9907", "18SVJ0006", "18SUJ9905", "18SUJ9806"}},
+		{"18SUJ2399", [4]string{"18SUK2300", "18SUJ2499", "18SUJ2398", "18SUJ2299"}},
+		{"18SUJ", [4]string{"18SUK", "18SVJ", "18SUH", "18STJ"}},
+		{"31MEV0099", [4]string{"31NEA0000", "31MEV0199", "31MEV0098", "31MDV9999"}},
+		{"18NWF", [4]string{"18NWG", "18NXF", "18MWE", "18NVF"}},
+		{"30NZF", [4]string{"30NZG", "31NAA", "30MZE", "30NYF"}},
+		{"18NWF1000", [4]string{"18NWF1001", "18NWF1100", "18MWE1099", "18NWF0900"}},
+	} {
+		for direction, expected := range tc.expected {
+			got, err := mgrs.Neighbor(tc.mgrs, coordconv.MGRSDirection(direction))
+			if err != nil {
+				t.Errorf("%s: expected no error, got %s", tc.mgrs, err)
+				continue
+			}
+			if got != expected {
+				t.Errorf("%s: expected neighbour %d %s, got %s", tc.mgrs, direction, expected, got)
+			}
+		}
+	}
+
+	if _, err := mgrs.Neighbor("18SUJ2306", coordconv.MGRSDirection(4)); err == nil {
+		t.Errorf("expected an error for an invalid direction")
+	}
+	for _, s := range []string{"18SUJ230", "32XAA", "16SGZ"} {
+		if _, err := mgrs.Neighbor(s, coordconv.MGRSNorth); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
+
+func TestMGRSNeighborBoundaries(t *testing.T) {
+	mgrs := coordconv.DefaultMGRSConverter
+	for _, tc := range []struct {
+		name      string
+		geo       s2.LatLng
+		precision int
+		direction coordconv.MGRSDirection
+		zone      int
+		band      byte
+	}{
+		{"zone seam", s2.LatLngFromDegrees(45, 5.999), 2, coordconv.MGRSEast, 32, 'T'},
+		{"zone seam west", s2.LatLngFromDegrees(45, 6.001), 2, coordconv.MGRSWest, 31, 'T'},
+		{"latitude band", s2.LatLngFromDegrees(39.999, -77), 2, coordconv.MGRSNorth, 18, 'T'},
+		{"equator", s2.LatLngFromDegrees(-0.001, 3), 2, coordconv.MGRSNorth, 31, 'N'},
+		{"Norway", s2.LatLngFromDegrees(60, 2.9995), 3, coordconv.MGRSEast, 32, 'V'},
+		{"Svalbard", s2.LatLngFromDegrees(78, 8.9995), 3, coordconv.MGRSEast, 33, 'X'},
+		{"UTM to UPS north", s2.LatLngFromDegrees(83.999, 10), 2, coordconv.MGRSNorth, 0, 'Z'},
+		{"UPS to UTM north", s2.LatLngFromDegrees(84.001, 0.5), 2, coordconv.MGRSSouth, 31, 'X'},
+		{"UTM to UPS south", s2.LatLngFromDegrees(-79.99, -100), 1, coordconv.MGRSSouth, 0, 'A'},
+		{"UPS to UTM south", s2.LatLngFromDegrees(-80.001, -100), 2, coordconv.MGRSWest, 14, 'C'},
+		{"UPS meridian", s2.LatLngFromDegrees(89, 0.001), 2, coordconv.MGRSWest, 0, 'Y'},
+	} {
+		c, err := mgrs.ConvertFromGeodeticCoord(tc.geo, tc.precision)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		neighbor, err := mgrs.NeighborCoord(c, tc.direction)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %s", tc.name, err)
+			continue
+		}
+		if (neighbor.Zone != tc.zone) || (neighbor.Band != tc.band) {
+			t.Errorf("%s: expected the neighbour of %s in %d%c, got %s", tc.name, c, tc.zone, tc.band, neighbor)
+		}
+		if neighbor.Precision != c.Precision {
+			t.Errorf("%s: expected precision %d, got %d", tc.name, c.Precision, neighbor.Precision)
+		}
+
+		// The cells touch
+		cell, err := mgrs.ConvertToCellCoord(c)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		neighborCell, err := mgrs.ConvertToCellCoord(neighbor)
+		if err != nil {
+			t.Fatalf("%s: %s: expected no error, got %s", tc.name, neighbor, err)
+		}
+		size := 1.0e5
+		for i := 0; i < c.Precision; i++ {
+			size /= 10
+		}
+		if d := cell.Center.Distance(neighborCell.Center).Radians() * ellipsoidSemiMajorAxis; d > 1.5*size {
+			t.Errorf("%s: expected %s next to %s, got %f m apart", tc.name, neighbor, c, d)
+		}
+	}
+}
+
+func TestMGRSNeighborEdges(t *testing.T) {
+	mgrs := coordconv.DefaultMGRSConverter
+	for _, tc := range []struct {
+		mgrs     string
+		expected [4]string // north, east, south and west neighbours
+	}{
+		// a few centimeters of a square south of 40 degrees, which the grid
+		// lines leave across the parallel into the rest of the square
+		{"60SYK5609832069", [4]string{"60TYK5609832069", "60SYK5609932069", "60SYK5609832068", "60TYK5609832069"}},
+		{"01SBE4390132069", [4]string{"01TBE4390132069", "01TBE4390132069", "01SBE4390132068", "01SBE4390032069"}},
+		{"60TYK5609932070", [4]string{"60TYK5609932071", "01TBE4390032070", "60TYK5609932069", "60TYK5609832070"}},
+	} {
+		for direction, expected := range tc.expected {
+			got, err := mgrs.Neighbor(tc.mgrs, coordconv.MGRSDirection(direction))
+			if err != nil {
+				t.Errorf("%s: expected no error, got %s", tc.mgrs, err)
+				continue
+			}
+			if got != expected {
+				t.Errorf("%s: expected neighbour %d %s, got %s", tc.mgrs, direction, expected, got)
+			}
+		}
+	}
+
+	// 1 m cells within half a meter of a latitude band, zone or UTM and UPS
+	// boundary
+	const metersPerDegree = 111000.0
+	for _, offset := range []float64{-0.45, -0.2, -0.05, 0.05, 0.2, 0.45} {
+		for _, geo := range []s2.LatLng{
+			s2.LatLngFromDegrees(40+offset/metersPerDegree, -77),
+			s2.LatLngFromDegrees(40+offset/metersPerDegree, 179.99999),
+			s2.LatLngFromDegrees(offset/metersPerDegree, 3),
+			s2.LatLngFromDegrees(64+offset/metersPerDegree, 4),
+			s2.LatLngFromDegrees(84+offset/metersPerDegree, 10),
+			s2.LatLngFromDegrees(-80+offset/metersPerDegree, -100),
+			s2.LatLngFromDegrees(45, 6+offset/(metersPerDegree*math.Cos(45*math.Pi/180))),
+			s2.LatLngFromDegrees(60, 3+offset/(metersPerDegree*math.Cos(60*math.Pi/180))),
+			s2.LatLngFromDegrees(78, 21+offset/(metersPerDegree*math.Cos(78*math.Pi/180))),
+			s2.LatLngFromDegrees(-30, 180+offset/(metersPerDegree*math.Cos(30*math.Pi/180))).Normalized(),
+		} {
+			c, err := mgrs.ConvertFromGeodeticCoord(geo, 5)
+			if err != nil {
+				t.Fatalf("%s: expected no error, got %s", geo, err)
+			}
+			cell, err := mgrs.ConvertToCellCoord(c)
+			if err != nil {
+				t.Fatalf("%s: expected no error, got %s", c, err)
+			}
+			for direction := coordconv.MGRSNorth; direction <= coordconv.MGRSWest; direction++ {
+				neighbor, err := mgrs.NeighborCoord(c, direction)
+				if err != nil {
+					t.Errorf("%s: expected no error, got %s", c, err)
+					continue
+				}
+				if neighbor == c {
+					t.Errorf("%s: expected a neighbour %d other than the cell", c, direction)
+				}
+				neighborCell, err := mgrs.ConvertToCellCoord(neighbor)
+				if err != nil {
+					t.Errorf("%s: %s: expected no error, got %s", c, neighbor, err)
+					continue
+				}
+				if d := cell.Center.Distance(neighborCell.Center).Radians() * ellipsoidSemiMajorAxis; d > 1.5 {
+					t.Errorf("%s: expected %s next to it, got %f m apart", c, neighbor, d)
+				}
+			}
+		}
+	}
+}
+
+func TestMGRSNeighborReverse(t *testing.T) {
+	mgrs := coordconv.DefaultMGRSConverter
+	opposite := map[coordconv.MGRSDirection]coordconv.MGRSDirection{
+		coordconv.MGRSNorth: coordconv.MGRSSouth,
+		coordconv.MGRSEast:  coordconv.MGRSWest,
+		coordconv.MGRSSouth: coordconv.MGRSNorth,
+		coordconv.MGRSWest:  coordconv.MGRSEast,
+	}
+	for lat := -89.7; lat < 90; lat += 7.3 {
+		for lng := -179.3; lng < 180; lng += 9.7 {
+			c, err := mgrs.ConvertFromGeodeticCoord(s2.LatLngFromDegrees(lat, lng), 2)
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+			for direction, reverse := range opposite {
+				neighbor, err := mgrs.NeighborCoord(c, direction)
+				if err != nil {
+					t.Fatalf("%s: expected no error, got %s", c, err)
+				}
+				// Grid lines cross zone and band boundaries at an angle, so only
+				// whole squares are their neighbour's neighbours
+				if !unclippedCell(t, mgrs, c) || !unclippedCell(t, mgrs, neighbor) {
+					continue
+				}
+				back, err := mgrs.NeighborCoord(neighbor, reverse)
+				if err != nil {
+					t.Fatalf("%s: expected no error, got %s", neighbor, err)
+				}
+				if back != c {
+					t.Errorf("expected %s back from %s, got %s", c, neighbor, back)
+				}
+			}
+		}
+	}
+}
+
+func TestMGRSOffset(t *testing.T) {
+	mgrs := coordconv.DefaultMGRSConverter
+	for _, tc := range []struct {
+		mgrs        string
+		east, north int
+		expected    string
+	}{
+		{"18SUJ2306", 3, -2, "18SUJ2604"},
+		{"18SUJ2306", 0, 0, "18SUJ2306"},
+		{"18SUJ2306", -24, 95, "18STK9901"},
+		{"18SUJ", 1, 0, "18SVJ"},
+		{"18SUJ", 0, -2, "18SUG"},
+	} {
+		got, err := mgrs.Offset(tc.mgrs, tc.east, tc.north)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %s", tc.mgrs, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("%s offset by %d, %d: expected %s, got %s", tc.mgrs, tc.east, tc.north, tc.expected, got)
+		}
+	}
+
+	// Around the zone 31 and 32 seam and back
+	start := "31TGK3687"
+	there, err := mgrs.Offset(start, 5, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if there[:3] != "32T" {
+		t.Errorf("expected %s offset into 32T, got %s", start, there)
+	}
+	back, err := mgrs.Offset(there, -5, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if back != start {
+		t.Errorf("expected %s back from %s, got %s", start, there, back)
+	}
+
+	if _, err := mgrs.Offset("18SUJ2", 1, 1); err == nil {
+		t.Errorf("expected an error")
+	}
+	for _, offset := range [][2]int{{10001, 0}, {0, -10001}, {math.MaxInt32, 1}} {
+		if _, err := mgrs.Offset("18SUJ2348006470", offset[0], offset[1]); err == nil {
+			t.Errorf("%v: expected an error for an offset out of range", offset)
+		}
+	}
+	if got, err := mgrs.Offset("18SUJ2348006470", 10000, -10000); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	} else if got != "18SUH3348096470" {
+		t.Errorf("expected 18SUH3348096470, got %s", got)
+	}
+}
+
+// unclippedCell returns whether the whole square of the cell is inside its grid
+// zone designation.
+func unclippedCell(t *testing.T, mgrs *coordconv.MGRS, c coordconv.MGRSCoord) bool {
+	cell, err := mgrs.ConvertToCellCoord(c)
+	if err != nil {
+		t.Fatalf("%s: expected no error, got %s", c, err)
+	}
+	center := s2.PointFromLatLng(cell.Center)
+	for _, corner := range cell.Corners {
+		inside := s2.Interpolate(0.01, s2.PointFromLatLng(corner), center)
+		got, err := mgrs.ConvertFromGeodeticCoord(s2.LatLngFromPoint(inside), c.Precision)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", c, err)
+		}
+		if got != c {
+			return false
+		}
+	}
+	return true
+}
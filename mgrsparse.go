@@ -0,0 +1,183 @@
+package coordconv
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// MGRSParseError describes a malformed MGRS coordinate string and the
+// position, counted in characters from zero, at which it was found, or -1 if
+// the position is unknown.
+type MGRSParseError struct {
+	Input    string
+	Position int
+	Message  string
+}
+
+func (e *MGRSParseError) Error() string {
+	return fmt.Sprintf("%s at position %d in %q", e.Message, e.Position, e.Input)
+}
+
+// ParseMGRSLenient breaks down an MGRS coordinate string into its component
+// parts as typed by hand: the zone may be a single digit, letters may be
+// lower case, whitespace and hyphens may separate the parts and digits may be
+// from any script.  If the easting and northing are separated, they must have
+// the same number of digits.  Errors are of type *MGRSParseError.
+func ParseMGRSLenient(mgrsString string) (MGRSCoord, error) {
+	fail := func(position int, message string) (MGRSCoord, error) {
+		return MGRSCoord{}, &MGRSParseError{Input: mgrsString, Position: position, Message: message}
+	}
+
+	const (
+		inZone = iota
+		inLetters
+		inDigits
+	)
+	state := inZone
+	var zone, letters, digits []byte
+	var zoneStart, lettersEnd, digitsStart, digitGroups, firstGroup, secondGroupStart int
+	var letterPositions [3]int
+	separated := false
+	position := 0
+	for _, r := range mgrsString {
+		switch {
+		case isSeparator(r):
+			separated = true
+			position++
+			continue
+		case unicode.IsDigit(r):
+			d := byte('0' + digitValue(r))
+			switch state {
+			case inZone:
+				if len(zone) == 0 {
+					zoneStart = position
+				} else if separated {
+					return fail(position, "zone digits separated")
+				}
+				if len(zone) == 2 {
+					return fail(position, "too many zone digits")
+				}
+				zone = append(zone, d)
+			case inLetters:
+				if len(letters) != 3 {
+					return fail(position, "wrong number of letters")
+				}
+				state = inDigits
+				fallthrough
+			case inDigits:
+				if len(digits) == 0 {
+					digitsStart = position
+				}
+				if len(digits) == 0 || separated {
+					digitGroups++
+					if digitGroups == 2 {
+						firstGroup = len(digits)
+						secondGroupStart = position
+					} else if digitGroups > 2 {
+						return fail(position, "too many digit groups")
+					}
+				}
+				if len(digits) == 2*mgrsMaxPrecision {
+					return fail(position, "too many digits")
+				}
+				digits = append(digits, d)
+			}
+		case r <= unicode.MaxASCII && isalpha(byte(r)):
+			l := toupper(byte(r))
+			switch state {
+			case inZone:
+				if len(zone) > 0 {
+					z := int(zone[0] - '0')
+					if len(zone) == 2 {
+						z = 10*z + int(zone[1]-'0')
+					}
+					if (z < 1) || (z > 60) {
+						return fail(zoneStart, "zone out of range")
+					}
+				}
+				state = inLetters
+				fallthrough
+			case inLetters:
+				if len(letters) == 3 {
+					return fail(position, "wrong number of letters")
+				}
+				if (l == 'I') || (l == 'O') {
+					return fail(position, "invalid letter")
+				}
+				letterPositions[len(letters)] = position
+				letters = append(letters, l)
+				lettersEnd = position + 1
+			case inDigits:
+				return fail(position, "letter after digits")
+			}
+		default:
+			return fail(position, "invalid character")
+		}
+		separated = false
+		position++
+	}
+
+	switch {
+	case len(zone) == 0 && len(letters) == 0 && len(digits) == 0:
+		return fail(position, "empty MGRS string")
+	case len(letters) != 3:
+		if state == inZone {
+			return fail(position, "zone without letters")
+		}
+		return fail(lettersEnd, "wrong number of letters")
+	case len(digits)%2 != 0:
+		return fail(position, "odd number of digits")
+	case digitGroups == 2 && 2*firstGroup != len(digits):
+		return fail(secondGroupStart, "easting and northing digits differ in number")
+	}
+	if len(zone) == 1 {
+		zone = append([]byte{'0'}, zone...)
+	}
+
+	c, err := ParseMGRS(string(zone) + string(letters) + string(digits))
+	if err != nil {
+		// at the component the error is about
+		position := -1
+		switch err.Error() {
+		case "zone out of range", "too few digits":
+			position = zoneStart
+		case "invalid letter 0", "wrong number of letters":
+			position = letterPositions[0]
+		case "invalid letter 1":
+			position = letterPositions[1]
+		case "invalid letter 2":
+			position = letterPositions[2]
+		case "wrong number of digits":
+			position = digitsStart
+		}
+		return fail(position, err.Error())
+	}
+	return c, nil
+}
+
+// CanonicalizeMGRS returns the MGRS coordinate string in its standard form, a
+// two digit zone, upper case letters and no separators, accepting the same
+// input as ParseMGRSLenient.
+func CanonicalizeMGRS(mgrsString string) (string, error) {
+	c, err := ParseMGRSLenient(mgrsString)
+	if err != nil {
+		return "", err
+	}
+	return c.format()
+}
+
+// isSeparator returns true for the whitespace and hyphens that may separate the
+// parts of an MGRS coordinate string.
+func isSeparator(r rune) bool {
+	return unicode.IsSpace(r) || unicode.Is(unicode.Pd, r)
+}
+
+// digitValue returns the value of a decimal digit from any script.  Unicode
+// encodes each script's digits zero to nine as a contiguous run.
+func digitValue(r rune) int {
+	start := r
+	for unicode.IsDigit(start - 1) {
+		start--
+	}
+	return int(r-start) % 10
+}
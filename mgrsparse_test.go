@@ -0,0 +1,106 @@
+package coordconv_test
+
+import (
+	"testing"
+
+	"github.com/tzneal/coordconv"
+)
+
+func TestParseMGRSLenient(t *testing.T) {
+	expected := coordconv.MGRSCoord{Zone: 4, Band: 'Q', Column: 'F', Row: 'J', Easting: 12345, Northing: 67890, Precision: 5}
+	for _, s := range []string{
+		"04QFJ1234567890",
+		"4QFJ1234567890",
+		"04 qfj 12345 67890",
+		"4q-fj-12345-67890",
+		" 04Q FJ\t12345 67890 ",
+		"04QFJ 1234567890",
+		"٠٤QFJ١٢٣٤٥٦٧٨٩٠",
+		"０４ QFJ 12345 67890",
+		"04–QFJ‐12345 67890",
+	} {
+		got, err := coordconv.ParseMGRSLenient(s)
+		if err != nil {
+			t.Errorf("%q: expected no error, got %s", s, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("%q: expected %+v, got %+v", s, expected, got)
+		}
+	}
+
+	got, err := coordconv.ParseMGRSLenient("z gc 12 34")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if got.String() != "ZGC1234" {
+		t.Errorf("expected ZGC1234, got %s", got.String())
+	}
+}
+
+func TestParseMGRSLenientErrors(t *testing.T) {
+	for _, tc := range []struct {
+		mgrs     string
+		position int
+	}{
+		{"", 0},
+		{"   ", 3},
+		{"04QFJ12345*67890", 10},
+		{"123QFJ", 2},
+		{"1 6SGC", 2},
+		{"61SGC", 0},
+		{" 00SGC", 1},
+		{"04QF", 4},
+		{"04QFJK123", 5},
+		{"04QFI123", 4},
+		{"04Q 12", 4},
+		{"04QFJ123", 8},
+		{"04QFJ12345678901", 15},
+		{"04QFJ 12 3456", 9},
+		{"04QFJ 12 34 56", 12},
+		{"04QFJ1234A", 9},
+		{"16", 2},
+		{"ééQFJ", 0},
+		{"04éQFJ", 2},
+	} {
+		_, err := coordconv.ParseMGRSLenient(tc.mgrs)
+		if err == nil {
+			t.Errorf("%q: expected an error", tc.mgrs)
+			continue
+		}
+		parseErr, ok := err.(*coordconv.MGRSParseError)
+		if !ok {
+			t.Errorf("%q: expected an MGRSParseError, got %T", tc.mgrs, err)
+			continue
+		}
+		if parseErr.Position != tc.position {
+			t.Errorf("%q: expected an error at %d, got %s", tc.mgrs, tc.position, err)
+		}
+	}
+}
+
+func TestCanonicalizeMGRS(t *testing.T) {
+	for _, tc := range []struct {
+		mgrs     string
+		expected string
+	}{
+		{"4QFJ1234567890", "04QFJ1234567890"},
+		{"04 qfj 12345 67890", "04QFJ1234567890"},
+		{"16s-gc-385-248", "16SGC385248"},
+		{"31NAA", "31NAA"},
+		{"y xk 1 2", "YXK12"},
+	} {
+		got, err := coordconv.CanonicalizeMGRS(tc.mgrs)
+		if err != nil {
+			t.Errorf("%q: expected no error, got %s", tc.mgrs, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("%q: expected %s, got %s", tc.mgrs, tc.expected, got)
+		}
+	}
+
+	if _, err := coordconv.CanonicalizeMGRS("04QFJ123"); err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -0,0 +1,185 @@
+package coordconv
+
+import (
+	"errors"
+	"math"
+
+	"github.com/golang/geo/s2"
+)
+
+// modisSphereRadius is the radius of the sphere of the MODIS sinusoidal grid.
+const modisSphereRadius = 6371007.181
+
+// MODISTileSize is the width and height of a MODIS tile in meters, 10 degrees
+// along the equator of the MODIS sphere.
+const MODISTileSize = modisSphereRadius * math.Pi / 18
+
+// MODISHorizontalTiles and MODISVerticalTiles are the number of columns and
+// rows of tiles in the MODIS grid.
+const (
+	MODISHorizontalTiles = 36
+	MODISVerticalTiles   = 18
+)
+
+// MODISResolution is the number of pixels along each side of a MODIS tile.
+type MODISResolution int
+
+// The nominal MODIS resolutions.
+const (
+	MODIS1km  MODISResolution = 1200
+	MODIS500m MODISResolution = 2400
+	MODIS250m MODISResolution = 4800
+)
+
+// PixelSize returns the width and height of a pixel in meters.
+func (r MODISResolution) PixelSize() float64 {
+	return MODISTileSize / float64(r)
+}
+
+// check returns an error if the resolution isn't one of the MODIS
+// resolutions.
+func (r MODISResolution) check() error {
+	switch r {
+	case MODIS1km, MODIS500m, MODIS250m:
+		return nil
+	}
+	return errors.New("invalid MODIS resolution")
+}
+
+// MODISTile is a tile of the MODIS sinusoidal grid.  H increases eastward from
+// 0 at the antimeridian to 35 and V increases southward from 0 at the north
+// pole to 17.
+type MODISTile struct {
+	H int
+	V int
+}
+
+// MODISPixel is a pixel of a MODIS tile at the given resolution.  Row
+// increases southward and Column eastward from 0 at the north west corner of
+// the tile.
+type MODISPixel struct {
+	Tile       MODISTile
+	Resolution MODISResolution
+	Row        int
+	Column     int
+}
+
+// MODIS locates WGS84 geodetic, UTM and MGRS coordinates on the tiles and
+// pixels of the MODIS sinusoidal grid.  As in the MODIS products, latitudes
+// and longitudes are used on the MODIS sphere unchanged.
+type MODIS struct {
+	sinusoidal *Sinusoidal
+	utm        *UTM
+	mgrs       *MGRS
+}
+
+// NewMODIS constructs a MODIS grid converter.
+func NewMODIS() (*MODIS, error) {
+	sinusoidal, err := NewSinusoidal(modisSphereRadius, 0, 0, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	utm, err := NewUTM()
+	if err != nil {
+		return nil, err
+	}
+	mgrs, err := NewMGRSEllipsoid(EllipsoidWGS84)
+	if err != nil {
+		return nil, err
+	}
+	return &MODIS{sinusoidal: sinusoidal, utm: utm, mgrs: mgrs}, nil
+}
+
+// Sinusoidal returns the projection of the MODIS grid.
+func (m *MODIS) Sinusoidal() *Sinusoidal {
+	return m.sinusoidal
+}
+
+// ConvertFromGeodetic returns the pixel at the given resolution that contains
+// the geodetic coordinates (latitude and longitude).  Points on the east and
+// south edges of the grid are placed in the last tile.
+func (m *MODIS) ConvertFromGeodetic(geodeticCoordinates s2.LatLng, resolution MODISResolution) (MODISPixel, error) {
+	if err := resolution.check(); err != nil {
+		return MODISPixel{}, err
+	}
+	mapCoords, err := m.sinusoidal.ConvertFromGeodetic(geodeticCoordinates)
+	if err != nil {
+		return MODISPixel{}, err
+	}
+
+	pixels := float64(resolution)
+	x := (mapCoords.Easting/MODISTileSize + MODISHorizontalTiles/2) * pixels
+	y := (MODISVerticalTiles/2 - mapCoords.Northing/MODISTileSize) * pixels
+	x = math.Max(0, math.Min(math.Floor(x), MODISHorizontalTiles*pixels-1))
+	y = math.Max(0, math.Min(math.Floor(y), MODISVerticalTiles*pixels-1))
+	column := int(x)
+	row := int(y)
+	return MODISPixel{
+		Tile:       MODISTile{H: column / int(resolution), V: row / int(resolution)},
+		Resolution: resolution,
+		Row:        row % int(resolution),
+		Column:     column % int(resolution),
+	}, nil
+}
+
+// ConvertFromUTM returns the pixel at the given resolution that contains the
+// WGS84 UTM coordinates.
+func (m *MODIS) ConvertFromUTM(utmCoordinates UTMCoord, resolution MODISResolution) (MODISPixel, error) {
+	geodeticCoordinates, err := m.utm.ConvertToGeodetic(utmCoordinates)
+	if err != nil {
+		return MODISPixel{}, err
+	}
+	return m.ConvertFromGeodetic(geodeticCoordinates, resolution)
+}
+
+// ConvertFromMGRS returns the pixel at the given resolution that contains the
+// WGS84 MGRS coordinate string.
+func (m *MODIS) ConvertFromMGRS(mgrsCoordinates string, resolution MODISResolution) (MODISPixel, error) {
+	geodeticCoordinates, err := m.mgrs.ConvertToGeodetic(mgrsCoordinates)
+	if err != nil {
+		return MODISPixel{}, err
+	}
+	return m.ConvertFromGeodetic(geodeticCoordinates, resolution)
+}
+
+// ConvertToMapCoords returns the sinusoidal coordinates (easting and
+// northing) of the centre of a pixel.
+func (m *MODIS) ConvertToMapCoords(pixel MODISPixel) (MapCoords, error) {
+	if err := pixel.check(); err != nil {
+		return MapCoords{}, err
+	}
+	size := pixel.Resolution.PixelSize()
+	return MapCoords{
+		Easting: float64(pixel.Tile.H-MODISHorizontalTiles/2)*MODISTileSize +
+			(float64(pixel.Column)+0.5)*size,
+		Northing: float64(MODISVerticalTiles/2-pixel.Tile.V)*MODISTileSize -
+			(float64(pixel.Row)+0.5)*size,
+	}, nil
+}
+
+// ConvertToGeodetic returns the geodetic coordinates (latitude and longitude)
+// of the centre of a pixel.  Pixels whose centres are off the edge of the
+// sinusoidal map, which hold no data in the MODIS products, are rejected.
+func (m *MODIS) ConvertToGeodetic(pixel MODISPixel) (s2.LatLng, error) {
+	mapCoords, err := m.ConvertToMapCoords(pixel)
+	if err != nil {
+		return s2.LatLng{}, err
+	}
+	return m.sinusoidal.ConvertToGeodetic(mapCoords)
+}
+
+// check returns an error if the pixel isn't on the MODIS grid.
+func (p MODISPixel) check() error {
+	if err := p.Resolution.check(); err != nil {
+		return err
+	}
+	if (p.Tile.H < 0) || (p.Tile.H >= MODISHorizontalTiles) ||
+		(p.Tile.V < 0) || (p.Tile.V >= MODISVerticalTiles) {
+		return errors.New("tile out of range")
+	}
+	if (p.Row < 0) || (p.Row >= int(p.Resolution)) ||
+		(p.Column < 0) || (p.Column >= int(p.Resolution)) {
+		return errors.New("pixel out of range")
+	}
+	return nil
+}
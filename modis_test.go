@@ -0,0 +1,145 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestMODIS(t *testing.T) {
+	modis, err := coordconv.NewMODIS()
+	if err != nil {
+		t.Fatalf("error creating modis converter: %s", err)
+	}
+
+	testCases := []struct {
+		name string
+		geo  s2.LatLng
+		tile coordconv.MODISTile
+	}{
+		{"boulder", s2.LatLngFromDegrees(39.5, -105.2), coordconv.MODISTile{H: 9, V: 5}},
+		{"boston", s2.LatLngFromDegrees(42.36, -71.06), coordconv.MODISTile{H: 12, V: 4}},
+		{"origin", s2.LatLngFromDegrees(0, 0), coordconv.MODISTile{H: 18, V: 9}},
+		{"canberra", s2.LatLngFromDegrees(-35.28, 149.13), coordconv.MODISTile{H: 30, V: 12}},
+		{"south pole", s2.LatLngFromDegrees(-90, 0), coordconv.MODISTile{H: 18, V: 17}},
+		{"antimeridian", s2.LatLngFromDegrees(0.5, 179.9), coordconv.MODISTile{H: 35, V: 8}},
+	}
+
+	for _, tc := range testCases {
+		for _, resolution := range []coordconv.MODISResolution{coordconv.MODIS1km, coordconv.MODIS500m, coordconv.MODIS250m} {
+			pixel, err := modis.ConvertFromGeodetic(tc.geo, resolution)
+			if err != nil {
+				t.Fatalf("%s: expected no error, got %s", tc.name, err)
+			}
+			if pixel.Tile != tc.tile {
+				t.Errorf("%s: expected tile %+v, got %+v", tc.name, tc.tile, pixel.Tile)
+			}
+
+			// on the sphere the rows follow the latitude and the columns
+			// the longitude scaled by the cosine of the latitude
+			pixels := float64(resolution)
+			lat := tc.geo.Lat.Degrees()
+			lng := tc.geo.Lng.Degrees()
+			row := math.Min(math.Floor((90-lat)/10*pixels), 18*pixels-1)
+			column := math.Min(math.Floor((lng*math.Cos(tc.geo.Lat.Radians())+180)/10*pixels), 36*pixels-1)
+			if pixel.Row != int(row)%int(resolution) || pixel.Column != int(column)%int(resolution) {
+				t.Errorf("%s: expected row %d column %d, got %+v", tc.name,
+					int(row)%int(resolution), int(column)%int(resolution), pixel)
+			}
+
+			// the pixel centre is within half a pixel of the point
+			centre, err := modis.ConvertToGeodetic(pixel)
+			if err != nil {
+				t.Fatalf("%s: expected no error, got %s", tc.name, err)
+			}
+			pixel2, err := modis.ConvertFromGeodetic(centre, resolution)
+			if err != nil {
+				t.Fatalf("%s: expected no error, got %s", tc.name, err)
+			}
+			if pixel2 != pixel {
+				t.Errorf("%s: expected the pixel centre in %+v, got %+v", tc.name, pixel, pixel2)
+			}
+			mc, err := modis.Sinusoidal().ConvertFromGeodetic(tc.geo)
+			if err != nil {
+				t.Fatalf("%s: expected no error, got %s", tc.name, err)
+			}
+			mcCentre, err := modis.ConvertToMapCoords(pixel)
+			if err != nil {
+				t.Fatalf("%s: expected no error, got %s", tc.name, err)
+			}
+			if math.Abs(mc.Easting-mcCentre.Easting) > resolution.PixelSize()/2+1e-6 ||
+				math.Abs(mc.Northing-mcCentre.Northing) > resolution.PixelSize()/2+1e-6 {
+				t.Errorf("%s: expected %v within half a pixel of %v", tc.name, mcCentre, mc)
+			}
+		}
+	}
+}
+
+func TestMODISUTMAndMGRS(t *testing.T) {
+	modis, err := coordconv.NewMODIS()
+	if err != nil {
+		t.Fatalf("error creating modis converter: %s", err)
+	}
+	utm, err := coordconv.NewUTM()
+	if err != nil {
+		t.Fatalf("error creating utm converter: %s", err)
+	}
+	mgrs, err := coordconv.NewMGRSEllipsoid(coordconv.EllipsoidWGS84)
+	if err != nil {
+		t.Fatalf("error creating mgrs converter: %s", err)
+	}
+
+	geo := s2.LatLngFromDegrees(39.5, -105.2)
+	expected, err := modis.ConvertFromGeodetic(geo, coordconv.MODIS250m)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	utmCoords, err := utm.ConvertFromGeodetic(geo, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	pixel, err := modis.ConvertFromUTM(utmCoords, coordconv.MODIS250m)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if pixel != expected {
+		t.Errorf("expected %+v from utm, got %+v", expected, pixel)
+	}
+
+	// 1 meter precision MGRS is well within a 250 meter pixel
+	mgrsCoords, err := mgrs.ConvertFromGeodetic(geo, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	pixel, err = modis.ConvertFromMGRS(mgrsCoords, coordconv.MODIS250m)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if pixel.Tile != expected.Tile || math.Abs(float64(pixel.Row-expected.Row)) > 1 ||
+		math.Abs(float64(pixel.Column-expected.Column)) > 1 {
+		t.Errorf("expected %+v from mgrs, got %+v", expected, pixel)
+	}
+}
+
+func TestMODISInvalid(t *testing.T) {
+	modis, err := coordconv.NewMODIS()
+	if err != nil {
+		t.Fatalf("error creating modis converter: %s", err)
+	}
+	if _, err := modis.ConvertFromGeodetic(s2.LatLngFromDegrees(0, 0), 1000); err == nil {
+		t.Errorf("expected an error for an invalid resolution")
+	}
+	for _, pixel := range []coordconv.MODISPixel{
+		{Tile: coordconv.MODISTile{H: 36, V: 0}, Resolution: coordconv.MODIS1km},
+		{Tile: coordconv.MODISTile{H: 0, V: -1}, Resolution: coordconv.MODIS1km},
+		{Tile: coordconv.MODISTile{H: 18, V: 9}, Resolution: coordconv.MODIS1km, Row: 1200},
+		// the north west corner of the grid is off the map
+		{Tile: coordconv.MODISTile{H: 0, V: 0}, Resolution: coordconv.MODIS1km},
+	} {
+		if _, err := modis.ConvertToGeodetic(pixel); err == nil {
+			t.Errorf("expected an error for %+v", pixel)
+		}
+	}
+}
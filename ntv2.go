@@ -0,0 +1,336 @@
+package coordconv
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// GridShift is an NTv2 grid shift, which shifts geodetic coordinates from one
+// datum to another by interpolating latitude and longitude shifts from a set
+// of regular grids.  Denser sub-grids take precedence over their parent grid.
+type GridShift struct {
+	FromSystem string // Name of the source datum
+	ToSystem   string // Name of the target datum
+	grids      []*ntv2Grid
+}
+
+// ntv2Grid is a single NTv2 grid.  Coordinates are in seconds with
+// longitude positive west, as in the file.
+type ntv2Grid struct {
+	name     string
+	parent   string
+	south    float64
+	north    float64
+	east     float64
+	west     float64
+	latInc   float64
+	lonInc   float64
+	rows     int
+	columns  int
+	shifts   []float64 // latitude and longitude shift pairs in seconds
+	children []*ntv2Grid
+}
+
+const ntv2RecordSize = 16
+const ntv2OverviewRecords = 11
+const ntv2SubGridRecords = 11
+const gridShiftMaxIterations = 10
+
+// LoadNTv2 reads an NTv2 grid shift file from disk.
+func LoadNTv2(filename string) (*GridShift, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadNTv2(bufio.NewReader(f))
+}
+
+// ReadNTv2 reads an NTv2 grid shift in the binary .gsb format, in either byte
+// order.
+func ReadNTv2(r io.Reader) (*GridShift, error) {
+	var record [ntv2RecordSize]byte
+	if _, err := io.ReadFull(r, record[:]); err != nil {
+		return nil, fmt.Errorf("error reading NTv2 header: %s", err)
+	}
+	if ntv2Keyword(record[:]) != "NUM_OREC" {
+		return nil, errors.New("not an NTv2 grid shift file")
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if binary.LittleEndian.Uint32(record[8:]) != ntv2OverviewRecords {
+		order = binary.BigEndian
+	}
+	if order.Uint32(record[8:]) != ntv2OverviewRecords {
+		return nil, errors.New("invalid NTv2 overview header")
+	}
+
+	header, err := readNTv2Header(r, order, ntv2OverviewRecords-1)
+	if err != nil {
+		return nil, err
+	}
+	numGrids, err := header.intValue("NUM_FILE")
+	if err != nil {
+		return nil, err
+	}
+	units, err := header.stringValue("GS_TYPE")
+	if err != nil {
+		return nil, err
+	}
+	var toSeconds float64
+	switch strings.ToUpper(units) {
+	case "SECONDS":
+		toSeconds = 1
+	case "MINUTES":
+		toSeconds = 60
+	case "DEGREES":
+		toSeconds = 3600
+	default:
+		return nil, fmt.Errorf("unsupported NTv2 units %q", units)
+	}
+
+	g := &GridShift{}
+	g.FromSystem, _ = header.stringValue("SYSTEM_F")
+	g.ToSystem, _ = header.stringValue("SYSTEM_T")
+
+	byName := map[string]*ntv2Grid{}
+	for i := 0; i < numGrids; i++ {
+		grid, err := readNTv2Grid(r, order, toSeconds)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := byName[grid.name]; ok {
+			return nil, fmt.Errorf("duplicate NTv2 sub-grid %s", grid.name)
+		}
+		byName[grid.name] = grid
+		if strings.EqualFold(grid.parent, "NONE") {
+			g.grids = append(g.grids, grid)
+		} else {
+			parent, ok := byName[grid.parent]
+			if !ok {
+				return nil, fmt.Errorf("NTv2 sub-grid %s has unknown parent %s", grid.name, grid.parent)
+			}
+			parent.children = append(parent.children, grid)
+		}
+	}
+	if len(g.grids) == 0 {
+		return nil, errors.New("NTv2 file contains no grids")
+	}
+	return g, nil
+}
+
+// Forward shifts geodetic coordinates from the source datum to the target
+// datum.
+func (g *GridShift) Forward(geodeticCoordinates s2.LatLng) (s2.LatLng, error) {
+	latitude := geodeticCoordinates.Lat.Radians()
+	longitude := geodeticCoordinates.Lng.Radians()
+	dLat, dLon, err := g.shift(latitude, longitude)
+	if err != nil {
+		return s2.LatLng{}, err
+	}
+	return s2.LatLng{Lat: s1.Angle(latitude + dLat), Lng: s1.Angle(longitude + dLon)}, nil
+}
+
+// Inverse shifts geodetic coordinates from the target datum back to the
+// source datum.  The grids are defined on the source datum, so the inverse is
+// found iteratively.
+func (g *GridShift) Inverse(geodeticCoordinates s2.LatLng) (s2.LatLng, error) {
+	targetLat := geodeticCoordinates.Lat.Radians()
+	targetLon := geodeticCoordinates.Lng.Radians()
+
+	latitude, longitude := targetLat, targetLon
+	for i := 0; i < gridShiftMaxIterations; i++ {
+		dLat, dLon, err := g.shift(latitude, longitude)
+		if err != nil {
+			return s2.LatLng{}, err
+		}
+		errLat := latitude + dLat - targetLat
+		errLon := longitude + dLon - targetLon
+		latitude -= errLat
+		longitude -= errLon
+		if math.Abs(errLat) < 1e-12 && math.Abs(errLon) < 1e-12 {
+			return s2.LatLng{Lat: s1.Angle(latitude), Lng: s1.Angle(longitude)}, nil
+		}
+	}
+	return s2.LatLng{}, errors.New("inverse grid shift did not converge")
+}
+
+// shift returns the interpolated latitude and longitude shift in radians at
+// the given position.
+func (g *GridShift) shift(latitude, longitude float64) (float64, float64, error) {
+	const secondsPerRadian = 180 * 3600 / math.Pi
+	lat := latitude * secondsPerRadian
+	lonWest := -longitude * secondsPerRadian
+
+	var grid *ntv2Grid
+	for _, root := range g.grids {
+		if root.contains(lat, lonWest) {
+			grid = root
+			break
+		}
+	}
+	if grid == nil {
+		return 0, 0, errors.New("point outside of grid shift area")
+	}
+	for found := true; found; {
+		found = false
+		for _, child := range grid.children {
+			if child.contains(lat, lonWest) {
+				grid = child
+				found = true
+				break
+			}
+		}
+	}
+
+	dLat, dLonWest := grid.interpolate(lat, lonWest)
+	return dLat / secondsPerRadian, -dLonWest / secondsPerRadian, nil
+}
+
+// contains reports whether the grid covers the point, in seconds with
+// longitude positive west.
+func (n *ntv2Grid) contains(lat, lonWest float64) bool {
+	return lat >= n.south && lat <= n.north && lonWest >= n.east && lonWest <= n.west
+}
+
+// interpolate bilinearly interpolates the latitude and longitude shifts at the
+// point, in seconds with longitude positive west.
+func (n *ntv2Grid) interpolate(lat, lonWest float64) (float64, float64) {
+	y := (lat - n.south) / n.latInc
+	x := (lonWest - n.east) / n.lonInc
+	row := int(math.Min(math.Floor(y), float64(n.rows-2)))
+	column := int(math.Min(math.Floor(x), float64(n.columns-2)))
+	if row < 0 {
+		row = 0
+	}
+	if column < 0 {
+		column = 0
+	}
+	y -= float64(row)
+	x -= float64(column)
+
+	node := func(r, c int) (float64, float64) {
+		i := 2 * (r*n.columns + c)
+		return n.shifts[i], n.shifts[i+1]
+	}
+	lat00, lon00 := node(row, column)
+	lat01, lon01 := node(row, column+1)
+	lat10, lon10 := node(row+1, column)
+	lat11, lon11 := node(row+1, column+1)
+
+	bilinear := func(v00, v01, v10, v11 float64) float64 {
+		return v00*(1-x)*(1-y) + v01*x*(1-y) + v10*(1-x)*y + v11*x*y
+	}
+	return bilinear(lat00, lat01, lat10, lat11), bilinear(lon00, lon01, lon10, lon11)
+}
+
+// ntv2Header holds the raw values of a header, keyed by keyword.
+type ntv2Header struct {
+	order  binary.ByteOrder
+	values map[string][]byte
+}
+
+func readNTv2Header(r io.Reader, order binary.ByteOrder, count int) (ntv2Header, error) {
+	h := ntv2Header{order: order, values: map[string][]byte{}}
+	for i := 0; i < count; i++ {
+		var record [ntv2RecordSize]byte
+		if _, err := io.ReadFull(r, record[:]); err != nil {
+			return ntv2Header{}, fmt.Errorf("error reading NTv2 header: %s", err)
+		}
+		h.values[ntv2Keyword(record[:])] = record[8:]
+	}
+	return h, nil
+}
+
+func (h ntv2Header) intValue(keyword string) (int, error) {
+	v, ok := h.values[keyword]
+	if !ok {
+		return 0, fmt.Errorf("NTv2 header missing %s", keyword)
+	}
+	return int(int32(h.order.Uint32(v))), nil
+}
+
+func (h ntv2Header) floatValue(keyword string) (float64, error) {
+	v, ok := h.values[keyword]
+	if !ok {
+		return 0, fmt.Errorf("NTv2 header missing %s", keyword)
+	}
+	return math.Float64frombits(h.order.Uint64(v)), nil
+}
+
+func (h ntv2Header) stringValue(keyword string) (string, error) {
+	v, ok := h.values[keyword]
+	if !ok {
+		return "", fmt.Errorf("NTv2 header missing %s", keyword)
+	}
+	return ntv2Keyword(v), nil
+}
+
+// ntv2Keyword returns the trimmed 8 character text at the start of b.
+func ntv2Keyword(b []byte) string {
+	return strings.TrimSpace(string(bytes.TrimRight(b[:8], "\x00")))
+}
+
+func readNTv2Grid(r io.Reader, order binary.ByteOrder, toSeconds float64) (*ntv2Grid, error) {
+	header, err := readNTv2Header(r, order, ntv2SubGridRecords)
+	if err != nil {
+		return nil, err
+	}
+	n := &ntv2Grid{}
+	if n.name, err = header.stringValue("SUB_NAME"); err != nil {
+		return nil, err
+	}
+	if n.parent, err = header.stringValue("PARENT"); err != nil {
+		return nil, err
+	}
+	for _, field := range []struct {
+		keyword string
+		value   *float64
+	}{
+		{"S_LAT", &n.south},
+		{"N_LAT", &n.north},
+		{"E_LONG", &n.east},
+		{"W_LONG", &n.west},
+		{"LAT_INC", &n.latInc},
+		{"LONG_INC", &n.lonInc},
+	} {
+		v, err := header.floatValue(field.keyword)
+		if err != nil {
+			return nil, err
+		}
+		*field.value = v * toSeconds
+	}
+	count, err := header.intValue("GS_COUNT")
+	if err != nil {
+		return nil, err
+	}
+	if n.latInc <= 0 || n.lonInc <= 0 || n.north <= n.south || n.west <= n.east {
+		return nil, fmt.Errorf("invalid NTv2 sub-grid %s extent", n.name)
+	}
+	n.rows = int(math.Round((n.north-n.south)/n.latInc)) + 1
+	n.columns = int(math.Round((n.west-n.east)/n.lonInc)) + 1
+	if n.rows*n.columns != count {
+		return nil, fmt.Errorf("NTv2 sub-grid %s has %d nodes, expected %d", n.name, count, n.rows*n.columns)
+	}
+
+	// each node is a latitude shift, longitude shift and their accuracies
+	nodes := make([]float32, 4*count)
+	if err := binary.Read(r, order, nodes); err != nil {
+		return nil, fmt.Errorf("error reading NTv2 sub-grid %s: %s", n.name, err)
+	}
+	n.shifts = make([]float64, 2*count)
+	for i := 0; i < count; i++ {
+		n.shifts[2*i] = float64(nodes[4*i]) * toSeconds
+		n.shifts[2*i+1] = float64(nodes[4*i+1]) * toSeconds
+	}
+	return n, nil
+}
@@ -0,0 +1,216 @@
+package coordconv_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+// ntv2Writer builds NTv2 grid shift files for testing.
+type ntv2Writer struct {
+	buf   bytes.Buffer
+	order binary.ByteOrder
+}
+
+func (w *ntv2Writer) keyword(k string) {
+	b := []byte("        ")
+	copy(b, k)
+	w.buf.Write(b)
+}
+
+func (w *ntv2Writer) int(k string, v int32) {
+	w.keyword(k)
+	binary.Write(&w.buf, w.order, v)
+	binary.Write(&w.buf, w.order, int32(0))
+}
+
+func (w *ntv2Writer) string(k, v string) {
+	w.keyword(k)
+	w.keyword(v)
+}
+
+func (w *ntv2Writer) float(k string, v float64) {
+	w.keyword(k)
+	binary.Write(&w.buf, w.order, v)
+}
+
+// grid writes a sub-grid with extents in degrees, longitude positive east,
+// and shifts in seconds given by fn.
+func (w *ntv2Writer) grid(name, parent string, south, north, west, east, inc float64,
+	fn func(lat, lng float64) (float64, float64)) {
+	rows := int(math.Round((north-south)/inc)) + 1
+	columns := int(math.Round((east-west)/inc)) + 1
+	w.string("SUB_NAME", name)
+	w.string("PARENT", parent)
+	w.string("CREATED", "20240101")
+	w.string("UPDATED", "20240101")
+	w.float("S_LAT", south*3600)
+	w.float("N_LAT", north*3600)
+	w.float("E_LONG", -east*3600)
+	w.float("W_LONG", -west*3600)
+	w.float("LAT_INC", inc*3600)
+	w.float("LONG_INC", inc*3600)
+	w.int("GS_COUNT", int32(rows*columns))
+	for r := 0; r < rows; r++ {
+		for c := 0; c < columns; c++ {
+			dLat, dLng := fn(south+float64(r)*inc, east-float64(c)*inc)
+			// longitude shifts are positive west
+			binary.Write(&w.buf, w.order, []float32{float32(dLat), float32(-dLng), 0, 0})
+		}
+	}
+}
+
+// parentShift is linear, so bilinear interpolation reproduces it exactly.
+func parentShift(lat, lng float64) (float64, float64) {
+	return 1 + 0.5*(lat-40), -2 + 0.25*(lng+80)
+}
+
+func childShift(lat, lng float64) (float64, float64) {
+	return 5, -3
+}
+
+func writeTestNTv2(t *testing.T, order binary.ByteOrder) string {
+	t.Helper()
+	w := &ntv2Writer{order: order}
+	w.int("NUM_OREC", 11)
+	w.int("NUM_SREC", 11)
+	w.int("NUM_FILE", 2)
+	w.string("GS_TYPE", "SECONDS")
+	w.string("VERSION", "NTv2.0")
+	w.string("SYSTEM_F", "NAD27")
+	w.string("SYSTEM_T", "NAD83")
+	w.float("MAJOR_F", 6378206.4)
+	w.float("MINOR_F", 6356583.8)
+	w.float("MAJOR_T", 6378137.0)
+	w.float("MINOR_T", 6356752.314)
+	w.grid("PARENT", "NONE", 40, 42, -80, -76, 1, parentShift)
+	w.grid("CHILD", "PARENT", 40.5, 41, -78, -77, 0.25, childShift)
+	w.keyword("END")
+	w.keyword("")
+
+	filename := filepath.Join(t.TempDir(), "test.gsb")
+	if err := os.WriteFile(filename, w.buf.Bytes(), 0644); err != nil {
+		t.Fatalf("error writing grid shift file: %s", err)
+	}
+	return filename
+}
+
+func TestNTv2(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		g, err := coordconv.LoadNTv2(writeTestNTv2(t, order))
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", order, err)
+		}
+		if g.FromSystem != "NAD27" || g.ToSystem != "NAD83" {
+			t.Errorf("%s: expected NAD27 to NAD83, got %s to %s", order, g.FromSystem, g.ToSystem)
+		}
+
+		testCases := []struct {
+			lat, lng float64
+			shift    func(lat, lng float64) (float64, float64)
+		}{
+			{40.3, -79.7, parentShift},
+			{41.9, -76.2, parentShift},
+			{41.99, -76.01, parentShift},
+			{40.7, -77.6, childShift},
+			{41.5, -77.5, parentShift},
+		}
+		for _, tc := range testCases {
+			geo := s2.LatLngFromDegrees(tc.lat, tc.lng)
+			shifted, err := g.Forward(geo)
+			if err != nil {
+				t.Fatalf("%s: expected no error, got %s", geo, err)
+			}
+			dLat, dLng := tc.shift(tc.lat, tc.lng)
+			expected := s2.LatLngFromDegrees(tc.lat+dLat/3600, tc.lng+dLng/3600)
+			if math.Abs(shifted.Lat.Degrees()-expected.Lat.Degrees())*3600 > 1e-5 ||
+				math.Abs(shifted.Lng.Degrees()-expected.Lng.Degrees())*3600 > 1e-5 {
+				t.Errorf("%s: expected %s, got %s", geo, expected, shifted)
+			}
+
+			back, err := g.Inverse(shifted)
+			if err != nil {
+				t.Fatalf("%s: expected no error, got %s", geo, err)
+			}
+			if back.Distance(geo).Degrees()*3600 > 1e-6 {
+				t.Errorf("%s: inverse expected %s, got %s", shifted, geo, back)
+			}
+		}
+
+		if _, err := g.Forward(s2.LatLngFromDegrees(45, -79)); err == nil {
+			t.Errorf("expected error for point outside of grid")
+		}
+	}
+}
+
+func TestNTv2Converters(t *testing.T) {
+	g, err := coordconv.LoadNTv2(writeTestNTv2(t, binary.LittleEndian))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	geo := s2.LatLngFromDegrees(40.7, -77.6)
+	shifted, err := g.Forward(geo)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	expectedUTM, err := coordconv.DefaultUTMConverter.ConvertFromGeodetic(shifted, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	utm, err := coordconv.DefaultUTMConverter.ConvertFromGeodeticGridShift(geo, g, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if utm != expectedUTM {
+		t.Errorf("expected %v, got %v", expectedUTM, utm)
+	}
+	back, err := coordconv.DefaultUTMConverter.ConvertToGeodeticGridShift(utm, g)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if back.Distance(geo).Radians()*ellipsoidSemiMajorAxis > 1e-3 {
+		t.Errorf("expected %s, got %s", geo, back)
+	}
+
+	expectedMGRS, err := coordconv.DefaultMGRSConverter.ConvertFromGeodetic(shifted, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	mgrs, err := coordconv.DefaultMGRSConverter.ConvertFromGeodeticGridShift(geo, g, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if mgrs != expectedMGRS {
+		t.Errorf("expected %s, got %s", expectedMGRS, mgrs)
+	}
+	back, err = coordconv.DefaultMGRSConverter.ConvertToGeodeticGridShift(mgrs, g)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if back.Distance(geo).Radians()*ellipsoidSemiMajorAxis > 2 {
+		t.Errorf("expected %s, got %s", geo, back)
+	}
+}
+
+func TestNTv2Invalid(t *testing.T) {
+	if _, err := coordconv.LoadNTv2(filepath.Join(t.TempDir(), "missing.gsb")); err == nil {
+		t.Errorf("expected error for missing file")
+	}
+	if _, err := coordconv.ReadNTv2(bytes.NewReader([]byte("not a grid shift file"))); err == nil {
+		t.Errorf("expected error for invalid file")
+	}
+	data, err := os.ReadFile(writeTestNTv2(t, binary.LittleEndian))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if _, err := coordconv.ReadNTv2(bytes.NewReader(data[:len(data)/2])); err == nil {
+		t.Errorf("expected error for truncated file")
+	}
+}
@@ -0,0 +1,362 @@
+package coordconv
+
+import (
+	"errors"
+	"math"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// ObliqueMercator provides conversions between geodetic coordinates (latitude
+// and longitude) and Hotine Oblique Mercator projection coordinates (easting
+// and northing).  The formulas are those of EPSG Guidance Note 7-2, for both
+// variant A (false easting and northing at the natural origin) and variant B
+// (easting and northing at the projection centre).  The grid is rotated from
+// the skew uv grid of the initial line by the rectified grid angle.
+type ObliqueMercator struct {
+	semiMajorAxis float64
+	flattening    float64
+	es            float64 // Eccentricity of ellipsoid
+
+	// Oblique Mercator projection Parameters
+	omercOriginLat     float64 // Latitude of the projection centre in radians
+	omercOriginLong    float64 // Longitude of the projection centre in radians
+	omercAzimuth       float64 // Azimuth of the initial line at the centre in radians
+	omercGridAngle     float64 // Rectified grid angle in radians
+	omercScaleFactor   float64 // Scale factor on the initial line
+	omercFalseEasting  float64 // False easting (variant A) or easting at the centre (variant B) in meters
+	omercFalseNorthing float64 // False northing (variant A) or northing at the centre (variant B) in meters
+	omercA             float64 // EPSG's A
+	omercB             float64 // EPSG's B
+	omercLnH           float64 // Natural logarithm of EPSG's H
+	omercSinGamma0     float64 // Sine of the azimuth of the initial line at the aposphere equator
+	omercCosGamma0     float64 // Cosine of the azimuth of the initial line at the aposphere equator
+	omercLambda0       float64 // Longitude of the natural origin relative to the centre
+	omercSinGridAngle  float64
+	omercCosGridAngle  float64
+	omercUc            float64 // u at the projection centre, variant B only
+}
+
+// NewObliqueMercatorA constructs a Hotine Oblique Mercator (variant A)
+// converter.  The initial line passes through the projection centre with the
+// given azimuth, and the false easting and northing are at the natural origin
+// where the initial line crosses the equator of the aposphere.
+func NewObliqueMercatorA(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+	originLatitude, azimuth, rectifiedGridAngle, scaleFactor,
+	falseEasting, falseNorthing float64) (*ObliqueMercator, error) {
+	return newObliqueMercator(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+		originLatitude, azimuth, rectifiedGridAngle, scaleFactor, falseEasting, falseNorthing, false)
+}
+
+// NewObliqueMercatorAEllipsoid constructs a Hotine Oblique Mercator (variant
+// A) converter for the given ellipsoid.
+func NewObliqueMercatorAEllipsoid(ellipsoid Ellipsoid, centralMeridian,
+	originLatitude, azimuth, rectifiedGridAngle, scaleFactor,
+	falseEasting, falseNorthing float64) (*ObliqueMercator, error) {
+	return NewObliqueMercatorA(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, centralMeridian,
+		originLatitude, azimuth, rectifiedGridAngle, scaleFactor, falseEasting, falseNorthing)
+}
+
+// NewObliqueMercatorB constructs a Hotine Oblique Mercator (variant B)
+// converter.  The initial line passes through the projection centre with the
+// given azimuth, and the easting and northing of the projection centre are
+// given.
+func NewObliqueMercatorB(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+	originLatitude, azimuth, rectifiedGridAngle, scaleFactor,
+	eastingAtCenter, northingAtCenter float64) (*ObliqueMercator, error) {
+	return newObliqueMercator(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+		originLatitude, azimuth, rectifiedGridAngle, scaleFactor, eastingAtCenter, northingAtCenter, true)
+}
+
+// NewObliqueMercatorBEllipsoid constructs a Hotine Oblique Mercator (variant
+// B) converter for the given ellipsoid.
+func NewObliqueMercatorBEllipsoid(ellipsoid Ellipsoid, centralMeridian,
+	originLatitude, azimuth, rectifiedGridAngle, scaleFactor,
+	eastingAtCenter, northingAtCenter float64) (*ObliqueMercator, error) {
+	return NewObliqueMercatorB(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, centralMeridian,
+		originLatitude, azimuth, rectifiedGridAngle, scaleFactor, eastingAtCenter, northingAtCenter)
+}
+
+// NewObliqueMercatorSwissLV95 constructs the CH1903+ / LV95 converter used in
+// Switzerland, on the Bessel 1841 ellipsoid.
+func NewObliqueMercatorSwissLV95() (*ObliqueMercator, error) {
+	bessel, err := EllipsoidByCode("BR")
+	if err != nil {
+		return nil, err
+	}
+	return NewObliqueMercatorBEllipsoid(bessel, dmsToRadians(7, 26, 22.50), dmsToRadians(46, 57, 8.66),
+		math.Pi/2, math.Pi/2, 1, 2600000, 1200000)
+}
+
+// NewObliqueMercatorBorneoRSO constructs the Timbalai 1948 / RSO Borneo
+// (meters) converter used in East Malaysia and Brunei, on the Everest (Sabah &
+// Sarawak) ellipsoid.
+func NewObliqueMercatorBorneoRSO() (*ObliqueMercator, error) {
+	everest, err := EllipsoidByCode("EB")
+	if err != nil {
+		return nil, err
+	}
+	return NewObliqueMercatorBEllipsoid(everest, 115*math.Pi/180, 4*math.Pi/180,
+		dmsToRadians(53, 18, 56.9537), dmsToRadians(53, 7, 48.3685), 0.99984, 590476.87, 442857.65)
+}
+
+// NewObliqueMercatorAlaskaZone1 constructs the NAD83 / Alaska zone 1 converter
+// of the State Plane Coordinate System, on the GRS 80 ellipsoid.
+func NewObliqueMercatorAlaskaZone1() (*ObliqueMercator, error) {
+	grs80, err := EllipsoidByCode("RF")
+	if err != nil {
+		return nil, err
+	}
+	return NewObliqueMercatorAEllipsoid(grs80, -dmsToRadians(133, 40, 0), 57*math.Pi/180,
+		math.Atan(-0.75), math.Atan(-0.75), 0.9999, 5000000, -5000000)
+}
+
+func newObliqueMercator(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+	originLatitude, azimuth, rectifiedGridAngle, scaleFactor,
+	falseEasting, falseNorthing float64, variantB bool) (*ObliqueMercator, error) {
+	const minScaleFactor = 0.3
+	const maxScaleFactor = 3.0
+	invF := 1 / ellipsoidFlattening
+	if ellipsoidSemiMajorAxis <= 0.0 {
+		return nil, errors.New("Semi-major axis must be greater than zero")
+	}
+	if (invF < 250) || (invF > 350) {
+		return nil, errors.New("Inverse flattening must be between 250 and 350")
+	}
+	if (originLatitude <= -math.Pi/2) || (originLatitude >= math.Pi/2) {
+		return nil, errors.New("Origin Latitude out of range")
+	}
+	if (centralMeridian < -math.Pi) || (centralMeridian > 2*math.Pi) {
+		return nil, errors.New("Origin Longitude out of range")
+	}
+	if (azimuth < -2*math.Pi) || (azimuth > 2*math.Pi) {
+		return nil, errors.New("Azimuth out of range")
+	}
+	if (originLatitude == 0) && (math.Abs(math.Cos(azimuth)) < 1.0e-10) {
+		return nil, errors.New("Azimuth cannot follow the equator")
+	}
+	if (rectifiedGridAngle < -2*math.Pi) || (rectifiedGridAngle > 2*math.Pi) {
+		return nil, errors.New("Rectified grid angle out of range")
+	}
+	if (scaleFactor < minScaleFactor) || (scaleFactor > maxScaleFactor) {
+		return nil, errors.New("Scale factor out of range")
+	}
+
+	if centralMeridian > math.Pi {
+		centralMeridian -= 2 * math.Pi
+	}
+	o := &ObliqueMercator{
+		semiMajorAxis:      ellipsoidSemiMajorAxis,
+		flattening:         ellipsoidFlattening,
+		omercOriginLat:     originLatitude,
+		omercOriginLong:    centralMeridian,
+		omercAzimuth:       azimuth,
+		omercGridAngle:     rectifiedGridAngle,
+		omercScaleFactor:   scaleFactor,
+		omercFalseEasting:  falseEasting,
+		omercFalseNorthing: falseNorthing,
+	}
+	es2 := 2*o.flattening - o.flattening*o.flattening
+	o.es = math.Sqrt(es2)
+
+	sinLat := math.Sin(originLatitude)
+	cosLat := math.Cos(originLatitude)
+	o.omercB = math.Sqrt(1 + es2*math.Pow(cosLat, 4)/(1-es2))
+	o.omercA = o.semiMajorAxis * o.omercB * scaleFactor * math.Sqrt(1-es2) / (1 - es2*sinLat*sinLat)
+	d := o.omercB * math.Sqrt(1-es2) / (cosLat * math.Sqrt(1-es2*sinLat*sinLat))
+	d2 := math.Max(1, d*d)
+	f := d + math.Copysign(math.Sqrt(d2-1), originLatitude)
+	// H = F t0^B, and the logarithm of t0 is minus the isometric latitude
+	o.omercLnH = math.Log(f) - o.omercB*o.isometricLatitude(originLatitude)
+	g := (f - 1/f) / 2
+	gamma0 := math.Asin(math.Sin(azimuth) / d)
+	o.omercSinGamma0, o.omercCosGamma0 = math.Sincos(gamma0)
+	o.omercLambda0 = -math.Asin(math.Max(-1, math.Min(1, g*math.Tan(gamma0)))) / o.omercB
+	o.omercSinGridAngle, o.omercCosGridAngle = math.Sincos(rectifiedGridAngle)
+	if variantB {
+		// u at the centre, where v is zero
+		_, o.omercUc = o.skewCoordinates(originLatitude, 0)
+	}
+	return o, nil
+}
+
+// ConvertFromGeodetic converts geodetic coordinates (latitude and longitude) to
+// Oblique Mercator coordinates (easting and northing), according to the
+// current ellipsoid and Oblique Mercator projection parameters.
+func (o *ObliqueMercator) ConvertFromGeodetic(geodeticCoordinates s2.LatLng) (MapCoords, error) {
+	longitude := geodeticCoordinates.Lng.Radians()
+	latitude := geodeticCoordinates.Lat.Radians()
+
+	if (latitude < -math.Pi/2) || (latitude > math.Pi/2) {
+		return MapCoords{}, errors.New("latitude out of range")
+	}
+	if (longitude < -math.Pi) || (longitude > 2*math.Pi) {
+		return MapCoords{}, errors.New("longitude out of range")
+	}
+
+	v, u := o.skewCoordinates(latitude, o.deltaLongitude(longitude))
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return MapCoords{}, errors.New("Point projects into infinity")
+	}
+	u -= o.omercUc
+	return MapCoords{
+		Easting:  o.omercFalseEasting + v*o.omercCosGridAngle + u*o.omercSinGridAngle,
+		Northing: o.omercFalseNorthing + u*o.omercCosGridAngle - v*o.omercSinGridAngle,
+	}, nil
+}
+
+// ConvertToGeodetic converts Oblique Mercator coordinates (easting and
+// northing) to geodetic coordinates (latitude and longitude) according to the
+// current ellipsoid and Oblique Mercator projection parameters.
+func (o *ObliqueMercator) ConvertToGeodetic(mapProjectionCoordinates MapCoords) (s2.LatLng, error) {
+	dx := mapProjectionCoordinates.Easting - o.omercFalseEasting
+	dy := mapProjectionCoordinates.Northing - o.omercFalseNorthing
+	v := dx*o.omercCosGridAngle - dy*o.omercSinGridAngle
+	u := dy*o.omercCosGridAngle + dx*o.omercSinGridAngle + o.omercUc
+
+	bOverA := o.omercB / o.omercA
+	// Q = exp(-B v / A), so S and T are the hyperbolic sine and cosine
+	s := math.Sinh(-bOverA * v)
+	t := math.Cosh(-bOverA * v)
+	sinBu, cosBu := math.Sincos(bOverA * u)
+	U := (sinBu*o.omercCosGamma0 + s*o.omercSinGamma0) / t
+	if math.IsNaN(U) || (math.Abs(U) > 1) {
+		return s2.LatLng{}, errors.New("Point is outside of projection area")
+	}
+
+	var latitude float64
+	if math.Abs(U) == 1 {
+		latitude = math.Copysign(math.Pi/2, U)
+	} else {
+		// invert the isometric latitude
+		psi := (math.Atanh(U) - o.omercLnH) / o.omercB
+		expPsi := math.Exp(psi)
+		latitude = 2*math.Atan(expPsi) - math.Pi/2
+		tempLat := 0.0
+		for count := 30; math.Abs(latitude-tempLat) > 1.0e-12 && count != 0; count-- {
+			tempLat = latitude
+			esSin := o.es * math.Sin(latitude)
+			latitude = 2*math.Atan(expPsi*math.Pow((1+esSin)/(1-esSin), o.es/2)) - math.Pi/2
+		}
+	}
+	longitude := o.omercOriginLong + o.omercLambda0 -
+		math.Atan2(s*o.omercCosGamma0-sinBu*o.omercSinGamma0, cosBu)/o.omercB
+	for longitude > math.Pi {
+		longitude -= 2 * math.Pi
+	}
+	for longitude < -math.Pi {
+		longitude += 2 * math.Pi
+	}
+	return s2.LatLng{Lat: s1.Angle(latitude), Lng: s1.Angle(longitude)}, nil
+}
+
+// ConvertFromGeodeticFactors converts geodetic coordinates (latitude and
+// longitude) to Oblique Mercator coordinates (easting and northing) and also
+// returns the meridian convergence and point scale factor at the point.
+func (o *ObliqueMercator) ConvertFromGeodeticFactors(geodeticCoordinates s2.LatLng) (MapCoords, GridFactors, error) {
+	mapCoords, err := o.ConvertFromGeodetic(geodeticCoordinates)
+	if err != nil {
+		return MapCoords{}, GridFactors{}, err
+	}
+	return mapCoords, o.gridFactors(geodeticCoordinates.Lat.Radians(), geodeticCoordinates.Lng.Radians()), nil
+}
+
+// ConvertToGeodeticFactors converts Oblique Mercator coordinates (easting and
+// northing) to geodetic coordinates (latitude and longitude) and also returns
+// the meridian convergence and point scale factor at the point.
+func (o *ObliqueMercator) ConvertToGeodeticFactors(mapProjectionCoordinates MapCoords) (s2.LatLng, GridFactors, error) {
+	geodeticCoordinates, err := o.ConvertToGeodetic(mapProjectionCoordinates)
+	if err != nil {
+		return s2.LatLng{}, GridFactors{}, err
+	}
+	return geodeticCoordinates, o.gridFactors(geodeticCoordinates.Lat.Radians(), geodeticCoordinates.Lng.Radians()), nil
+}
+
+// gridFactors computes the meridian convergence and point scale factor at
+// the given latitude and longitude.  The projection is the conformal mapping of
+// the ellipsoid onto the aposphere followed by a Mercator projection of the
+// aposphere about the initial line, so the factors are those of the two steps
+// combined.
+func (o *ObliqueMercator) gridFactors(latitude, longitude float64) GridFactors {
+	q := o.omercLnH + o.omercB*o.isometricLatitude(latitude)
+	s := math.Sinh(q)
+	t := math.Cosh(q)
+	sinBl, cosBl := math.Sincos(o.omercB * (o.deltaLongitude(longitude) - o.omercLambda0))
+	U := (-sinBl*o.omercCosGamma0 + s*o.omercSinGamma0) / t
+
+	// the derivatives of u and v along the meridian, up to a common factor
+	du := cosBl * t * o.omercCosGamma0
+	dv := -(o.omercSinGamma0 + sinBl*s*o.omercCosGamma0)
+	gamma := -o.omercGridAngle - math.Atan2(dv, du)
+	gamma = math.Remainder(gamma, 2*math.Pi)
+
+	esSin := o.es * math.Sin(latitude)
+	k := o.omercA * math.Sqrt(1-esSin*esSin) /
+		(o.semiMajorAxis * math.Cos(latitude) * t * math.Sqrt(1-U*U))
+	return GridFactors{Convergence: s1.Angle(gamma), PointScale: k}
+}
+
+// skewCoordinates returns v and u, the coordinates on the skew grid of the
+// initial line, of the given latitude and longitude from the projection
+// centre.  u is measured from the natural origin.
+func (o *ObliqueMercator) skewCoordinates(latitude, dlam float64) (v, u float64) {
+	aOverB := o.omercA / o.omercB
+	sinBl, cosBl := math.Sincos(o.omercB * (dlam - o.omercLambda0))
+	var U float64
+	if math.Abs(latitude) == math.Pi/2 {
+		// Q is zero or infinite at the poles
+		U = math.Copysign(o.omercSinGamma0, latitude)
+		u = math.Copysign(aOverB*math.Pi/2, latitude)
+	} else {
+		// Q = H / t^B, so S and T are the hyperbolic sine and cosine
+		q := o.omercLnH + o.omercB*o.isometricLatitude(latitude)
+		s := math.Sinh(q)
+		t := math.Cosh(q)
+		U = (-sinBl*o.omercCosGamma0 + s*o.omercSinGamma0) / t
+		u = aOverB * math.Atan2(s*o.omercCosGamma0+sinBl*o.omercSinGamma0, cosBl)
+	}
+	v = -aOverB * math.Atanh(U)
+	return v, u
+}
+
+// isometricLatitude returns the isometric latitude, minus the logarithm of
+// EPSG's t, of the given latitude.
+func (o *ObliqueMercator) isometricLatitude(latitude float64) float64 {
+	return math.Asinh(math.Tan(latitude)) - o.es*math.Atanh(o.es*math.Sin(latitude))
+}
+
+// deltaLongitude returns the longitude from the projection centre in
+// (-Pi, Pi].
+func (o *ObliqueMercator) deltaLongitude(longitude float64) float64 {
+	dlam := longitude - o.omercOriginLong
+	if dlam > math.Pi {
+		dlam -= 2 * math.Pi
+	}
+	if dlam < -math.Pi {
+		dlam += 2 * math.Pi
+	}
+	return dlam
+}
+
+// Parameters returns the ellipsoid and Oblique Mercator projection
+// parameters.  For variant B the false easting and northing are those of the
+// projection centre.
+func (o *ObliqueMercator) Parameters() ProjectionParameters {
+	return ProjectionParameters{
+		SemiMajorAxis:      o.semiMajorAxis,
+		Flattening:         o.flattening,
+		CentralMeridian:    o.omercOriginLong,
+		OriginLatitude:     o.omercOriginLat,
+		Azimuth:            o.omercAzimuth,
+		RectifiedGridAngle: o.omercGridAngle,
+		FalseEasting:       o.omercFalseEasting,
+		FalseNorthing:      o.omercFalseNorthing,
+		ScaleFactor:        o.omercScaleFactor,
+	}
+}
+
+// dmsToRadians converts degrees, minutes and seconds to radians.
+func dmsToRadians(degrees, minutes, seconds float64) float64 {
+	return (degrees + minutes/60 + seconds/3600) * math.Pi / 180
+}
@@ -0,0 +1,204 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func dms(degrees, minutes, seconds float64) float64 {
+	return (degrees + minutes/60 + seconds/3600) * math.Pi / 180
+}
+
+func TestObliqueMercator(t *testing.T) {
+	// EPSG Guidance Note 7-2, Timbalai 1948 / RSO Borneo (m)
+	rso, err := coordconv.NewObliqueMercatorBorneoRSO()
+	if err != nil {
+		t.Fatalf("error creating oblique mercator converter: %s", err)
+	}
+	geo := s2.LatLngFromDegrees(5+23.0/60+14.1129/3600, 115+48.0/60+19.8196/3600)
+	expected := coordconv.MapCoords{Easting: 679245.73, Northing: 596562.78}
+
+	const epsilon = 0.01
+	mc, err := rso.ConvertFromGeodetic(geo)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(mc.Easting-expected.Easting) > epsilon ||
+		math.Abs(mc.Northing-expected.Northing) > epsilon {
+		t.Errorf("expected %v, got %v", expected, mc)
+	}
+	geo2, err := rso.ConvertToGeodetic(expected)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > epsilon {
+		t.Errorf("expected %s, got %s", geo, geo2)
+	}
+
+	// the centre is at the easting and northing of the centre
+	center := s2.LatLngFromDegrees(4, 115)
+	mc, err = rso.ConvertFromGeodetic(center)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(mc.Easting-590476.87) > 1e-6 || math.Abs(mc.Northing-442857.65) > 1e-6 {
+		t.Errorf("expected the centre at the easting and northing of the centre, got %v", mc)
+	}
+}
+
+func TestObliqueMercatorVariants(t *testing.T) {
+	everest, _ := coordconv.EllipsoidByCode("EB")
+	azimuth := dms(53, 18, 56.9537)
+	gridAngle := dms(53, 7, 48.3685)
+	centralMeridian := 115 * math.Pi / 180
+	originLat := 4 * math.Pi / 180
+
+	// variant A places the false easting and northing at the natural origin,
+	// uc back along the initial line from the centre
+	natural, err := coordconv.NewObliqueMercatorAEllipsoid(everest, centralMeridian, originLat,
+		azimuth, gridAngle, 0.99984, 0, 0)
+	if err != nil {
+		t.Fatalf("error creating oblique mercator converter: %s", err)
+	}
+	mc, err := natural.ConvertFromGeodetic(s2.LatLngFromDegrees(4, 115))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	uc := math.Hypot(mc.Easting, mc.Northing)
+	if math.Abs(uc-738096.09) > 0.01 {
+		t.Errorf("expected uc 738096.09, got %.2f", uc)
+	}
+	if math.Abs(math.Atan2(mc.Easting, mc.Northing)-gridAngle) > 1e-12 {
+		t.Errorf("expected the centre along the rectified grid angle, got %v", mc)
+	}
+
+	variantA, err := coordconv.NewObliqueMercatorAEllipsoid(everest, centralMeridian, originLat,
+		azimuth, gridAngle, 0.99984, 590476.87-mc.Easting, 442857.65-mc.Northing)
+	if err != nil {
+		t.Fatalf("error creating oblique mercator converter: %s", err)
+	}
+	variantB, err := coordconv.NewObliqueMercatorBorneoRSO()
+	if err != nil {
+		t.Fatalf("error creating oblique mercator converter: %s", err)
+	}
+	for lng := 109.0; lng <= 120; lng++ {
+		for lat := 0.0; lat <= 8; lat++ {
+			geo := s2.LatLngFromDegrees(lat, lng)
+			mcA, err := variantA.ConvertFromGeodetic(geo)
+			if err != nil {
+				t.Fatalf("expected no error at %s, got %s", geo, err)
+			}
+			mcB, err := variantB.ConvertFromGeodetic(geo)
+			if err != nil {
+				t.Fatalf("expected no error at %s, got %s", geo, err)
+			}
+			if math.Abs(mcA.Easting-mcB.Easting) > 1e-6 || math.Abs(mcA.Northing-mcB.Northing) > 1e-6 {
+				t.Errorf("at %s expected variant A %v to match variant B %v", geo, mcA, mcB)
+			}
+		}
+	}
+}
+
+func TestObliqueMercatorPresets(t *testing.T) {
+	lv95, err := coordconv.NewObliqueMercatorSwissLV95()
+	if err != nil {
+		t.Fatalf("error creating oblique mercator converter: %s", err)
+	}
+	// the projection centre in Bern is at the origin of the grid
+	bern := s2.LatLngFromDegrees(46+57.0/60+8.66/3600, 7+26.0/60+22.50/3600)
+	mc, factors, err := lv95.ConvertFromGeodeticFactors(bern)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(mc.Easting-2600000) > 1e-6 || math.Abs(mc.Northing-1200000) > 1e-6 {
+		t.Errorf("expected 2600000 1200000, got %v", mc)
+	}
+	if math.Abs(factors.PointScale-1) > 1e-9 || math.Abs(factors.Convergence.Radians()) > 1e-9 {
+		t.Errorf("expected no distortion at the centre, got %+v", factors)
+	}
+
+	// NAD83 / Alaska zone 1 is variant A
+	alaska, err := coordconv.NewObliqueMercatorAlaskaZone1()
+	if err != nil {
+		t.Fatalf("error creating oblique mercator converter: %s", err)
+	}
+	params := alaska.Parameters()
+	if params.FalseEasting != 5000000 || params.FalseNorthing != -5000000 || params.ScaleFactor != 0.9999 {
+		t.Errorf("unexpected alaska zone 1 parameters %+v", params)
+	}
+	geo := s2.LatLngFromDegrees(57, -133-40.0/60)
+	_, factors, err = alaska.ConvertFromGeodeticFactors(geo)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(factors.PointScale-0.9999) > 1e-9 {
+		t.Errorf("expected point scale 0.9999 at the centre, got %.9f", factors.PointScale)
+	}
+}
+
+func TestObliqueMercatorRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		originLat, azimuth, gridAngle float64
+	}{
+		{45, 90, 90},
+		{4, 53.3, 53.1},
+		{57, -36.87, -36.87},
+		{-30, 20, 0},
+		{0, 45, 45},
+		{-60, 150, 150},
+	} {
+		omerc, err := coordconv.NewObliqueMercatorB(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+			10*math.Pi/180, tc.originLat*math.Pi/180, tc.azimuth*math.Pi/180, tc.gridAngle*math.Pi/180,
+			0.9999, 500000, 200000)
+		if err != nil {
+			t.Fatalf("error creating oblique mercator converter: %s", err)
+		}
+		for lng := -10.0; lng <= 30; lng += 5 {
+			for lat := tc.originLat - 20; lat <= tc.originLat+20; lat += 5 {
+				if math.Abs(lat) > 85 {
+					continue
+				}
+				geo := s2.LatLngFromDegrees(lat, lng)
+				mc, factors, err := omerc.ConvertFromGeodeticFactors(geo)
+				if err != nil {
+					t.Fatalf("origin %g: expected no error at %s, got %s", tc.originLat, geo, err)
+				}
+				geo2, factors2, err := omerc.ConvertToGeodeticFactors(mc)
+				if err != nil {
+					t.Fatalf("origin %g: expected no error in round trip at %s, got %s", tc.originLat, geo, err)
+				}
+				if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > 1e-3 {
+					t.Errorf("origin %g: expected %s, got %s", tc.originLat, geo, geo2)
+				}
+				if math.Abs(factors.PointScale-factors2.PointScale) > 1e-9 {
+					t.Errorf("origin %g: at %s expected matching point scales, got %f and %f",
+						tc.originLat, geo, factors.PointScale, factors2.PointScale)
+				}
+				checkGridFactors(t, "oblique mercator", omerc.ConvertFromGeodetic,
+					ellipsoidSemiMajorAxis, ellipsoidFlattening, geo, factors)
+			}
+		}
+	}
+}
+
+func TestObliqueMercatorInvalid(t *testing.T) {
+	if _, err := coordconv.NewObliqueMercatorA(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		0, math.Pi/2, math.Pi/4, math.Pi/4, 1, 0, 0); err == nil {
+		t.Errorf("expected an error for a centre at the pole")
+	}
+	if _, err := coordconv.NewObliqueMercatorA(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		0, 0, math.Pi/2, math.Pi/2, 1, 0, 0); err == nil {
+		t.Errorf("expected an error for an initial line along the equator")
+	}
+	if _, err := coordconv.NewObliqueMercatorB(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		0, math.Pi/4, 7, 0, 1, 0, 0); err == nil {
+		t.Errorf("expected an error for an azimuth out of range")
+	}
+	if _, err := coordconv.NewObliqueMercatorB(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		0, math.Pi/4, math.Pi/4, math.Pi/4, 0.1, 0, 0); err == nil {
+		t.Errorf("expected an error for a scale factor out of range")
+	}
+}
@@ -145,6 +145,79 @@ func NewPolarStereographic(ellipsoidSemiMajorAxis,
 	return p, nil
 }
 
+// NewPolarStereographicEllipsoid constructs a Polar Stereographic (Standard
+// Parallel) converter for the given ellipsoid.
+func NewPolarStereographicEllipsoid(ellipsoid Ellipsoid,
+	centralMeridian,
+	standardParallel,
+	falseEasting,
+	falseNorthing float64) (*PolarStereographic, error) {
+	return NewPolarStereographic(ellipsoid.SemiMajorAxis, ellipsoid.Flattening,
+		centralMeridian, standardParallel, falseEasting, falseNorthing)
+}
+
+// NewPolarStereographicFalseOrigin constructs a Polar Stereographic converter
+// with the false origin where the central meridian crosses the standard
+// parallel, EPSG's variant C.  Parameters reports the false easting and
+// northing of the pole.
+func NewPolarStereographicFalseOrigin(ellipsoidSemiMajorAxis,
+	ellipsoidFlattening,
+	centralMeridian,
+	standardParallel,
+	eastingAtFalseOrigin,
+	northingAtFalseOrigin float64) (*PolarStereographic, error) {
+	p, err := NewPolarStereographic(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		centralMeridian, standardParallel, eastingAtFalseOrigin, northingAtFalseOrigin)
+	if err != nil {
+		return nil, err
+	}
+	if math.Abs(math.Abs(p.polarStandardParallel)-math.Pi/2) <= 1.0e-10 {
+		return nil, errors.New("Standard parallel must not be a pole")
+	}
+	// the false origin is a radius of the standard parallel from the pole
+	rhoF := p.polaraMc
+	if p.isSouthernHemisphere {
+		rhoF = -rhoF
+	}
+	return NewPolarStereographic(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		centralMeridian, standardParallel, eastingAtFalseOrigin, northingAtFalseOrigin+rhoF)
+}
+
+// NewPolarStereographicFalseOriginEllipsoid constructs a Polar Stereographic
+// (False Origin) converter for the given ellipsoid.
+func NewPolarStereographicFalseOriginEllipsoid(ellipsoid Ellipsoid,
+	centralMeridian,
+	standardParallel,
+	eastingAtFalseOrigin,
+	northingAtFalseOrigin float64) (*PolarStereographic, error) {
+	return NewPolarStereographicFalseOrigin(ellipsoid.SemiMajorAxis, ellipsoid.Flattening,
+		centralMeridian, standardParallel, eastingAtFalseOrigin, northingAtFalseOrigin)
+}
+
+// NewPolarStereographicNSIDCNorth constructs the WGS 84 / NSIDC Sea Ice Polar
+// Stereographic North (EPSG:3413) converter.
+func NewPolarStereographicNSIDCNorth() (*PolarStereographic, error) {
+	return NewPolarStereographicEllipsoid(EllipsoidWGS84, -45*math.Pi/180, 70*math.Pi/180, 0, 0)
+}
+
+// NewPolarStereographicNSIDCSouth constructs the WGS 84 / NSIDC Sea Ice Polar
+// Stereographic South (EPSG:3976) converter.
+func NewPolarStereographicNSIDCSouth() (*PolarStereographic, error) {
+	return NewPolarStereographicEllipsoid(EllipsoidWGS84, 0, -70*math.Pi/180, 0, 0)
+}
+
+// NewPolarStereographicArctic constructs the WGS 84 / Arctic Polar
+// Stereographic (EPSG:3995) converter.
+func NewPolarStereographicArctic() (*PolarStereographic, error) {
+	return NewPolarStereographicEllipsoid(EllipsoidWGS84, 0, 71*math.Pi/180, 0, 0)
+}
+
+// NewPolarStereographicAntarctic constructs the WGS 84 / Antarctic Polar
+// Stereographic (EPSG:3031) converter.
+func NewPolarStereographicAntarctic() (*PolarStereographic, error) {
+	return NewPolarStereographicEllipsoid(EllipsoidWGS84, 0, -71*math.Pi/180, 0, 0)
+}
+
 // NewPolarStereographicScaleFactor ellipsoid parameters and Polar Stereograpic
 // (Scale Factor) projection parameters as inputs, and sets the corresponding
 // state variables.
@@ -286,6 +359,17 @@ func NewPolarStereographicScaleFactor(ellipsoidSemiMajorAxis,
 	return p, nil
 }
 
+// NewPolarStereographicScaleFactorEllipsoid constructs a Polar Stereographic
+// (Scale Factor) converter for the given ellipsoid.
+func NewPolarStereographicScaleFactorEllipsoid(ellipsoid Ellipsoid,
+	centralMeridian,
+	scaleFactor float64, hemisphere Hemisphere,
+	falseEasting,
+	falseNorthing float64) (*PolarStereographic, error) {
+	return NewPolarStereographicScaleFactor(ellipsoid.SemiMajorAxis, ellipsoid.Flattening,
+		centralMeridian, scaleFactor, hemisphere, falseEasting, falseNorthing)
+}
+
 // ConvertFromGeodetic converts geodetic coordinates (latitude and longitude) to
 // Polar Stereographic coordinates (easting and northing), according to the
 // current ellipsoid and Polar Stereographic projection parameters.
@@ -320,17 +404,7 @@ func (p *PolarStereographic) ConvertFromGeodetic(geodeticCoordinates s2.LatLng)
 		if dlam < -math.Pi {
 			dlam += 2 * math.Pi
 		}
-		slat := math.Sin(latitude)
-		essin := p.es * slat
-		powEs := p.polarPow(essin)
-		t := math.Tan(math.Pi/4-latitude/2.0) / powEs
-
-		var rho float64
-		if math.Abs(math.Abs(p.polarStandardParallel)-math.Pi/2) > 1.0e-10 {
-			rho = p.polaraMc * t / p.polarTC
-		} else {
-			rho = p.twoPolarA * t / p.polarK90
-		}
+		rho := p.polarRho(latitude)
 
 		if p.isSouthernHemisphere {
 			easting = -(rho*math.Sin(dlam) - p.polarFalseEasting)
@@ -431,6 +505,91 @@ func (p *PolarStereographic) ConvertToGeodetic(mapProjectionCoordinates MapCoord
 	return s2.LatLng{Lat: s1.Angle(latitude), Lng: s1.Angle(longitude)}, nil
 }
 
+// ConvertFromGeodeticFactors converts geodetic coordinates (latitude and
+// longitude) to Polar Stereographic coordinates (easting and northing) and
+// also returns the meridian convergence and point scale factor at the point.
+func (p *PolarStereographic) ConvertFromGeodeticFactors(geodeticCoordinates s2.LatLng) (MapCoords, GridFactors, error) {
+	mapCoords, err := p.ConvertFromGeodetic(geodeticCoordinates)
+	if err != nil {
+		return MapCoords{}, GridFactors{}, err
+	}
+	return mapCoords, p.gridFactors(geodeticCoordinates.Lat.Radians(), geodeticCoordinates.Lng.Radians()), nil
+}
+
+// ConvertToGeodeticFactors converts Polar Stereographic coordinates (easting
+// and northing) to geodetic coordinates (latitude and longitude) and also
+// returns the meridian convergence and point scale factor at the point.
+func (p *PolarStereographic) ConvertToGeodeticFactors(mapProjectionCoordinates MapCoords) (s2.LatLng, GridFactors, error) {
+	geodeticCoordinates, err := p.ConvertToGeodetic(mapProjectionCoordinates)
+	if err != nil {
+		return s2.LatLng{}, GridFactors{}, err
+	}
+	return geodeticCoordinates, p.gridFactors(geodeticCoordinates.Lat.Radians(), geodeticCoordinates.Lng.Radians()), nil
+}
+
+// gridFactors computes the meridian convergence and point scale factor at
+// the given latitude and longitude.
+func (p *PolarStereographic) gridFactors(latitude, longitude float64) GridFactors {
+	if p.isSouthernHemisphere {
+		longitude *= -1.0
+		latitude *= -1.0
+	}
+	dlam := longitude - p.polarCentralMeridian
+	if dlam > math.Pi {
+		dlam -= 2 * math.Pi
+	}
+	if dlam < -math.Pi {
+		dlam += 2 * math.Pi
+	}
+
+	// The projection is conformal, so the convergence is just the longitude
+	// difference (taken in the mirrored sense in the southern hemisphere).
+	// The scale is the ratio of the radius on the grid to the radius of the
+	// parallel on the ellipsoid.
+	k := p.polarScaleFactor
+	if math.Abs(math.Abs(latitude)-math.Pi/2) >= 1.0e-10 {
+		essin := p.es * math.Sin(latitude)
+		k = p.polarRho(latitude) * math.Sqrt(1.0-essin*essin) /
+			(p.semiMajorAxis * math.Cos(latitude))
+	}
+	return GridFactors{Convergence: s1.Angle(dlam), PointScale: k}
+}
+
+// polarRho returns the distance on the grid from the pole to a point at the
+// given latitude, measured in the northern hemisphere sense.
+func (p *PolarStereographic) polarRho(latitude float64) float64 {
+	slat := math.Sin(latitude)
+	essin := p.es * slat
+	powEs := p.polarPow(essin)
+	t := math.Tan(math.Pi/4-latitude/2.0) / powEs
+
+	if math.Abs(math.Abs(p.polarStandardParallel)-math.Pi/2) > 1.0e-10 {
+		return p.polaraMc * t / p.polarTC
+	}
+	return p.twoPolarA * t / p.polarK90
+}
+
 func (p *PolarStereographic) polarPow(esSin float64) float64 {
 	return math.Pow((1.0-esSin)/(1.0+esSin), p.esOverTwo)
 }
+
+// Parameters returns the ellipsoid and Polar Stereographic projection
+// parameters.
+func (p *PolarStereographic) Parameters() ProjectionParameters {
+	params := ProjectionParameters{
+		SemiMajorAxis:    p.semiMajorAxis,
+		Flattening:       p.flattening,
+		CentralMeridian:  p.polarCentralMeridian,
+		OriginLatitude:   math.Pi / 2,
+		StandardParallel: p.polarStandardParallel,
+		FalseEasting:     p.polarFalseEasting,
+		FalseNorthing:    p.polarFalseNorthing,
+		ScaleFactor:      p.polarScaleFactor,
+	}
+	if p.isSouthernHemisphere {
+		params.CentralMeridian = -params.CentralMeridian
+		params.OriginLatitude = -params.OriginLatitude
+		params.StandardParallel = -params.StandardParallel
+	}
+	return params
+}
@@ -0,0 +1,159 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestPolarStereographicFactors(t *testing.T) {
+	for _, standardParallel := range []float64{-71, 70} {
+		ps, err := coordconv.NewPolarStereographic(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+			-45*math.Pi/180, standardParallel*math.Pi/180, 0, 0)
+		if err != nil {
+			t.Fatalf("error creating polar stereographic converter: %s", err)
+		}
+		sign := math.Copysign(1, standardParallel)
+		for lng := -180.0; lng < 180; lng += 15 {
+			for lat := 45.0; lat < 90; lat += 5 {
+				geo := s2.LatLngFromDegrees(sign*lat, lng)
+				_, factors, err := ps.ConvertFromGeodeticFactors(geo)
+				if err != nil {
+					t.Fatalf("expected no error at %s, got %s", geo, err)
+				}
+				checkGridFactors(t, "polar stereographic", ps.ConvertFromGeodetic,
+					ellipsoidSemiMajorAxis, ellipsoidFlattening, geo, factors)
+			}
+		}
+
+		// true scale along the standard parallel
+		_, factors, err := ps.ConvertFromGeodeticFactors(s2.LatLngFromDegrees(standardParallel, 10))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if math.Abs(factors.PointScale-1) > 1e-12 {
+			t.Errorf("expected a point scale of 1 at the standard parallel, got %f", factors.PointScale)
+		}
+	}
+}
+
+func TestPolarStereographicVariants(t *testing.T) {
+	wgs84, _ := coordconv.EllipsoidByCode("WE")
+	intl, _ := coordconv.EllipsoidByCode("IN")
+
+	// EPSG Guidance Note 7-2 examples
+	variantA, err := coordconv.NewPolarStereographicScaleFactorEllipsoid(wgs84,
+		0, 0.994, coordconv.HemisphereNorth, 2000000, 2000000)
+	if err != nil {
+		t.Fatalf("error creating polar stereographic converter: %s", err)
+	}
+	variantB, err := coordconv.NewPolarStereographicEllipsoid(wgs84,
+		70*math.Pi/180, -71*math.Pi/180, 6000000, 6000000)
+	if err != nil {
+		t.Fatalf("error creating polar stereographic converter: %s", err)
+	}
+	variantC, err := coordconv.NewPolarStereographicFalseOriginEllipsoid(intl,
+		140*math.Pi/180, -67*math.Pi/180, 300000, 200000)
+	if err != nil {
+		t.Fatalf("error creating polar stereographic converter: %s", err)
+	}
+
+	testCases := []struct {
+		name     string
+		ps       *coordconv.PolarStereographic
+		geo      s2.LatLng
+		expected coordconv.MapCoords
+	}{
+		{"variant A", variantA, s2.LatLngFromDegrees(73, 44),
+			coordconv.MapCoords{Easting: 3320416.75, Northing: 632668.43}},
+		{"variant B", variantB, s2.LatLngFromDegrees(-75, 120),
+			coordconv.MapCoords{Easting: 7255380.79, Northing: 7053389.56}},
+		{"variant C", variantC, s2.LatLngFromDegrees(-(66 + 36.0/60 + 18.820/3600), 140+4.0/60+17.040/3600),
+			coordconv.MapCoords{Easting: 303169.52, Northing: 244055.72}},
+	}
+
+	const epsilon = 0.01
+	for _, tc := range testCases {
+		mc, err := tc.ps.ConvertFromGeodetic(tc.geo)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if math.Abs(mc.Easting-tc.expected.Easting) > epsilon ||
+			math.Abs(mc.Northing-tc.expected.Northing) > epsilon {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, mc)
+		}
+		geo, err := tc.ps.ConvertToGeodetic(tc.expected)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if geo.Distance(tc.geo).Radians()*ellipsoidSemiMajorAxis > epsilon {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.geo, geo)
+		}
+	}
+
+	// the false origin is on the standard parallel
+	mc, err := variantC.ConvertFromGeodetic(s2.LatLngFromDegrees(-67, 140))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(mc.Easting-300000) > 1e-6 || math.Abs(mc.Northing-200000) > 1e-6 {
+		t.Errorf("expected the false origin at 300000 200000, got %v", mc)
+	}
+	if _, err := coordconv.NewPolarStereographicFalseOriginEllipsoid(intl, 0, math.Pi/2, 0, 0); err == nil {
+		t.Errorf("expected an error for a false origin at the pole")
+	}
+}
+
+func TestPolarStereographicPresets(t *testing.T) {
+	testCases := []struct {
+		name             string
+		constructor      func() (*coordconv.PolarStereographic, error)
+		centralMeridian  float64
+		standardParallel float64
+	}{
+		{"nsidc north", coordconv.NewPolarStereographicNSIDCNorth, -45, 70},
+		{"nsidc south", coordconv.NewPolarStereographicNSIDCSouth, 0, -70},
+		{"arctic", coordconv.NewPolarStereographicArctic, 0, 71},
+		{"antarctic", coordconv.NewPolarStereographicAntarctic, 0, -71},
+	}
+	for _, tc := range testCases {
+		ps, err := tc.constructor()
+		if err != nil {
+			t.Fatalf("%s: error creating polar stereographic converter: %s", tc.name, err)
+		}
+		params := ps.Parameters()
+		if params.SemiMajorAxis != ellipsoidSemiMajorAxis || params.Flattening != ellipsoidFlattening ||
+			params.CentralMeridian != tc.centralMeridian*math.Pi/180 ||
+			params.StandardParallel != tc.standardParallel*math.Pi/180 ||
+			params.FalseEasting != 0 || params.FalseNorthing != 0 {
+			t.Errorf("%s: unexpected parameters %+v", tc.name, params)
+		}
+		mc, err := ps.ConvertFromGeodetic(s2.LatLngFromDegrees(math.Copysign(90, tc.standardParallel), 0))
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if mc.Easting != 0 || mc.Northing != 0 {
+			t.Errorf("%s: expected the pole at the grid origin, got %v", tc.name, mc)
+		}
+		// grid north is along the central meridian
+		mc, err = ps.ConvertFromGeodetic(s2.LatLngFromDegrees(tc.standardParallel, tc.centralMeridian))
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if math.Abs(mc.Easting) > 1e-6 {
+			t.Errorf("%s: expected the central meridian along the northing axis, got %v", tc.name, mc)
+		}
+	}
+
+	// the NSIDC grid puts 45E along the positive easting axis
+	nsidc, _ := coordconv.NewPolarStereographicNSIDCNorth()
+	mc, err := nsidc.ConvertFromGeodetic(s2.LatLngFromDegrees(80, 45))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(mc.Northing) > 1e-6 || mc.Easting <= 0 {
+		t.Errorf("expected 45E along the positive easting axis, got %v", mc)
+	}
+}
@@ -0,0 +1,46 @@
+package coordconv
+
+import "github.com/golang/geo/s2"
+
+// Projection is a map projection that converts between geodetic coordinates
+// (latitude and longitude) and projected map coordinates (easting and
+// northing).
+type Projection interface {
+	// ConvertFromGeodetic converts geodetic coordinates to map coordinates.
+	ConvertFromGeodetic(geodeticCoordinates s2.LatLng) (MapCoords, error)
+	// ConvertToGeodetic converts map coordinates to geodetic coordinates.
+	ConvertToGeodetic(mapProjectionCoordinates MapCoords) (s2.LatLng, error)
+	// Parameters returns the ellipsoid and projection parameters.
+	Parameters() ProjectionParameters
+}
+
+// ProjectionParameters are the ellipsoid and projection parameters of a
+// Projection.  Angles are in radians, distances in meters.
+type ProjectionParameters struct {
+	SemiMajorAxis      float64 // Ellipsoid semi-major axis
+	Flattening         float64 // Ellipsoid flattening
+	CentralMeridian    float64 // Longitude of origin
+	OriginLatitude     float64 // Latitude of origin
+	StandardParallel   float64 // Latitude of true scale, if any
+	StandardParallel2  float64 // Second latitude of true scale, if any
+	Azimuth            float64 // Azimuth of the initial line, if any
+	RectifiedGridAngle float64 // Angle from the initial line to grid north, if any
+	FalseEasting       float64
+	FalseNorthing      float64
+	ScaleFactor        float64 // Scale factor at the origin
+}
+
+var _ Projection = (*TransverseMercator)(nil)
+var _ Projection = (*PolarStereographic)(nil)
+var _ Projection = (*LambertConformalConic)(nil)
+var _ Projection = (*Mercator)(nil)
+var _ Projection = (*WebMercator)(nil)
+var _ Projection = (*AlbersEqualAreaConic)(nil)
+var _ Projection = (*LambertAzimuthalEqualArea)(nil)
+var _ Projection = (*AzimuthalEquidistant)(nil)
+var _ Projection = (*Gnomonic)(nil)
+var _ Projection = (*ObliqueMercator)(nil)
+var _ Projection = (*TransverseMercatorSouthOrientated)(nil)
+var _ Projection = (*Sinusoidal)(nil)
+var _ Projection = (*CassiniSoldner)(nil)
+var _ Projection = (*EquidistantCylindrical)(nil)
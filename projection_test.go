@@ -0,0 +1,167 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestProjectionParameters(t *testing.T) {
+	tm, err := coordconv.NewTransverseMercator(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		-2*math.Pi/180, 49*math.Pi/180, 400000, -100000, 0.9996012717, "WE")
+	if err != nil {
+		t.Fatalf("error creating transverse mercator converter: %s", err)
+	}
+	ps, err := coordconv.NewPolarStereographic(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		70*math.Pi/180, -71*math.Pi/180, 6000000, 6000000)
+	if err != nil {
+		t.Fatalf("error creating polar stereographic converter: %s", err)
+	}
+	lcc, err := coordconv.NewLambertConformalConic2SP(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		3*math.Pi/180, 46.5*math.Pi/180, 49*math.Pi/180, 44*math.Pi/180, 700000, 6600000)
+	if err != nil {
+		t.Fatalf("error creating lambert conformal conic converter: %s", err)
+	}
+
+	testCases := []struct {
+		name       string
+		projection coordconv.Projection
+		expected   coordconv.ProjectionParameters
+		geo        s2.LatLng
+	}{
+		{
+			name:       "transverse mercator",
+			projection: tm,
+			expected: coordconv.ProjectionParameters{
+				SemiMajorAxis:   ellipsoidSemiMajorAxis,
+				Flattening:      ellipsoidFlattening,
+				CentralMeridian: -2 * math.Pi / 180,
+				OriginLatitude:  49 * math.Pi / 180,
+				FalseEasting:    400000,
+				FalseNorthing:   -100000,
+				ScaleFactor:     0.9996012717,
+			},
+			geo: s2.LatLngFromDegrees(50.5, 0.5),
+		},
+		{
+			name:       "polar stereographic",
+			projection: ps,
+			expected: coordconv.ProjectionParameters{
+				SemiMajorAxis:    ellipsoidSemiMajorAxis,
+				Flattening:       ellipsoidFlattening,
+				CentralMeridian:  70 * math.Pi / 180,
+				OriginLatitude:   -math.Pi / 2,
+				StandardParallel: -71 * math.Pi / 180,
+				FalseEasting:     6000000,
+				FalseNorthing:    6000000,
+				ScaleFactor:      ps.Parameters().ScaleFactor,
+			},
+			geo: s2.LatLngFromDegrees(-75, 120),
+		},
+		{
+			name:       "lambert conformal conic",
+			projection: lcc,
+			expected: coordconv.ProjectionParameters{
+				SemiMajorAxis:     ellipsoidSemiMajorAxis,
+				Flattening:        ellipsoidFlattening,
+				CentralMeridian:   3 * math.Pi / 180,
+				OriginLatitude:    46.5 * math.Pi / 180,
+				StandardParallel:  49 * math.Pi / 180,
+				StandardParallel2: 44 * math.Pi / 180,
+				FalseEasting:      700000,
+				FalseNorthing:     6600000,
+				ScaleFactor:       1,
+			},
+			geo: s2.LatLngFromDegrees(48.85, 2.35),
+		},
+	}
+
+	for _, tc := range testCases {
+		params := tc.projection.Parameters()
+		if params != tc.expected {
+			t.Errorf("%s: expected parameters %+v, got %+v", tc.name, tc.expected, params)
+		}
+		mc, err := tc.projection.ConvertFromGeodetic(tc.geo)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		geo, err := tc.projection.ConvertToGeodetic(mc)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if geo.Distance(tc.geo).Radians()*params.SemiMajorAxis > 1e-3 {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.geo, geo)
+		}
+	}
+}
+
+// checkGridFactors compares convergence and point scale factor against a
+// finite difference of the forward conversion along the meridian.
+func checkGridFactors(t *testing.T, name string, forward func(s2.LatLng) (coordconv.MapCoords, error),
+	semiMajorAxis, flattening float64, geo s2.LatLng, factors coordconv.GridFactors) {
+	t.Helper()
+	const h = 1e-7
+	lat := geo.Lat.Radians()
+	lng := geo.Lng.Radians()
+	south, err := forward(s2.LatLng{Lat: s1.Angle(lat - h), Lng: s1.Angle(lng)})
+	if err != nil {
+		t.Fatalf("%s: expected no error, got %s", name, err)
+	}
+	north, err := forward(s2.LatLng{Lat: s1.Angle(lat + h), Lng: s1.Angle(lng)})
+	if err != nil {
+		t.Fatalf("%s: expected no error, got %s", name, err)
+	}
+	dE := north.Easting - south.Easting
+	dN := north.Northing - south.Northing
+
+	es2 := 2*flattening - flattening*flattening
+	sinLat := math.Sin(lat)
+	meridianRadius := semiMajorAxis * (1 - es2) / math.Pow(1-es2*sinLat*sinLat, 1.5)
+
+	convergence := -math.Atan2(dE, dN)
+	scale := math.Hypot(dE, dN) / (2 * h * meridianRadius)
+	if math.Abs(math.Remainder(convergence-factors.Convergence.Radians(), 2*math.Pi)) > 1e-7 {
+		t.Errorf("%s: at %s expected convergence %f, got %f", name, geo,
+			convergence*180/math.Pi, factors.Convergence.Degrees())
+	}
+	if math.Abs(scale-factors.PointScale) > 1e-7 {
+		t.Errorf("%s: at %s expected point scale %.9f, got %.9f", name, geo, scale, factors.PointScale)
+	}
+}
+
+// checkEqualArea compares the area of a small cell on the grid, from a finite
+// difference of the forward conversion, against its area on the ellipsoid.
+func checkEqualArea(t *testing.T, name string, forward func(s2.LatLng) (coordconv.MapCoords, error),
+	semiMajorAxis, flattening float64, geo s2.LatLng) {
+	t.Helper()
+	const h = 1e-6
+	lat := geo.Lat.Radians()
+	lng := geo.Lng.Radians()
+	var corners [4]coordconv.MapCoords
+	for i, d := range [][2]float64{{-h, -h}, {-h, h}, {h, h}, {h, -h}} {
+		mc, err := forward(s2.LatLng{Lat: s1.Angle(lat + d[0]), Lng: s1.Angle(lng + d[1])})
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", name, err)
+		}
+		corners[i] = mc
+	}
+	// half the cross product of the diagonals
+	e1 := corners[2].Easting - corners[0].Easting
+	n1 := corners[2].Northing - corners[0].Northing
+	e2 := corners[3].Easting - corners[1].Easting
+	n2 := corners[3].Northing - corners[1].Northing
+	gridArea := math.Abs(e1*n2-e2*n1) / 2
+
+	es2 := 2*flattening - flattening*flattening
+	sinLat := math.Sin(lat)
+	w2 := 1 - es2*sinLat*sinLat
+	meridianRadius := semiMajorAxis * (1 - es2) / math.Pow(w2, 1.5)
+	parallelRadius := semiMajorAxis * math.Cos(lat) / math.Sqrt(w2)
+	area := meridianRadius * parallelRadius * 4 * h * h
+	if math.Abs(gridArea/area-1) > 1e-6 {
+		t.Errorf("%s: at %s expected area ratio 1, got %.9f", name, geo, gridArea/area)
+	}
+}
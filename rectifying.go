@@ -0,0 +1,60 @@
+package coordconv
+
+import "math"
+
+// rectifying converts between geodetic latitudes and distances along the
+// meridian from the equator, for the projections that preserve distance along
+// the central meridian.
+type rectifying struct {
+	a        float64    // Rectifying radius, the length of a quarter meridian over Pi/2
+	forward  [6]float64 // Series for the rectifying latitude in the geodetic latitude
+	backward [6]float64 // Series for the geodetic latitude in the rectifying latitude
+}
+
+func newRectifying(semiMajorAxis, flattening float64) rectifying {
+	re := rectifying{}
+
+	// The series are to sixth order in the third flattening (Karney 2023, "On
+	// auxiliary latitudes"), accurate to a micrometer for the earth.
+	n := flattening / (2 - flattening)
+	n2 := n * n
+	n3 := n2 * n
+	n4 := n3 * n
+	n5 := n4 * n
+	n6 := n5 * n
+	re.a = semiMajorAxis / (1 + n) * (1 + n2/4 + n4/64 + n6/256)
+	re.forward[0] = -3.0/2*n + 9.0/16*n3 - 3.0/32*n5
+	re.forward[1] = 15.0/16*n2 - 15.0/32*n4 + 135.0/2048*n6
+	re.forward[2] = -35.0/48*n3 + 105.0/256*n5
+	re.forward[3] = 315.0/512*n4 - 189.0/512*n6
+	re.forward[4] = -693.0 / 1280 * n5
+	re.forward[5] = 1001.0 / 2048 * n6
+	re.backward[0] = 3.0/2*n - 27.0/32*n3 + 269.0/512*n5
+	re.backward[1] = 21.0/16*n2 - 55.0/32*n4 + 6759.0/4096*n6
+	re.backward[2] = 151.0/96*n3 - 417.0/128*n5
+	re.backward[3] = 1097.0/512*n4 - 15543.0/2560*n6
+	re.backward[4] = 8011.0 / 2560 * n5
+	re.backward[5] = 293393.0 / 61440 * n6
+	return re
+}
+
+// distance returns the distance along the meridian from the equator to the
+// given latitude.
+func (re *rectifying) distance(latitude float64) float64 {
+	mu := latitude
+	for k, c := range re.forward {
+		mu += c * math.Sin(2*float64(k+1)*latitude)
+	}
+	return re.a * mu
+}
+
+// latitude returns the geodetic latitude at the given distance along the
+// meridian from the equator.
+func (re *rectifying) latitude(distance float64) float64 {
+	mu := distance / re.a
+	latitude := mu
+	for k, c := range re.backward {
+		latitude += c * math.Sin(2*float64(k+1)*mu)
+	}
+	return latitude
+}
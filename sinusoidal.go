@@ -0,0 +1,135 @@
+package coordconv
+
+import (
+	"errors"
+	"math"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// Sinusoidal provides conversions between geodetic coordinates (latitude and
+// longitude) and Sinusoidal projection coordinates (easting and northing).
+// The projection is equal area, with true distances along every parallel and
+// along the central meridian.  A flattening of zero selects the sphere, as
+// used by the MODIS land products.
+type Sinusoidal struct {
+	semiMajorAxis float64
+	flattening    float64
+	es2           float64 // Eccentricity squared
+	rectifying    rectifying
+
+	// Sinusoidal projection Parameters
+	sinuCentralMeridian float64 // Longitude of origin in radians
+	sinuFalseEasting    float64 // False easting in meters
+	sinuFalseNorthing   float64 // False northing in meters
+}
+
+// NewSinusoidal constructs a Sinusoidal converter.
+func NewSinusoidal(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+	falseEasting, falseNorthing float64) (*Sinusoidal, error) {
+	invF := 1 / ellipsoidFlattening
+	if ellipsoidSemiMajorAxis <= 0.0 {
+		return nil, errors.New("Semi-major axis must be greater than zero")
+	}
+	if (ellipsoidFlattening != 0) && ((invF < 250) || (invF > 350)) {
+		return nil, errors.New("Inverse flattening must be between 250 and 350")
+	}
+	if (centralMeridian < -math.Pi) || (centralMeridian > 2*math.Pi) {
+		return nil, errors.New("Origin Longitude out of range")
+	}
+
+	if centralMeridian > math.Pi {
+		centralMeridian -= 2 * math.Pi
+	}
+	return &Sinusoidal{
+		semiMajorAxis:       ellipsoidSemiMajorAxis,
+		flattening:          ellipsoidFlattening,
+		es2:                 2*ellipsoidFlattening - ellipsoidFlattening*ellipsoidFlattening,
+		rectifying:          newRectifying(ellipsoidSemiMajorAxis, ellipsoidFlattening),
+		sinuCentralMeridian: centralMeridian,
+		sinuFalseEasting:    falseEasting,
+		sinuFalseNorthing:   falseNorthing,
+	}, nil
+}
+
+// NewSinusoidalEllipsoid constructs a Sinusoidal converter for the given
+// ellipsoid.
+func NewSinusoidalEllipsoid(ellipsoid Ellipsoid, centralMeridian,
+	falseEasting, falseNorthing float64) (*Sinusoidal, error) {
+	return NewSinusoidal(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, centralMeridian,
+		falseEasting, falseNorthing)
+}
+
+// ConvertFromGeodetic converts geodetic coordinates (latitude and longitude) to
+// Sinusoidal coordinates (easting and northing), according to the current
+// ellipsoid and Sinusoidal projection parameters.
+func (s *Sinusoidal) ConvertFromGeodetic(geodeticCoordinates s2.LatLng) (MapCoords, error) {
+	longitude := geodeticCoordinates.Lng.Radians()
+	latitude := geodeticCoordinates.Lat.Radians()
+
+	if (latitude < -math.Pi/2) || (latitude > math.Pi/2) {
+		return MapCoords{}, errors.New("latitude out of range")
+	}
+	if (longitude < -math.Pi) || (longitude > 2*math.Pi) {
+		return MapCoords{}, errors.New("longitude out of range")
+	}
+
+	dlam := longitude - s.sinuCentralMeridian
+	if dlam > math.Pi {
+		dlam -= 2 * math.Pi
+	}
+	if dlam < -math.Pi {
+		dlam += 2 * math.Pi
+	}
+	sinLat := math.Sin(latitude)
+	return MapCoords{
+		Easting:  s.sinuFalseEasting + s.semiMajorAxis*dlam*math.Cos(latitude)/math.Sqrt(1-s.es2*sinLat*sinLat),
+		Northing: s.sinuFalseNorthing + s.rectifying.distance(latitude),
+	}, nil
+}
+
+// ConvertToGeodetic converts Sinusoidal coordinates (easting and northing) to
+// geodetic coordinates (latitude and longitude) according to the current
+// ellipsoid and Sinusoidal projection parameters.  Points outside the outline
+// of the map, which is bounded by the meridians opposite the central
+// meridian, are rejected.
+func (s *Sinusoidal) ConvertToGeodetic(mapProjectionCoordinates MapCoords) (s2.LatLng, error) {
+	dx := mapProjectionCoordinates.Easting - s.sinuFalseEasting
+	dy := mapProjectionCoordinates.Northing - s.sinuFalseNorthing
+
+	quarterMeridian := s.rectifying.a * math.Pi / 2
+	if !(math.Abs(dy) <= quarterMeridian*(1+1.0e-12)) {
+		return s2.LatLng{}, errors.New("northing out of range")
+	}
+	latitude := math.Max(-math.Pi/2, math.Min(math.Pi/2, s.rectifying.latitude(dy)))
+
+	longitude := s.sinuCentralMeridian
+	parallelRadius := s.semiMajorAxis * math.Cos(latitude) /
+		math.Sqrt(1-s.es2*math.Sin(latitude)*math.Sin(latitude))
+	if parallelRadius > 1.0e-6 {
+		dlam := dx / parallelRadius
+		if math.Abs(dlam) > math.Pi*(1+1.0e-12) {
+			return s2.LatLng{}, errors.New("Point is outside of projection area")
+		}
+		longitude += dlam
+	}
+	if longitude > math.Pi {
+		longitude -= 2 * math.Pi
+	} else if longitude < -math.Pi {
+		longitude += 2 * math.Pi
+	}
+	return s2.LatLng{Lat: s1.Angle(latitude), Lng: s1.Angle(longitude)}, nil
+}
+
+// Parameters returns the ellipsoid and Sinusoidal projection parameters.
+func (s *Sinusoidal) Parameters() ProjectionParameters {
+	return ProjectionParameters{
+		SemiMajorAxis:   s.semiMajorAxis,
+		Flattening:      s.flattening,
+		CentralMeridian: s.sinuCentralMeridian,
+		FalseEasting:    s.sinuFalseEasting,
+		FalseNorthing:   s.sinuFalseNorthing,
+		ScaleFactor:     1.0,
+	}
+}
@@ -0,0 +1,86 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestSinusoidal(t *testing.T) {
+	clarke, _ := coordconv.EllipsoidByCode("CC")
+
+	// Snyder, Map Projections: A Working Manual, p. 365
+	sinu, err := coordconv.NewSinusoidalEllipsoid(clarke, -90*math.Pi/180, 0, 0)
+	if err != nil {
+		t.Fatalf("error creating sinusoidal converter: %s", err)
+	}
+	geo := s2.LatLngFromDegrees(-50, -75)
+	expected := coordconv.MapCoords{Easting: 1075471.5, Northing: -5540628.0}
+
+	const epsilon = 0.1
+	mc, err := sinu.ConvertFromGeodetic(geo)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(mc.Easting-expected.Easting) > epsilon ||
+		math.Abs(mc.Northing-expected.Northing) > epsilon {
+		t.Errorf("expected %v, got %v", expected, mc)
+	}
+	geo2, err := sinu.ConvertToGeodetic(expected)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if geo.Distance(geo2).Radians()*clarke.SemiMajorAxis > epsilon {
+		t.Errorf("expected %s, got %s", geo, geo2)
+	}
+}
+
+func TestSinusoidalRoundTrip(t *testing.T) {
+	for _, flattening := range []float64{ellipsoidFlattening, 0} {
+		sinu, err := coordconv.NewSinusoidal(ellipsoidSemiMajorAxis, flattening,
+			20*math.Pi/180, 1000000, 2000000)
+		if err != nil {
+			t.Fatalf("error creating sinusoidal converter: %s", err)
+		}
+		for lng := -175.0; lng <= 180; lng += 15 {
+			for lat := -90.0; lat <= 90; lat += 10 {
+				geo := s2.LatLngFromDegrees(lat, lng)
+				mc, err := sinu.ConvertFromGeodetic(geo)
+				if err != nil {
+					t.Fatalf("expected no error at %s, got %s", geo, err)
+				}
+				geo2, err := sinu.ConvertToGeodetic(mc)
+				if err != nil {
+					t.Fatalf("expected no error in round trip at %s, got %s", geo, err)
+				}
+				if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > 1e-3 {
+					t.Errorf("expected %s, got %s", geo, geo2)
+				}
+				// cells on the edge of the map are split in two
+				if math.Abs(lat) < 90 && lng != -160 {
+					checkEqualArea(t, "sinusoidal", sinu.ConvertFromGeodetic,
+						ellipsoidSemiMajorAxis, flattening, geo)
+				}
+			}
+		}
+
+		// the central meridian is true to scale, as on the sphere
+		mc, err := sinu.ConvertFromGeodetic(s2.LatLngFromDegrees(90, 20))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if flattening == 0 && math.Abs(mc.Northing-2000000-ellipsoidSemiMajorAxis*math.Pi/2) > 1e-6 {
+			t.Errorf("expected the pole a quarter circle from the equator, got %v", mc)
+		}
+		if _, err := sinu.ConvertToGeodetic(coordconv.MapCoords{Easting: 1000000 + 1.1*math.Pi*ellipsoidSemiMajorAxis,
+			Northing: 2000000}); err == nil {
+			t.Errorf("expected an error beyond the edge of the map")
+		}
+		if _, err := sinu.ConvertToGeodetic(coordconv.MapCoords{Easting: 1000000,
+			Northing: 2000000 + 2*ellipsoidSemiMajorAxis}); err == nil {
+			t.Errorf("expected an error beyond the pole")
+		}
+	}
+}
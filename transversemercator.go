@@ -9,7 +9,9 @@ import (
 	"github.com/golang/geo/s2"
 )
 
-const nTerms = 6
+// defaultSeriesOrder is the number of terms of the Kruger series used unless
+// changed with SetSeriesOrder.
+const defaultSeriesOrder = 6
 
 // TransverseMercator provides conversions between Geodetic coordinates
 // (latitude and longitude) and Transverse Mercator projection coordinates
@@ -38,6 +40,9 @@ type TransverseMercator struct {
 	// Maximum variance for easting and northing values
 	tranMercDeltaEasting  float64
 	tranMercDeltaNorthing float64
+
+	nTerms int                      // Terms of the Kruger series
+	exact  *exactTransverseMercator // Exact projection, nil for the series
 }
 
 // NewTransverseMercator constructs a new TransverseMercator converter.
@@ -54,6 +59,7 @@ func NewTransverseMercator(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralM
 		tranMercScaleFactor:   scaleFactor,
 		tranMercDeltaEasting:  20000000.0,
 		tranMercDeltaNorthing: 10000000.0,
+		nTerms:                defaultSeriesOrder,
 	}
 	t.semiMajorAxis = ellipsoidSemiMajorAxis
 	t.flattening = ellipsoidFlattening
@@ -100,6 +106,49 @@ func NewTransverseMercator(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralM
 	return t, nil
 }
 
+// NewTransverseMercatorEllipsoid constructs a new TransverseMercator converter
+// for the given ellipsoid.
+func NewTransverseMercatorEllipsoid(ellipsoid Ellipsoid, centralMeridian,
+	latitudeOfTrueScale, falseEasting, falseNorthing, scaleFactor float64) (*TransverseMercator, error) {
+	return NewTransverseMercator(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, centralMeridian,
+		latitudeOfTrueScale, falseEasting, falseNorthing, scaleFactor, ellipsoid.Code)
+}
+
+// NewTransverseMercatorExact constructs a new TransverseMercator converter
+// using the exact projection of Lee (1976) and Karney (2011) in place of the
+// Kruger series.  It is slower than the series, but accurate to a few
+// nanometers over the whole ellipsoid rather than only within 70 degrees of
+// the central meridian.
+func NewTransverseMercatorExact(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+	latitudeOfTrueScale, falseEasting, falseNorthing, scaleFactor float64,
+	ellipsoidCode string) (*TransverseMercator, error) {
+	t, err := NewTransverseMercator(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+		latitudeOfTrueScale, falseEasting, falseNorthing, scaleFactor, ellipsoidCode)
+	if err != nil {
+		return nil, err
+	}
+	t.exact = newExactTransverseMercator(ellipsoidFlattening)
+	// The far side of the ellipsoid extends the northings to twice the
+	// length of the quarter meridian, and the eastings out to the equator
+	// 90 degrees from the central meridian
+	eta, _, _, _ := t.exact.forward(0, math.Pi/2)
+	t.tranMercDeltaEasting = eta * scaleFactor * ellipsoidSemiMajorAxis
+	t.tranMercDeltaNorthing = 2 * t.exact.eu.e * scaleFactor * ellipsoidSemiMajorAxis
+	return t, nil
+}
+
+// SetSeriesOrder sets the number of terms, from 1 to 8, of the Kruger series
+// used by the projection.  The default is 6, which is accurate to well under
+// a millimeter within the 70 degree limit; 8 terms are accurate to a few
+// nanometers.  It has no effect on an exact projection.
+func (t *TransverseMercator) SetSeriesOrder(order int) error {
+	if order < 1 || order > len(t.tranMercACoeff) {
+		return errors.New("series order out of range")
+	}
+	t.nTerms = order
+	return nil
+}
+
 func (t *TransverseMercator) generateCoefficients(invfla float64, n1 *float64,
 	aCoeff []float64,
 	bCoeff []float64,
@@ -570,6 +619,12 @@ func (t *TransverseMercator) latLonToNorthingEasting(latitude, longitude float64
 	if lambda < -math.Pi {
 		lambda += (2 * math.Pi)
 	}
+	if t.exact != nil {
+		eta, xi, _, _ := t.exact.forward(latitude, lambda)
+		*easting = t.tranMercScaleFactor * t.semiMajorAxis * eta
+		*northing = t.tranMercScaleFactor * t.semiMajorAxis * xi
+		return nil
+	}
 	if err := t.checkLatLon(latitude, lambda); err != nil {
 		return err
 	}
@@ -610,7 +665,7 @@ func (t *TransverseMercator) latLonToNorthingEasting(latitude, longitude float64
 	xStar := 0.0
 	yStar := 0.0
 
-	for k := nTerms - 1; k >= 0; k-- {
+	for k := t.nTerms - 1; k >= 0; k-- {
 		xStar += t.tranMercACoeff[k] * s2ku[k] * c2kv[k]
 		yStar += t.tranMercACoeff[k] * c2ku[k] * s2kv[k]
 	}
@@ -624,10 +679,20 @@ func (t *TransverseMercator) latLonToNorthingEasting(latitude, longitude float64
 	return nil
 }
 
-func (t *TransverseMercator) convertFromGeodetic(geodeticCoordinates s2.LatLng) (MapCoords, error) {
+// ConvertFromGeodetic converts geodetic coordinates (latitude and longitude) to
+// Transverse Mercator coordinates (easting and northing), according to the
+// current ellipsoid and Transverse Mercator projection parameters.
+func (t *TransverseMercator) ConvertFromGeodetic(geodeticCoordinates s2.LatLng) (MapCoords, error) {
 	longitude := geodeticCoordinates.Lng.Radians()
 	latitude := geodeticCoordinates.Lat.Radians()
 
+	if (latitude < -math.Pi/2) || (latitude > math.Pi/2) {
+		return MapCoords{}, errors.New("latitude out of range")
+	}
+	if (longitude < -math.Pi) || (longitude > 2*math.Pi) {
+		return MapCoords{}, errors.New("longitude out of range")
+	}
+
 	if longitude > math.Pi {
 		longitude -= (2 * math.Pi)
 	}
@@ -645,8 +710,10 @@ func (t *TransverseMercator) convertFromGeodetic(geodeticCoordinates s2.LatLng)
 	if lambda < -math.Pi {
 		lambda += (2 * math.Pi)
 	}
-	if err := t.checkLatLon(latitude, lambda); err != nil {
-		return MapCoords{}, err
+	if t.exact == nil {
+		if err := t.checkLatLon(latitude, lambda); err != nil {
+			return MapCoords{}, err
+		}
 	}
 
 	var easting, northing float64
@@ -677,7 +744,10 @@ func (t *TransverseMercator) convertFromGeodetic(geodeticCoordinates s2.LatLng)
 	}, nil
 }
 
-func (t *TransverseMercator) convertToGeodetic(mapProjectionCoordinates MapCoords) (s2.LatLng, error) {
+// ConvertToGeodetic converts Transverse Mercator coordinates (easting and
+// northing) to geodetic coordinates (latitude and longitude) according to the
+// current ellipsoid and Transverse Mercator projection parameters.
+func (t *TransverseMercator) ConvertToGeodetic(mapProjectionCoordinates MapCoords) (s2.LatLng, error) {
 	easting := mapProjectionCoordinates.Easting
 	northing := mapProjectionCoordinates.Northing
 
@@ -737,9 +807,108 @@ func (t *TransverseMercator) convertToGeodetic(mapProjectionCoordinates MapCoord
 	return s2.LatLng{Lat: s1.Angle(latitude), Lng: s1.Angle(longitude)}, nil
 }
 
+// ConvertFromGeodeticFactors converts geodetic coordinates (latitude and
+// longitude) to Transverse Mercator coordinates (easting and northing) and
+// also returns the meridian convergence and point scale factor at the point.
+func (t *TransverseMercator) ConvertFromGeodeticFactors(geodeticCoordinates s2.LatLng) (MapCoords, GridFactors, error) {
+	mapCoords, err := t.ConvertFromGeodetic(geodeticCoordinates)
+	if err != nil {
+		return MapCoords{}, GridFactors{}, err
+	}
+	return mapCoords, t.gridFactors(geodeticCoordinates.Lat.Radians(), geodeticCoordinates.Lng.Radians()), nil
+}
+
+// ConvertToGeodeticFactors converts Transverse Mercator coordinates (easting
+// and northing) to geodetic coordinates (latitude and longitude) and also
+// returns the meridian convergence and point scale factor at the point.
+func (t *TransverseMercator) ConvertToGeodeticFactors(mapProjectionCoordinates MapCoords) (s2.LatLng, GridFactors, error) {
+	geodeticCoordinates, err := t.ConvertToGeodetic(mapProjectionCoordinates)
+	if err != nil {
+		return s2.LatLng{}, GridFactors{}, err
+	}
+	return geodeticCoordinates, t.gridFactors(geodeticCoordinates.Lat.Radians(), geodeticCoordinates.Lng.Radians()), nil
+}
+
+// gridFactors computes the meridian convergence and point scale factor at
+// the given latitude and longitude.  These are the convergence and scale of
+// the spherical (Gauss-Schreiber) transverse mercator, corrected by the
+// derivative of the Kruger series (Karney 2011, eqs. 13-14).
+func (t *TransverseMercator) gridFactors(latitude, longitude float64) GridFactors {
+	lambda := longitude - t.tranMercOriginLong
+	if lambda > math.Pi {
+		lambda -= (2 * math.Pi)
+	}
+	if lambda < -math.Pi {
+		lambda += (2 * math.Pi)
+	}
+	if t.exact != nil {
+		_, _, gamma, k := t.exact.forward(latitude, lambda)
+		return GridFactors{Convergence: s1.Angle(gamma), PointScale: k * t.tranMercScaleFactor}
+	}
+
+	cosLam := math.Cos(lambda)
+	sinLam := math.Sin(lambda)
+	cosPhi := math.Cos(latitude)
+	sinPhi := math.Sin(latitude)
+
+	var c2ku, s2ku [8]float64
+	var c2kv, s2kv [8]float64
+
+	// Conformal latitude, as in latLonToNorthingEasting
+	P := math.Exp(t.tranMercEps * aTanH(t.tranMercEps*sinPhi))
+	part1 := (1 + sinPhi) / P
+	part2 := (1 - sinPhi) * P
+	denom := part1 + part2
+	cosChi := 2 * cosPhi / denom
+	sinChi := (part1 - part2) / denom
+
+	var gamma, k float64
+	if math.Abs(latitude) >= math.Pi/2 {
+		// At the poles the convergence is the longitude difference and the
+		// scale reduces to sqrt(1-e^2) * exp(e * atanh(e))
+		gamma = lambda
+		if latitude < 0 {
+			gamma = -lambda
+		}
+		k = math.Sqrt(1-t.tranMercEps*t.tranMercEps) *
+			math.Exp(t.tranMercEps*aTanH(t.tranMercEps))
+		sinChi = math.Copysign(1, latitude)
+		cosChi = 0
+	} else {
+		gamma = math.Atan2(sinChi*sinLam, cosLam)
+		k = math.Sqrt(1-t.tranMercEps*t.tranMercEps*sinPhi*sinPhi) * cosChi /
+			(cosPhi * math.Hypot(sinChi, cosChi*cosLam))
+	}
+
+	U := aTanH(cosChi * sinLam)
+	V := math.Atan2(sinChi, cosChi*cosLam)
+
+	computeHyperbolicSeries(2.0*U, c2ku[:], s2ku[:])
+	computeTrigSeries(2.0*V, c2kv[:], s2kv[:])
+
+	// Derivative of the series, dzeta/dzeta' = p - iq
+	p := 1.0
+	q := 0.0
+	for k := t.nTerms - 1; k >= 0; k-- {
+		p += 2 * float64(k+1) * t.tranMercACoeff[k] * c2ku[k] * c2kv[k]
+		q += 2 * float64(k+1) * t.tranMercACoeff[k] * s2ku[k] * s2kv[k]
+	}
+
+	gamma += math.Atan2(q, p)
+	k *= t.tranMercK0R4 / t.semiMajorAxis * math.Hypot(p, q)
+	return GridFactors{Convergence: s1.Angle(gamma), PointScale: k}
+}
+
 func (t *TransverseMercator) northingEastingToLatLon(northing,
 	easting float64,
 	latitude, longitude *float64) {
+	if t.exact != nil {
+		var lambda float64
+		*latitude, lambda, _, _ = t.exact.reverse(easting/(t.tranMercScaleFactor*t.semiMajorAxis),
+			northing/(t.tranMercScaleFactor*t.semiMajorAxis))
+		*longitude = t.tranMercOriginLong + lambda
+		return
+	}
 	var c2kx, s2kx, c2ky, s2ky [8]float64
 
 	//  Undo offsets, scale change, and factor R4
@@ -756,7 +925,7 @@ func (t *TransverseMercator) northingEastingToLatLon(northing,
 	U := 0.0
 	V := 0.0
 
-	for k := nTerms - 1; k >= 0; k-- {
+	for k := t.nTerms - 1; k >= 0; k-- {
 		U += t.tranMercBCoeff[k] * s2kx[k] * c2ky[k]
 		V += t.tranMercBCoeff[k] * c2kx[k] * s2ky[k]
 	}
@@ -853,3 +1022,17 @@ func computeTrigSeries(twoY float64, c2ky,
 func aTanH(x float64) float64 {
 	return (0.5 * math.Log((1+x)/(1-x)))
 }
+
+// Parameters returns the ellipsoid and Transverse Mercator projection
+// parameters.
+func (t *TransverseMercator) Parameters() ProjectionParameters {
+	return ProjectionParameters{
+		SemiMajorAxis:   t.semiMajorAxis,
+		Flattening:      t.flattening,
+		CentralMeridian: t.tranMercOriginLong,
+		OriginLatitude:  t.tranMercOriginLat,
+		FalseEasting:    t.tranMercFalseEasting,
+		FalseNorthing:   t.TranMercFalseNorthing,
+		ScaleFactor:     t.tranMercScaleFactor,
+	}
+}
@@ -0,0 +1,172 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestTransverseMercator(t *testing.T) {
+	testCases := []struct {
+		name            string
+		semiMajorAxis   float64
+		flattening      float64
+		centralMeridian float64
+		originLatitude  float64
+		falseEasting    float64
+		falseNorthing   float64
+		scaleFactor     float64
+		geo             s2.LatLng
+		expected        coordconv.MapCoords
+	}{
+		{
+			// EPSG Guidance Note 7-2, OSGB 1936 / British National Grid
+			name:            "british national grid",
+			semiMajorAxis:   6377563.396,
+			flattening:      1 / 299.3249646,
+			centralMeridian: -2 * math.Pi / 180,
+			originLatitude:  49 * math.Pi / 180,
+			falseEasting:    400000,
+			falseNorthing:   -100000,
+			scaleFactor:     0.9996012717,
+			geo:             s2.LatLngFromDegrees(50.5, 0.5),
+			expected:        coordconv.MapCoords{Easting: 577274.99, Northing: 69740.50},
+		},
+		{
+			name:            "local grid origin",
+			semiMajorAxis:   ellipsoidSemiMajorAxis,
+			flattening:      ellipsoidFlattening,
+			centralMeridian: -84.25 * math.Pi / 180,
+			originLatitude:  33.75 * math.Pi / 180,
+			falseEasting:    10000,
+			falseNorthing:   20000,
+			scaleFactor:     1.0000412,
+			geo:             s2.LatLngFromDegrees(33.75, -84.25),
+			expected:        coordconv.MapCoords{Easting: 10000, Northing: 20000},
+		},
+		{
+			name:            "southern origin",
+			semiMajorAxis:   6378160,
+			flattening:      1 / 298.25,
+			centralMeridian: 147 * math.Pi / 180,
+			originLatitude:  -42 * math.Pi / 180,
+			falseEasting:    0,
+			falseNorthing:   0,
+			scaleFactor:     1,
+			geo:             s2.LatLngFromDegrees(-42, 147),
+			expected:        coordconv.MapCoords{Easting: 0, Northing: 0},
+		},
+	}
+
+	for _, tc := range testCases {
+		tm, err := coordconv.NewTransverseMercator(tc.semiMajorAxis, tc.flattening,
+			tc.centralMeridian, tc.originLatitude, tc.falseEasting, tc.falseNorthing,
+			tc.scaleFactor, "XX")
+		if err != nil {
+			t.Fatalf("%s: error creating transverse mercator converter: %s", tc.name, err)
+		}
+		mc, err := tm.ConvertFromGeodetic(tc.geo)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		const epsilon = 0.01
+		if math.Abs(mc.Easting-tc.expected.Easting) > epsilon {
+			t.Errorf("%s: expected Easting %f, got %f", tc.name, tc.expected.Easting, mc.Easting)
+		}
+		if math.Abs(mc.Northing-tc.expected.Northing) > epsilon {
+			t.Errorf("%s: expected Northing %f, got %f", tc.name, tc.expected.Northing, mc.Northing)
+		}
+		geo, err := tm.ConvertToGeodetic(tc.expected)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if geo.Distance(tc.geo).Radians()*tc.semiMajorAxis > epsilon {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.geo, geo)
+		}
+	}
+}
+
+func TestTransverseMercatorRoundTrip(t *testing.T) {
+	tm, err := coordconv.NewTransverseMercator(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		10*math.Pi/180, 45*math.Pi/180, 250000, 50000, 0.9999, "WE")
+	if err != nil {
+		t.Fatalf("error creating transverse mercator converter: %s", err)
+	}
+	const latInc = 0.5
+	const lngInc = 0.5
+	for lng := -50.0; lng < 70; lng += lngInc {
+		for lat := 0.0; lat < 90; lat += latInc {
+			geo := s2.LatLngFromDegrees(lat, lng)
+			mc, err := tm.ConvertFromGeodetic(geo)
+			if err != nil {
+				t.Fatalf("expected no error at %s, got %s", geo, err)
+			}
+			geo2, err := tm.ConvertToGeodetic(mc)
+			if err != nil {
+				t.Fatalf("expected no error in round trip, got one at %s (%s)", geo, err)
+			}
+			if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > 1e-3 {
+				t.Fatalf("expected %s, got %s", geo, geo2)
+			}
+		}
+	}
+	if _, err := tm.ConvertFromGeodetic(s2.LatLngFromDegrees(0, 90)); err == nil {
+		t.Errorf("expected an error more than 70 degrees from the central meridian")
+	}
+}
+
+func TestTransverseMercatorFactors(t *testing.T) {
+	tm, err := coordconv.NewTransverseMercator(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		-2*math.Pi/180, 0, 400000, 0, 0.9996012717, "WE")
+	if err != nil {
+		t.Fatalf("error creating transverse mercator converter: %s", err)
+	}
+	for lng := -60.0; lng <= 56; lng += 4 {
+		for lat := -88.0; lat <= 88; lat += 4 {
+			geo := s2.LatLngFromDegrees(lat, lng)
+			mc, factors, err := tm.ConvertFromGeodeticFactors(geo)
+			if err != nil {
+				t.Fatalf("expected no error at %s, got %s", geo, err)
+			}
+			checkGridFactors(t, "transverse mercator", tm.ConvertFromGeodetic,
+				ellipsoidSemiMajorAxis, ellipsoidFlattening, geo, factors)
+
+			_, inverseFactors, err := tm.ConvertToGeodeticFactors(mc)
+			if err != nil {
+				t.Fatalf("expected no error at %s, got %s", geo, err)
+			}
+			if math.Abs(inverseFactors.Convergence.Radians()-factors.Convergence.Radians()) > 1e-9 ||
+				math.Abs(inverseFactors.PointScale-factors.PointScale) > 1e-9 {
+				t.Errorf("expected inverse factors %v at %s, got %v", factors, geo, inverseFactors)
+			}
+		}
+	}
+
+	_, factors, err := tm.ConvertFromGeodeticFactors(s2.LatLngFromDegrees(0, -2))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if factors.Convergence != 0 || math.Abs(factors.PointScale-0.9996012717) > 1e-12 {
+		t.Errorf("expected no convergence and the origin scale factor, got %v", factors)
+	}
+
+	// the pole is the limit of nearby points
+	for _, lat := range []float64{-90, 90} {
+		for _, lng := range []float64{-30, 10, 45} {
+			_, pole, err := tm.ConvertFromGeodeticFactors(s2.LatLngFromDegrees(lat, lng))
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+			_, near, err := tm.ConvertFromGeodeticFactors(s2.LatLngFromDegrees(lat*(1-1e-9), lng))
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+			if math.Abs(pole.Convergence.Radians()-near.Convergence.Radians()) > 1e-9 ||
+				math.Abs(pole.PointScale-near.PointScale) > 1e-9 {
+				t.Errorf("expected pole factors %v, got %v", near, pole)
+			}
+		}
+	}
+}
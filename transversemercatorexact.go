@@ -0,0 +1,386 @@
+// This file is a port of TransverseMercatorExact.cpp from GeographicLib, and
+// unlike the rest of this package is not in the public domain but under the
+// MIT license:
+//
+// Copyright (c) Charles Karney (2008-2023) <karney@alum.mit.edu>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package coordconv
+
+import "math"
+
+// exactTransverseMercator is the exact Transverse Mercator projection of Lee
+// (1976), as formulated by Karney (2011), "Transverse Mercator with an
+// accuracy of a few nanometers".  It is evaluated with elliptic functions
+// rather than a series, so it stays accurate over the whole ellipsoid.
+// Coordinates are for a unit semi-major axis and unit scale factor.
+type exactTransverseMercator struct {
+	e      float64 // Eccentricity
+	mu     float64 // Eccentricity squared
+	mv     float64 // 1 - mu
+	eu     ellipticFunction
+	ev     ellipticFunction
+	tol2   float64
+	taytol float64
+}
+
+const exactTMMaxIterations = 10
+
+func newExactTransverseMercator(flattening float64) *exactTransverseMercator {
+	x := &exactTransverseMercator{}
+	x.mu = flattening * (2 - flattening)
+	x.mv = 1 - x.mu
+	x.e = math.Sqrt(x.mu)
+	x.eu = newEllipticFunction(x.mu)
+	x.ev = newEllipticFunction(x.mv)
+	x.tol2 = 0.1 * epsilon64
+	x.taytol = math.Pow(epsilon64, 0.6)
+	return x
+}
+
+// forward converts latitude and longitude from the central meridian, in
+// radians, to easting (eta) and northing (xi) along with the convergence and
+// point scale.
+func (x *exactTransverseMercator) forward(latitude, lambda float64) (eta, xi, gamma, k float64) {
+	// Explicitly enforce the parity
+	latSign, lonSign := 1.0, 1.0
+	if math.Signbit(latitude) {
+		latSign = -1
+	}
+	if math.Signbit(lambda) {
+		lonSign = -1
+	}
+	latitude *= latSign
+	lambda *= lonSign
+	backside := lambda > math.Pi/2
+	if backside {
+		if latitude == 0 {
+			latSign = -1
+		}
+		lambda = math.Pi - lambda
+	}
+
+	var u, v float64
+	if latitude == math.Pi/2 {
+		u = x.eu.k
+		v = 0
+	} else if latitude == 0 && lambda == math.Pi/2*(1-x.e) {
+		u = 0
+		v = x.ev.k
+	} else {
+		u, v = x.zetainv(taupf(math.Tan(latitude), x.e), lambda)
+	}
+
+	snu, cnu, dnu := x.eu.sncndn(u)
+	snv, cnv, dnv := x.ev.sncndn(v)
+	xi, eta = x.sigma(v, snu, cnu, dnu, snv, cnv, dnv)
+	if backside {
+		xi = 2*x.eu.e - xi
+	}
+	xi *= latSign
+	eta *= lonSign
+
+	if latitude == math.Pi/2 {
+		gamma = lambda
+		k = 1
+	} else {
+		// Recompute (tau, lambda) from (u, v) to improve the accuracy of the
+		// scale
+		taup, lam := x.zeta(snu, cnu, dnu, snv, cnv, dnv)
+		gamma, k = x.scale(tauf(taup, x.e), lam, snu, cnu, dnu, snv, cnv, dnv)
+	}
+	if backside {
+		gamma = math.Pi - gamma
+	}
+	gamma *= latSign * lonSign
+	return eta, xi, gamma, k
+}
+
+// reverse converts easting (eta) and northing (xi) to latitude and longitude
+// from the central meridian, in radians, along with the convergence and
+// point scale.
+func (x *exactTransverseMercator) reverse(eta, xi float64) (latitude, lambda, gamma, k float64) {
+	// Explicitly enforce the parity
+	xiSign, etaSign := 1.0, 1.0
+	if math.Signbit(xi) {
+		xiSign = -1
+	}
+	if math.Signbit(eta) {
+		etaSign = -1
+	}
+	xi *= xiSign
+	eta *= etaSign
+	backside := xi > x.eu.e
+	if backside {
+		xi = 2*x.eu.e - xi
+	}
+
+	var u, v float64
+	if xi == 0 && eta == x.ev.ke {
+		u = 0
+		v = x.ev.k
+	} else {
+		u, v = x.sigmainv(xi, eta)
+	}
+
+	snu, cnu, dnu := x.eu.sncndn(u)
+	snv, cnv, dnv := x.ev.sncndn(v)
+	if v != 0 || u != x.eu.k {
+		taup, lam := x.zeta(snu, cnu, dnu, snv, cnv, dnv)
+		tau := tauf(taup, x.e)
+		latitude = math.Atan(tau)
+		lambda = lam
+		gamma, k = x.scale(tau, lam, snu, cnu, dnu, snv, cnv, dnv)
+	} else {
+		latitude = math.Pi / 2
+		lambda = 0
+		gamma = 0
+		k = 1
+	}
+
+	if backside {
+		lambda = math.Pi - lambda
+		gamma = math.Pi - gamma
+	}
+	lambda *= etaSign
+	latitude *= xiSign
+	gamma *= xiSign * etaSign
+	return latitude, lambda, gamma, k
+}
+
+// zeta returns the conformal latitude, as taup = sinh(psi), and longitude of
+// the point w = u + iv (Lee 54.17).
+func (x *exactTransverseMercator) zeta(snu, cnu, dnu, snv, cnv, dnv float64) (taup, lambda float64) {
+	// Overflow value such that atan(overflow) = pi/2
+	overflow := 1 / (epsilon64 * epsilon64)
+	d1 := math.Sqrt(cnu*cnu + x.mv*(snu*snv)*(snu*snv))
+	d2 := math.Sqrt(x.mu*cnu*cnu + x.mv*cnv*cnv)
+	t1 := math.Copysign(overflow, snu)
+	if d1 != 0 {
+		t1 = snu * dnv / d1
+	}
+	t2 := math.Copysign(overflow, snu)
+	if d2 != 0 {
+		t2 = math.Sinh(x.e * math.Asinh(x.e*snu/d2))
+	}
+	taup = t1*math.Hypot(1, t2) - t2*math.Hypot(1, t1)
+	if d1 != 0 && d2 != 0 {
+		lambda = math.Atan2(dnu*snv, cnu*cnv) - x.e*math.Atan2(x.e*cnu*snv, dnu*cnv)
+	}
+	return taup, lambda
+}
+
+// dwdzeta returns the derivative dw/dzeta (Lee 54.21).
+func (x *exactTransverseMercator) dwdzeta(snu, cnu, dnu, snv, cnv, dnv float64) (du, dv float64) {
+	d := x.mv * sq(cnv*cnv+x.mu*sq(snu*snv))
+	du = cnu * dnu * dnv * (cnv*cnv - x.mu*sq(snu*snv)) / d
+	dv = -snu * snv * cnv * (sq(dnu*dnv) + x.mu*cnu*cnu) / d
+	return du, dv
+}
+
+// zetainv0 returns a starting point for zetainv, and whether it is already
+// accurate enough.
+func (x *exactTransverseMercator) zetainv0(psi, lambda float64) (u, v float64, done bool) {
+	if psi < -x.e*math.Pi/4 &&
+		lambda > (1-2*x.e)*math.Pi/2 &&
+		psi < lambda-(1-x.e)*math.Pi/2 {
+		// Log singularity at w0 = K(e) + iK'(e), the south pole
+		psix := 1 - psi/x.e
+		lamx := (math.Pi/2 - lambda) / x.e
+		u = math.Asinh(math.Sin(lamx)/math.Hypot(math.Cos(lamx), math.Sinh(psix))) * (1 + x.mu/2)
+		v = math.Atan2(math.Cos(lamx), math.Sinh(psix)) * (1 + x.mu/2)
+		u = x.eu.k - u
+		v = x.ev.k - v
+	} else if psi < x.e*math.Pi/2 && lambda > (1-2*x.e)*math.Pi/2 {
+		// Near w0 = iK'(e), zeta = zeta0 - (mv * e) / 3 * (w - w0)^3
+		dlam := lambda - (1-x.e)*math.Pi/2
+		rad := math.Hypot(psi, dlam)
+		ang := math.Atan2(dlam-psi, psi+dlam) - 0.75*math.Pi
+		done = rad < x.e*x.taytol
+		rad = math.Cbrt(3 / (x.mv * x.e) * rad)
+		ang /= 3
+		u = rad * math.Cos(ang)
+		v = rad*math.Sin(ang) + x.ev.k
+	} else {
+		// Use the spherical transverse mercator, scaled to put the pole in
+		// the right place (Lee 12.6)
+		v = math.Asinh(math.Sin(lambda) / math.Hypot(math.Cos(lambda), math.Sinh(psi)))
+		u = math.Atan2(math.Sinh(psi), math.Cos(lambda))
+		u *= x.eu.k / (math.Pi / 2)
+		v *= x.eu.k / (math.Pi / 2)
+	}
+	return u, v, done
+}
+
+// zetainv inverts zeta using Newton's method.
+func (x *exactTransverseMercator) zetainv(taup, lambda float64) (u, v float64) {
+	psi := math.Asinh(taup)
+	scal := 1 / math.Hypot(1, taup)
+	u, v, done := x.zetainv0(psi, lambda)
+	if done {
+		return u, v
+	}
+	stol2 := x.tol2 / sq(math.Max(psi, 1))
+	for i, trip := 0, false; i < exactTMMaxIterations; i++ {
+		snu, cnu, dnu := x.eu.sncndn(u)
+		snv, cnv, dnv := x.ev.sncndn(v)
+		tau1, lam1 := x.zeta(snu, cnu, dnu, snv, cnv, dnv)
+		du1, dv1 := x.dwdzeta(snu, cnu, dnu, snv, cnv, dnv)
+		tau1 = (tau1 - taup) * scal
+		lam1 -= lambda
+		delu := tau1*du1 - lam1*dv1
+		delv := tau1*dv1 + lam1*du1
+		u -= delu
+		v -= delv
+		if trip {
+			break
+		}
+		if !(delu*delu+delv*delv >= stol2) {
+			trip = true
+		}
+	}
+	return u, v
+}
+
+// sigma returns the projected northing (xi) and easting (eta) of the point
+// w = u + iv (Lee 55.4).
+func (x *exactTransverseMercator) sigma(v, snu, cnu, dnu, snv, cnv, dnv float64) (xi, eta float64) {
+	d := x.mu*cnu*cnu + x.mv*cnv*cnv
+	xi = x.eu.incompleteE(snu, cnu, dnu) - x.mu*snu*cnu*dnu/d
+	eta = v - x.ev.incompleteE(snv, cnv, dnv) + x.mv*snv*cnv*dnv/d
+	return xi, eta
+}
+
+// dwdsigma returns the derivative dw/dsigma, the reciprocal of Lee 55.9.
+func (x *exactTransverseMercator) dwdsigma(snu, cnu, dnu, snv, cnv, dnv float64) (du, dv float64) {
+	d := x.mv * sq(cnv*cnv+x.mu*sq(snu*snv))
+	dnr := dnu * cnv * dnv
+	dni := -x.mu * snu * cnu * snv
+	du = (dnr*dnr - dni*dni) / d
+	dv = 2 * dnr * dni / d
+	return du, dv
+}
+
+// sigmainv0 returns a starting point for sigmainv, and whether it is already
+// accurate enough.
+func (x *exactTransverseMercator) sigmainv0(xi, eta float64) (u, v float64, done bool) {
+	if eta > 1.25*x.ev.ke ||
+		(xi < -0.25*x.eu.e && xi < eta-x.ev.ke) {
+		// Simple pole at w0 = K(e) + iK'(e), sigma = sigma0 + 1/(w - w0)
+		dx := xi - x.eu.e
+		dy := eta - x.ev.ke
+		r2 := dx*dx + dy*dy
+		u = x.eu.k + dx/r2
+		v = x.ev.k - dy/r2
+	} else if (eta > 0.75*x.ev.ke && xi < 0.25*x.eu.e) || eta > x.ev.ke {
+		// Near w0 = iK'(e), sigma = sigma0 - mv / 3 * (w - w0)^3
+		deta := eta - x.ev.ke
+		rad := math.Hypot(xi, deta)
+		ang := math.Atan2(deta-xi, xi+deta) - 0.75*math.Pi
+		done = rad < 2*x.taytol
+		rad = math.Cbrt(3 / x.mv * rad)
+		ang /= 3
+		u = rad * math.Cos(ang)
+		v = rad*math.Sin(ang) + x.ev.k
+	} else {
+		// w = sigma * K(e) / E(e), which is correct in the limit e -> 0
+		u = xi * x.eu.k / x.eu.e
+		v = eta * x.eu.k / x.eu.e
+	}
+	return u, v, done
+}
+
+// sigmainv inverts sigma using Newton's method.
+func (x *exactTransverseMercator) sigmainv(xi, eta float64) (u, v float64) {
+	u, v, done := x.sigmainv0(xi, eta)
+	if done {
+		return u, v
+	}
+	for i, trip := 0, false; i < exactTMMaxIterations; i++ {
+		snu, cnu, dnu := x.eu.sncndn(u)
+		snv, cnv, dnv := x.ev.sncndn(v)
+		xi1, eta1 := x.sigma(v, snu, cnu, dnu, snv, cnv, dnv)
+		du1, dv1 := x.dwdsigma(snu, cnu, dnu, snv, cnv, dnv)
+		xi1 -= xi
+		eta1 -= eta
+		delu := xi1*du1 - eta1*dv1
+		delv := xi1*dv1 + eta1*du1
+		u -= delu
+		v -= delv
+		if trip {
+			break
+		}
+		if !(delu*delu+delv*delv >= x.tol2) {
+			trip = true
+		}
+	}
+	return u, v
+}
+
+// scale returns the meridian convergence and point scale (Lee 55.12 and
+// 55.13).
+func (x *exactTransverseMercator) scale(tau, lambda, snu, cnu, dnu, snv, cnv, dnv float64) (gamma, k float64) {
+	sec2 := 1 + tau*tau
+	gamma = math.Atan2(x.mv*snu*snv*cnv, cnu*dnu*dnv)
+	k = math.Sqrt(x.mv+x.mu/sec2) * math.Sqrt(sec2) *
+		math.Sqrt((x.mv*snv*snv+sq(cnu*dnv))/(x.mu*cnu*cnu+x.mv*cnv*cnv))
+	return gamma, k
+}
+
+func sq(x float64) float64 {
+	return x * x
+}
+
+// taupf returns tan(chi), the tangent of the conformal latitude, given tau =
+// tan(phi), the tangent of the geodetic latitude.
+func taupf(tau, e float64) float64 {
+	if math.IsInf(tau, 0) {
+		return tau
+	}
+	tau1 := math.Hypot(1, tau)
+	sig := math.Sinh(e * aTanH(e*tau/tau1))
+	return math.Hypot(1, sig)*tau - sig*tau1
+}
+
+// tauf inverts taupf using Newton's method.
+func tauf(taup, e float64) float64 {
+	const maxIterations = 5
+	tol := math.Sqrt(epsilon64) / 10
+	tauMax := 2 / math.Sqrt(epsilon64)
+	e2m := 1 - e*e
+	tau := taup / e2m
+	if math.Abs(taup) > 70 {
+		tau = taup * math.Exp(e*aTanH(e))
+	}
+	stol := tol * math.Max(1, math.Abs(taup))
+	if !(math.Abs(tau) < tauMax) {
+		return tau
+	}
+	for i := 0; i < maxIterations; i++ {
+		taupa := taupf(tau, e)
+		dtau := (taup - taupa) * (1 + e2m*tau*tau) /
+			(e2m * math.Hypot(1, tau) * math.Hypot(1, taupa))
+		tau += dtau
+		if !(math.Abs(dtau) >= stol) {
+			break
+		}
+	}
+	return tau
+}
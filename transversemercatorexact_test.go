@@ -0,0 +1,532 @@
+package coordconv_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+// bigPrec is the precision of the reference computations, in bits.
+const bigPrec = 256
+
+const bigPi = "3.14159265358979323846264338327950288419716939937510582097494459230781640628620899862803482534211706798"
+
+func bigFloat(x float64) *big.Float {
+	return new(big.Float).SetPrec(bigPrec).SetFloat64(x)
+}
+
+func bigRat(num, den int64) *big.Float {
+	r := new(big.Float).SetPrec(bigPrec).SetInt64(num)
+	return r.Quo(r, bigFloat(float64(den)))
+}
+
+func bigAdd(x, y *big.Float) *big.Float { return new(big.Float).SetPrec(bigPrec).Add(x, y) }
+func bigSub(x, y *big.Float) *big.Float { return new(big.Float).SetPrec(bigPrec).Sub(x, y) }
+func bigMul(x, y *big.Float) *big.Float { return new(big.Float).SetPrec(bigPrec).Mul(x, y) }
+func bigQuo(x, y *big.Float) *big.Float { return new(big.Float).SetPrec(bigPrec).Quo(x, y) }
+func bigSqrt(x *big.Float) *big.Float   { return new(big.Float).SetPrec(bigPrec).Sqrt(x) }
+
+// bigTaylor sums the power series with the given term ratio until the terms
+// no longer affect the result.
+func bigTaylor(first *big.Float, ratio func(n int) *big.Float) *big.Float {
+	sum := new(big.Float).SetPrec(bigPrec).Set(first)
+	term := new(big.Float).SetPrec(bigPrec).Set(first)
+	for n := 1; term.Sign() != 0 && term.MantExp(nil)-sum.MantExp(nil) > -bigPrec-8; n++ {
+		term.Mul(term, ratio(n))
+		sum.Add(sum, term)
+	}
+	return sum
+}
+
+func bigExp(x *big.Float) *big.Float {
+	// halve the argument until the series converges quickly, then square
+	halvings := 0
+	r := new(big.Float).SetPrec(bigPrec).Set(x)
+	for r.Cmp(bigFloat(0.25)) > 0 || r.Cmp(bigFloat(-0.25)) < 0 {
+		r.Quo(r, bigFloat(2))
+		halvings++
+	}
+	e := bigTaylor(bigFloat(1), func(n int) *big.Float { return bigQuo(r, bigFloat(float64(n))) })
+	for ; halvings > 0; halvings-- {
+		e.Mul(e, e)
+	}
+	return e
+}
+
+func bigLog(x *big.Float) *big.Float {
+	f, _ := x.Float64()
+	y := bigFloat(math.Log(f))
+	for i := 0; i < 4; i++ {
+		// Halley's method, y += 2 (x - e^y) / (x + e^y)
+		ey := bigExp(y)
+		y = bigAdd(y, bigQuo(bigMul(bigFloat(2), bigSub(x, ey)), bigAdd(x, ey)))
+	}
+	return y
+}
+
+func bigSin(x *big.Float) *big.Float {
+	x2 := bigMul(x, x)
+	return bigTaylor(x, func(n int) *big.Float {
+		return bigQuo(x2, bigFloat(-float64(2*n*(2*n+1))))
+	})
+}
+
+func bigCos(x *big.Float) *big.Float {
+	x2 := bigMul(x, x)
+	return bigTaylor(bigFloat(1), func(n int) *big.Float {
+		return bigQuo(x2, bigFloat(-float64(2*n*(2*n-1))))
+	})
+}
+
+func bigSinh(x *big.Float) *big.Float {
+	ex := bigExp(x)
+	return bigQuo(bigSub(ex, bigQuo(bigFloat(1), ex)), bigFloat(2))
+}
+
+func bigCosh(x *big.Float) *big.Float {
+	ex := bigExp(x)
+	return bigQuo(bigAdd(ex, bigQuo(bigFloat(1), ex)), bigFloat(2))
+}
+
+func bigAsinh(x *big.Float) *big.Float {
+	return bigLog(bigAdd(x, bigSqrt(bigAdd(bigMul(x, x), bigFloat(1)))))
+}
+
+func bigAtanh(x *big.Float) *big.Float {
+	return bigQuo(bigLog(bigQuo(bigAdd(bigFloat(1), x), bigSub(bigFloat(1), x))), bigFloat(2))
+}
+
+func bigAtan2(y, x *big.Float) *big.Float {
+	fy, _ := y.Float64()
+	fx, _ := x.Float64()
+	t := bigFloat(math.Atan2(fy, fx))
+	for i := 0; i < 4; i++ {
+		// t += tan(atan2(y, x) - t), to third order
+		s, c := bigSin(t), bigCos(t)
+		t = bigAdd(t, bigQuo(bigSub(bigMul(y, c), bigMul(x, s)), bigAdd(bigMul(x, c), bigMul(y, s))))
+	}
+	return t
+}
+
+// bigRadians converts degrees to radians.
+func bigRadians(degrees float64) *big.Float {
+	pi, _ := new(big.Float).SetPrec(bigPrec).SetString(bigPi)
+	return bigQuo(bigMul(bigFloat(degrees), pi), bigFloat(180))
+}
+
+// krugerReference projects a point with the Kruger series to eighth order in
+// the third flattening (Karney 2011, eqs. 7-11), evaluated with 256 bits of
+// precision.  Within 35 degrees of the central meridian the truncation error
+// of the series is well under a micrometer.
+func krugerReference(a, f, k0, latitude, lambda float64) (easting, northing float64) {
+	bf := bigFloat(f)
+	n := bigQuo(bf, bigSub(bigFloat(2), bf))
+	e := bigSqrt(bigMul(bf, bigSub(bigFloat(2), bf)))
+	var np [9]*big.Float
+	np[0] = bigFloat(1)
+	for i := 1; i < len(np); i++ {
+		np[i] = bigMul(np[i-1], n)
+	}
+	series := func(power int, terms ...[2]int64) *big.Float {
+		// terms are the coefficients of successive powers of n
+		s := bigFloat(0)
+		for i, t := range terms {
+			s = bigAdd(s, bigMul(bigRat(t[0], t[1]), np[power+i]))
+		}
+		return s
+	}
+	alpha := []*big.Float{
+		series(1, [2]int64{1, 2}, [2]int64{-2, 3}, [2]int64{5, 16}, [2]int64{41, 180},
+			[2]int64{-127, 288}, [2]int64{7891, 37800}, [2]int64{72161, 387072}, [2]int64{-18975107, 50803200}),
+		series(2, [2]int64{13, 48}, [2]int64{-3, 5}, [2]int64{557, 1440}, [2]int64{281, 630},
+			[2]int64{-1983433, 1935360}, [2]int64{13769, 28800}, [2]int64{148003883, 174182400}),
+		series(3, [2]int64{61, 240}, [2]int64{-103, 140}, [2]int64{15061, 26880},
+			[2]int64{167603, 181440}, [2]int64{-67102379, 29030400}, [2]int64{79682431, 79833600}),
+		series(4, [2]int64{49561, 161280}, [2]int64{-179, 168}, [2]int64{6601661, 7257600},
+			[2]int64{97445, 49896}, [2]int64{-40176129013, 7664025600}),
+		series(5, [2]int64{34729, 80640}, [2]int64{-3418889, 1995840}, [2]int64{14644087, 9123840},
+			[2]int64{2605413599, 622702080}),
+		series(6, [2]int64{212378941, 319334400}, [2]int64{-30705481, 10378368},
+			[2]int64{175214326799, 58118860800}),
+		series(7, [2]int64{1522256789, 1383782400}, [2]int64{-16759934899, 3113510400}),
+		series(8, [2]int64{1424729850961, 743921418240}),
+	}
+	rectifying := bigQuo(bigAdd(bigAdd(bigAdd(bigAdd(bigFloat(1),
+		bigMul(bigRat(1, 4), np[2])), bigMul(bigRat(1, 64), np[4])),
+		bigMul(bigRat(1, 256), np[6])), bigMul(bigRat(25, 16384), np[8])),
+		bigAdd(bigFloat(1), n))
+
+	phi := bigRadians(latitude)
+	lam := bigRadians(lambda)
+	tau := bigQuo(bigSin(phi), bigCos(phi))
+	tau1 := bigSqrt(bigAdd(bigFloat(1), bigMul(tau, tau)))
+	sig := bigSinh(bigMul(e, bigAtanh(bigQuo(bigMul(e, tau), tau1))))
+	taup := bigSub(bigMul(tau, bigSqrt(bigAdd(bigFloat(1), bigMul(sig, sig)))), bigMul(sig, tau1))
+	cosLam := bigCos(lam)
+	xip := bigAtan2(taup, cosLam)
+	etap := bigAsinh(bigQuo(bigSin(lam), bigSqrt(bigAdd(bigMul(taup, taup), bigMul(cosLam, cosLam)))))
+
+	xi := new(big.Float).SetPrec(bigPrec).Set(xip)
+	eta := new(big.Float).SetPrec(bigPrec).Set(etap)
+	for j, aj := range alpha {
+		twoJ := bigFloat(float64(2 * (j + 1)))
+		xi = bigAdd(xi, bigMul(aj, bigMul(bigSin(bigMul(twoJ, xip)), bigCosh(bigMul(twoJ, etap)))))
+		eta = bigAdd(eta, bigMul(aj, bigMul(bigCos(bigMul(twoJ, xip)), bigSinh(bigMul(twoJ, etap)))))
+	}
+	scale := bigMul(bigFloat(k0*a), rectifying)
+	easting, _ = bigMul(scale, eta).Float64()
+	northing, _ = bigMul(scale, xi).Float64()
+	return easting, northing
+}
+
+// bigComplex is a complex number with the precision of the reference
+// computations.
+type bigComplex struct{ re, im *big.Float }
+
+func (x bigComplex) add(y bigComplex) bigComplex {
+	return bigComplex{bigAdd(x.re, y.re), bigAdd(x.im, y.im)}
+}
+
+func (x bigComplex) mul(y bigComplex) bigComplex {
+	return bigComplex{bigSub(bigMul(x.re, y.re), bigMul(x.im, y.im)), bigAdd(bigMul(x.re, y.im), bigMul(x.im, y.re))}
+}
+
+func (x bigComplex) scale(k *big.Float) bigComplex {
+	return bigComplex{bigMul(x.re, k), bigMul(x.im, k)}
+}
+
+func (x bigComplex) abs() float64 {
+	re, _ := x.re.Float64()
+	im, _ := x.im.Float64()
+	return math.Hypot(re, im)
+}
+
+// exactTaylorOrder is the order of the Taylor series that exactReference
+// integrates with.
+const exactTaylorOrder = 40
+
+// exactReference projects a point with the exact Transverse Mercator
+// projection, without the elliptic functions of Lee (1976), evaluated with 256
+// bits of precision.  The northing and easting are the meridian distance to
+// the complex latitude whose isometric latitude is psi + i lambda.  With s, c
+// the sine and cosine of that latitude, d = sqrt(1 - e^2 s^2) and q = 1/d, the
+// meridian distance M follows from
+//
+//	ds/dz = c^2 d^2 / (1 - e^2)          dc/dz = -s c d^2 / (1 - e^2)
+//	dd/dz = -e^2 s c^2 d / (1 - e^2)     dq/dz = e^2 s c^2 q / (1 - e^2)
+//	dM/dz = c q
+//
+// which is integrated from the origin along the real axis to psi and then
+// parallel to the imaginary axis to psi + i lambda, away from the branch
+// points on the imaginary axis.  Continuing across lambda = 90 degrees gives
+// the far side of the ellipsoid, beyond the poles.  The latitude must not be
+// zero, where the equator beyond 90 (1 - e) degrees is a branch cut.
+func exactReference(a, f, k0, latitude, lambda float64) (easting, northing float64) {
+	bf := bigFloat(f)
+	e2 := bigMul(bf, bigSub(bigFloat(2), bf))
+	e := bigSqrt(e2)
+	k := bigQuo(bigFloat(1), bigSub(bigFloat(1), e2))
+	sinPhi := bigSin(bigRadians(latitude))
+	psi := bigSub(bigAtanh(sinPhi), bigMul(e, bigAtanh(bigMul(e, sinPhi))))
+
+	zero := bigComplex{bigFloat(0), bigFloat(0)}
+	one := bigComplex{bigFloat(1), bigFloat(0)}
+	y := [5]bigComplex{zero, one, one, one, zero} // s, c, d, q and M at the origin
+	integrate := func(direction bigComplex, length *big.Float) {
+		remaining := new(big.Float).SetPrec(bigPrec).Set(length)
+		for remaining.Sign() > 0 {
+			// Taylor series of s, c, d, q and M and of the products c^2,
+			// d^2, s d^2 and s c^2 along the direction
+			var series [5][exactTaylorOrder + 1]bigComplex
+			var cc, dd, sdd, scc [exactTaylorOrder + 1]bigComplex
+			product := func(x, z *[exactTaylorOrder + 1]bigComplex, n int) bigComplex {
+				sum := zero
+				for j := 0; j <= n; j++ {
+					sum = sum.add(x[j].mul(z[n-j]))
+				}
+				return sum
+			}
+			for v := range y {
+				series[v][0] = y[v]
+			}
+			for n := 0; n < exactTaylorOrder; n++ {
+				cc[n] = product(&series[1], &series[1], n)
+				dd[n] = product(&series[2], &series[2], n)
+				sdd[n] = product(&series[0], &dd, n)
+				scc[n] = product(&series[0], &cc, n)
+				derivatives := [5]bigComplex{
+					product(&cc, &dd, n).scale(k),
+					product(&series[1], &sdd, n).scale(bigMul(k, bigFloat(-1))),
+					product(&series[2], &scc, n).scale(bigMul(k, bigMul(e2, bigFloat(-1)))),
+					product(&series[3], &scc, n).scale(bigMul(k, e2)),
+					product(&series[1], &series[3], n),
+				}
+				for v, derivative := range derivatives {
+					series[v][n+1] = derivative.mul(direction).scale(bigQuo(bigFloat(1), bigFloat(float64(n+1))))
+				}
+			}
+
+			// step a quarter of the radius of convergence, estimated from
+			// the last terms, so the truncation error is about 4^-40
+			radius := math.Inf(1)
+			for v := range series {
+				for _, n := range []int{exactTaylorOrder - 1, exactTaylorOrder} {
+					if c := series[v][n].abs(); c > 0 {
+						radius = math.Min(radius, math.Pow(c, -1/float64(n)))
+					}
+				}
+			}
+			step := bigFloat(radius / 4)
+			if step.Cmp(remaining) > 0 {
+				step = remaining
+			}
+			for v := range y {
+				sum := series[v][exactTaylorOrder]
+				for n := exactTaylorOrder - 1; n >= 0; n-- {
+					sum = sum.scale(step).add(series[v][n])
+				}
+				y[v] = sum
+			}
+			remaining = bigSub(remaining, step)
+		}
+	}
+	if psi.Sign() >= 0 {
+		integrate(one, psi)
+	} else {
+		integrate(bigComplex{bigFloat(-1), bigFloat(0)}, bigMul(psi, bigFloat(-1)))
+	}
+	if lambda >= 0 {
+		integrate(bigComplex{bigFloat(0), bigFloat(1)}, bigRadians(lambda))
+	} else {
+		integrate(bigComplex{bigFloat(0), bigFloat(-1)}, bigRadians(-lambda))
+	}
+
+	scale := bigFloat(a * k0)
+	easting, _ = bigMul(scale, y[4].im).Float64()
+	northing, _ = bigMul(scale, y[4].re).Float64()
+	return easting, northing
+}
+
+// completeEllipticReference returns the complete elliptic integrals K(m) and
+// E(m) using the arithmetic-geometric mean, with 256 bits of precision.
+func completeEllipticReference(m float64) (k, e float64) {
+	a := bigFloat(1)
+	b := bigSqrt(bigSub(bigFloat(1), bigFloat(m)))
+	c := bigSqrt(bigFloat(m))
+	sum := bigQuo(bigMul(c, c), bigFloat(2))
+	pow := bigFloat(0.5)
+	for i := 0; i < 12; i++ {
+		a, b, c = bigQuo(bigAdd(a, b), bigFloat(2)), bigSqrt(bigMul(a, b)), bigQuo(bigSub(a, b), bigFloat(2))
+		pow = bigMul(pow, bigFloat(2))
+		sum = bigAdd(sum, bigMul(pow, bigMul(c, c)))
+	}
+	pi, _ := new(big.Float).SetPrec(bigPrec).SetString(bigPi)
+	bk := bigQuo(pi, bigMul(bigFloat(2), a))
+	k, _ = bk.Float64()
+	e, _ = bigMul(bk, bigSub(bigFloat(1), sum)).Float64()
+	return k, e
+}
+
+func TestTransverseMercatorExact(t *testing.T) {
+	const k0 = 0.9996
+	tm, err := coordconv.NewTransverseMercatorExact(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		0, 0, 0, 0, k0, "WE")
+	if err != nil {
+		t.Fatalf("error creating transverse mercator converter: %s", err)
+	}
+	series, err := coordconv.NewTransverseMercator(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		0, 0, 0, 0, k0, "WE")
+	if err != nil {
+		t.Fatalf("error creating transverse mercator converter: %s", err)
+	}
+	if err := series.SetSeriesOrder(8); err != nil {
+		t.Fatalf("expected no error setting the series order, got %s", err)
+	}
+
+	const epsilon = 1e-6 // meters
+	for _, lat := range []float64{-70, -45, 0, 1, 10, 30, 45, 60, 75, 89} {
+		for _, lng := range []float64{-30, -3, 0, 0.5, 3, 6, 15, 30, 35} {
+			geo := s2.LatLngFromDegrees(lat, lng)
+			expectedEasting, expectedNorthing := krugerReference(ellipsoidSemiMajorAxis,
+				ellipsoidFlattening, k0, lat, lng)
+			for _, conv := range []*coordconv.TransverseMercator{tm, series} {
+				if conv == series && math.Abs(lng) > 6 {
+					// outside of a UTM zone the eighth order series
+					// loses its nanometer accuracy
+					continue
+				}
+				mc, err := conv.ConvertFromGeodetic(geo)
+				if err != nil {
+					t.Fatalf("expected no error at %s, got %s", geo, err)
+				}
+				if math.Abs(mc.Easting-expectedEasting) > epsilon ||
+					math.Abs(mc.Northing-expectedNorthing) > epsilon {
+					t.Errorf("expected %f %f at %s, got %f %f", expectedEasting, expectedNorthing,
+						geo, mc.Easting, mc.Northing)
+				}
+			}
+
+			geo2, err := tm.ConvertToGeodetic(coordconv.MapCoords{Easting: expectedEasting, Northing: expectedNorthing})
+			if err != nil {
+				t.Fatalf("expected no error at %s, got %s", geo, err)
+			}
+			if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > epsilon {
+				t.Errorf("expected %s, got %s", geo, geo2)
+			}
+		}
+	}
+}
+
+func TestTransverseMercatorExactFarFromMeridian(t *testing.T) {
+	const k0 = 0.9996
+	tm, err := coordconv.NewTransverseMercatorExact(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		0, 0, 0, 0, k0, "WE")
+	if err != nil {
+		t.Fatalf("error creating transverse mercator converter: %s", err)
+	}
+
+	const epsilon = 1e-8 // meters
+
+	// the two references agree where the series converges
+	for _, p := range [][2]float64{{40, 25}, {-10, 30}, {75, -15}} {
+		expectedEasting, expectedNorthing := krugerReference(ellipsoidSemiMajorAxis,
+			ellipsoidFlattening, k0, p[0], p[1])
+		easting, northing := exactReference(ellipsoidSemiMajorAxis, ellipsoidFlattening, k0, p[0], p[1])
+		if math.Abs(easting-expectedEasting) > epsilon || math.Abs(northing-expectedNorthing) > epsilon {
+			t.Errorf("expected the references to agree at %v, got %f %f and %f %f", p,
+				expectedEasting, expectedNorthing, easting, northing)
+		}
+	}
+
+	for _, p := range [][2]float64{
+		{60, 80}, {0.1, 82}, {1, 82.5}, {-1, 83}, {1, 85}, {30, 90}, {-30, 100}, {45, 120},
+		{-70, 135}, {80, 150}, {10, 165}, {5, 179}, {-5, -179}, {20, -170}, {-45, -100}, {89, -85},
+	} {
+		geo := s2.LatLngFromDegrees(p[0], p[1])
+		expectedEasting, expectedNorthing := exactReference(ellipsoidSemiMajorAxis,
+			ellipsoidFlattening, k0, p[0], p[1])
+		mc, err := tm.ConvertFromGeodetic(geo)
+		if err != nil {
+			t.Fatalf("expected no error at %s, got %s", geo, err)
+		}
+		if math.Abs(mc.Easting-expectedEasting) > epsilon ||
+			math.Abs(mc.Northing-expectedNorthing) > epsilon {
+			t.Errorf("expected %f %f at %s, got %f %f", expectedEasting, expectedNorthing,
+				geo, mc.Easting, mc.Northing)
+		}
+
+		geo2, err := tm.ConvertToGeodetic(coordconv.MapCoords{Easting: expectedEasting, Northing: expectedNorthing})
+		if err != nil {
+			t.Fatalf("expected no error at %s, got %s", geo, err)
+		}
+		if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > epsilon {
+			t.Errorf("expected %s, got %s", geo, geo2)
+		}
+	}
+}
+
+func TestTransverseMercatorExactSingularities(t *testing.T) {
+	const k0 = 0.9996
+	tm, err := coordconv.NewTransverseMercatorExact(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		0, 0, 0, 0, k0, "WE")
+	if err != nil {
+		t.Fatalf("error creating transverse mercator converter: %s", err)
+	}
+	es2 := ellipsoidFlattening * (2 - ellipsoidFlattening)
+	const epsilon = 1e-6 // meters
+
+	// the pole lies on the central meridian at the quarter meridian
+	_, e := completeEllipticReference(es2)
+	quarterMeridian := ellipsoidSemiMajorAxis * k0 * e
+	for _, lng := range []float64{0, 45, 120, -170} {
+		mc, err := tm.ConvertFromGeodetic(s2.LatLngFromDegrees(90, lng))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if math.Abs(mc.Easting) > epsilon || math.Abs(mc.Northing-quarterMeridian) > epsilon {
+			t.Errorf("expected the pole at 0 %f, got %f %f", quarterMeridian, mc.Easting, mc.Northing)
+		}
+	}
+	geo, err := tm.ConvertToGeodetic(coordconv.MapCoords{Easting: 0, Northing: -quarterMeridian})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(geo.Lat.Degrees()+90) > 1e-12 {
+		t.Errorf("expected the south pole, got %s", geo)
+	}
+
+	// the equator at 90 (1 - e) degrees from the central meridian is a branch
+	// point of the projection, at a k0 (K(e') - E(e')) along the equator
+	kp, ep := completeEllipticReference(1 - es2)
+	singular := ellipsoidSemiMajorAxis * k0 * (kp - ep)
+	geo, err = tm.ConvertToGeodetic(coordconv.MapCoords{Easting: singular, Northing: 0})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	expected := s2.LatLngFromDegrees(0, 90*(1-math.Sqrt(es2)))
+	if geo.Distance(expected).Radians()*ellipsoidSemiMajorAxis > 1e-3 {
+		t.Errorf("expected %s, got %s", expected, geo)
+	}
+
+	// the whole ellipsoid, including the far side, survives a round trip
+	for lng := -180.0; lng <= 180; lng += 7.5 {
+		for lat := -89.5; lat < 90; lat += 4.5 {
+			geo := s2.LatLngFromDegrees(lat, lng)
+			mc, factors, err := tm.ConvertFromGeodeticFactors(geo)
+			if err != nil {
+				t.Fatalf("expected no error at %s, got %s", geo, err)
+			}
+			geo2, err := tm.ConvertToGeodetic(mc)
+			if err != nil {
+				t.Fatalf("expected no error in round trip at %s, got %s", geo, err)
+			}
+			if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > 1e-6 {
+				t.Errorf("expected %s, got %s", geo, geo2)
+			}
+			if math.Abs(lng) < 80 {
+				checkGridFactors(t, "exact transverse mercator", tm.ConvertFromGeodetic,
+					ellipsoidSemiMajorAxis, ellipsoidFlattening, geo, factors)
+			}
+		}
+	}
+}
+
+func TestTransverseMercatorSeriesOrder(t *testing.T) {
+	tm, err := coordconv.NewTransverseMercator(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		0, 0, 500000, 0, 0.9996, "WE")
+	if err != nil {
+		t.Fatalf("error creating transverse mercator converter: %s", err)
+	}
+	for _, order := range []int{0, 9} {
+		if err := tm.SetSeriesOrder(order); err == nil {
+			t.Errorf("expected an error for series order %d", order)
+		}
+	}
+
+	// the error shrinks as the order of the series grows
+	geo := s2.LatLngFromDegrees(40, 25)
+	expectedEasting, expectedNorthing := krugerReference(ellipsoidSemiMajorAxis,
+		ellipsoidFlattening, 0.9996, 40, 25)
+	expectedEasting += 500000
+	previous := math.Inf(1)
+	for order := 2; order <= 8; order++ {
+		if err := tm.SetSeriesOrder(order); err != nil {
+			t.Fatalf("expected no error setting series order %d, got %s", order, err)
+		}
+		mc, err := tm.ConvertFromGeodetic(geo)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		dist := math.Hypot(mc.Easting-expectedEasting, mc.Northing-expectedNorthing)
+		if dist > previous && dist > 1e-6 {
+			t.Errorf("expected order %d to be more accurate than %g m, got %g m", order, previous, dist)
+		}
+		previous = dist
+	}
+	if previous > 1e-6 {
+		t.Errorf("expected the eighth order series within a micrometer, got %g m", previous)
+	}
+}
@@ -0,0 +1,287 @@
+package coordconv
+
+import (
+	"errors"
+	"math"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// TransverseMercatorSouthOrientated provides conversions between geodetic
+// coordinates (latitude and longitude) and Transverse Mercator (South
+// Orientated) coordinates, as used by the South African Lo grids.  The axes
+// increase to the west and south, so the Easting and Northing of its
+// MapCoords are the westing and southing.
+type TransverseMercatorSouthOrientated struct {
+	transverseMercator *TransverseMercator // Projection without false easting and northing
+
+	tmsoFalseEasting  float64 // False westing in meters
+	tmsoFalseNorthing float64 // False southing in meters
+}
+
+// NewTransverseMercatorSouthOrientated constructs a Transverse Mercator (South
+// Orientated) converter.
+func NewTransverseMercatorSouthOrientated(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+	latitudeOfTrueScale, falseEasting, falseNorthing, scaleFactor float64,
+	ellipsoidCode string) (*TransverseMercatorSouthOrientated, error) {
+	t, err := NewTransverseMercator(ellipsoidSemiMajorAxis, ellipsoidFlattening, centralMeridian,
+		latitudeOfTrueScale, 0, 0, scaleFactor, ellipsoidCode)
+	if err != nil {
+		return nil, err
+	}
+	return &TransverseMercatorSouthOrientated{
+		transverseMercator: t,
+		tmsoFalseEasting:   falseEasting,
+		tmsoFalseNorthing:  falseNorthing,
+	}, nil
+}
+
+// NewTransverseMercatorSouthOrientatedEllipsoid constructs a Transverse
+// Mercator (South Orientated) converter for the given ellipsoid.
+func NewTransverseMercatorSouthOrientatedEllipsoid(ellipsoid Ellipsoid, centralMeridian,
+	latitudeOfTrueScale, falseEasting, falseNorthing, scaleFactor float64) (*TransverseMercatorSouthOrientated, error) {
+	return NewTransverseMercatorSouthOrientated(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, centralMeridian,
+		latitudeOfTrueScale, falseEasting, falseNorthing, scaleFactor, ellipsoid.Code)
+}
+
+// NewTransverseMercatorLo constructs the Hartebeesthoek94 Lo grid converter
+// for the given odd central meridian in degrees, from Lo15 to Lo33.
+func NewTransverseMercatorLo(centralMeridian int) (*TransverseMercatorSouthOrientated, error) {
+	if !validLoZone(centralMeridian) {
+		return nil, errors.New("Lo central meridian out of range")
+	}
+	return NewTransverseMercatorSouthOrientatedEllipsoid(EllipsoidWGS84,
+		float64(centralMeridian)*math.Pi/180, 0, 0, 0, 1)
+}
+
+// ConvertFromGeodetic converts geodetic coordinates (latitude and longitude) to
+// Transverse Mercator (South Orientated) coordinates (westing and southing).
+func (t *TransverseMercatorSouthOrientated) ConvertFromGeodetic(geodeticCoordinates s2.LatLng) (MapCoords, error) {
+	mapCoords, err := t.transverseMercator.ConvertFromGeodetic(geodeticCoordinates)
+	if err != nil {
+		return MapCoords{}, err
+	}
+	return MapCoords{
+		Easting:  t.tmsoFalseEasting - mapCoords.Easting,
+		Northing: t.tmsoFalseNorthing - mapCoords.Northing,
+	}, nil
+}
+
+// ConvertToGeodetic converts Transverse Mercator (South Orientated)
+// coordinates (westing and southing) to geodetic coordinates (latitude and
+// longitude).
+func (t *TransverseMercatorSouthOrientated) ConvertToGeodetic(mapProjectionCoordinates MapCoords) (s2.LatLng, error) {
+	return t.transverseMercator.ConvertToGeodetic(MapCoords{
+		Easting:  t.tmsoFalseEasting - mapProjectionCoordinates.Easting,
+		Northing: t.tmsoFalseNorthing - mapProjectionCoordinates.Northing,
+	})
+}
+
+// ConvertFromGeodeticFactors converts geodetic coordinates (latitude and
+// longitude) to Transverse Mercator (South Orientated) coordinates and also
+// returns the meridian convergence and point scale factor at the point.  The
+// convergence is the bearing of the southing axis, which points roughly
+// south.
+func (t *TransverseMercatorSouthOrientated) ConvertFromGeodeticFactors(geodeticCoordinates s2.LatLng) (MapCoords, GridFactors, error) {
+	mapCoords, err := t.ConvertFromGeodetic(geodeticCoordinates)
+	if err != nil {
+		return MapCoords{}, GridFactors{}, err
+	}
+	return mapCoords, t.gridFactors(geodeticCoordinates.Lat.Radians(), geodeticCoordinates.Lng.Radians()), nil
+}
+
+// ConvertToGeodeticFactors converts Transverse Mercator (South Orientated)
+// coordinates to geodetic coordinates (latitude and longitude) and also
+// returns the meridian convergence and point scale factor at the point.
+func (t *TransverseMercatorSouthOrientated) ConvertToGeodeticFactors(mapProjectionCoordinates MapCoords) (s2.LatLng, GridFactors, error) {
+	geodeticCoordinates, err := t.ConvertToGeodetic(mapProjectionCoordinates)
+	if err != nil {
+		return s2.LatLng{}, GridFactors{}, err
+	}
+	return geodeticCoordinates, t.gridFactors(geodeticCoordinates.Lat.Radians(), geodeticCoordinates.Lng.Radians()), nil
+}
+
+// gridFactors computes the meridian convergence and point scale factor at
+// the given latitude and longitude.  Negating both axes turns the grid by
+// half a circle.
+func (t *TransverseMercatorSouthOrientated) gridFactors(latitude, longitude float64) GridFactors {
+	factors := t.transverseMercator.gridFactors(latitude, longitude)
+	factors.Convergence = s1.Angle(math.Remainder(factors.Convergence.Radians()+math.Pi, 2*math.Pi))
+	return factors
+}
+
+// Parameters returns the ellipsoid and Transverse Mercator (South Orientated)
+// projection parameters.  The false easting and northing are the false
+// westing and southing.
+func (t *TransverseMercatorSouthOrientated) Parameters() ProjectionParameters {
+	params := t.transverseMercator.Parameters()
+	params.FalseEasting = t.tmsoFalseEasting
+	params.FalseNorthing = t.tmsoFalseNorthing
+	return params
+}
+
+const loMinZone = 15 // Central meridian of Lo15 in degrees
+const loMaxZone = 33 // Central meridian of Lo33 in degrees
+
+// LoCoord is a coordinate on a South African Lo grid, with the zone named by
+// its odd central meridian in degrees, e.g. 29 for Lo29.
+type LoCoord struct {
+	Zone     int
+	Westing  float64
+	Southing float64
+}
+
+// LoGrid is a South African Lo grid coordinate converter.  Each zone, from
+// Lo15 to Lo33, is a Transverse Mercator (South Orientated) projection with
+// unit scale on its odd central meridian and no false westing or southing,
+// covering one degree either side of it.
+type LoGrid struct {
+	semiMajorAxis         float64
+	flattening            float64
+	ellipsCode            string
+	loOverride            int
+	transverseMercatorMap [loMaxZone + 1]*TransverseMercatorSouthOrientated
+}
+
+// NewLoGrid receives the ellipsoid parameters and Lo zone override parameter
+// as inputs, and sets the corresponding state variables.  override is the
+// central meridian of the Lo override zone, 0 indicates no override.
+func NewLoGrid(ellipsoidSemiMajorAxis, ellipsoidFlattening float64,
+	ellipsoidCode string, override int) (*LoGrid, error) {
+	invF := 1 / ellipsoidFlattening
+	if ellipsoidSemiMajorAxis <= 0.0 {
+		return nil, errors.New("Semi-major axis must be greater than zero")
+	}
+	if (invF < 250) || (invF > 350) {
+		return nil, errors.New("Inverse flattening must be between 250 and 350")
+	}
+	if (override != 0) && !validLoZone(override) {
+		return nil, errors.New("zone override out of range")
+	}
+
+	l := &LoGrid{
+		semiMajorAxis: ellipsoidSemiMajorAxis,
+		flattening:    ellipsoidFlattening,
+		ellipsCode:    ellipsoidCode,
+		loOverride:    override,
+	}
+
+	originLatitude := 0.0
+	falseWesting := 0.0
+	falseSouthing := 0.0
+	scale := 1.0
+	for zone := loMinZone; zone <= loMaxZone; zone += 2 {
+		centralMeridian := float64(zone) * math.Pi / 180
+
+		var err error
+		l.transverseMercatorMap[zone], err = NewTransverseMercatorSouthOrientated(
+			l.semiMajorAxis, l.flattening, centralMeridian, originLatitude,
+			falseWesting, falseSouthing, scale, l.ellipsCode)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// NewLoGridEllipsoid constructs a Lo grid converter for the given ellipsoid.
+// override is the central meridian of the Lo override zone, 0 indicates no
+// override.
+func NewLoGridEllipsoid(ellipsoid Ellipsoid, override int) (*LoGrid, error) {
+	return NewLoGrid(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, ellipsoid.Code, override)
+}
+
+// ConvertFromGeodetic converts geodetic (latitude and longitude) coordinates
+// to Lo grid coordinates (zone, westing and southing) according to the
+// current ellipsoid and zone override parameters.  A non-zero loZoneOverride
+// selects a zone adjacent to the computed one.
+func (l *LoGrid) ConvertFromGeodetic(geodeticCoordinates s2.LatLng, loZoneOverride int) (LoCoord, error) {
+	zone, err := l.zone(geodeticCoordinates, loZoneOverride)
+	if err != nil {
+		return LoCoord{}, err
+	}
+	mapCoords, err := l.transverseMercatorMap[zone].ConvertFromGeodetic(geodeticCoordinates)
+	if err != nil {
+		return LoCoord{}, err
+	}
+	return LoCoord{Zone: zone, Westing: mapCoords.Easting, Southing: mapCoords.Northing}, nil
+}
+
+// ConvertToGeodetic converts Lo grid coordinates (zone, westing and southing)
+// to geodetic (latitude and longitude) coordinates, according to the current
+// ellipsoid parameters.
+func (l *LoGrid) ConvertToGeodetic(loCoordinates LoCoord) (s2.LatLng, error) {
+	if !validLoZone(loCoordinates.Zone) {
+		return s2.LatLng{}, errors.New("zone out of range")
+	}
+	return l.transverseMercatorMap[loCoordinates.Zone].ConvertToGeodetic(
+		MapCoords{Easting: loCoordinates.Westing, Northing: loCoordinates.Southing})
+}
+
+// ConvertFromGeodeticFactors converts geodetic (latitude and longitude)
+// coordinates to Lo grid coordinates and also returns the meridian
+// convergence and point scale factor at the point.
+func (l *LoGrid) ConvertFromGeodeticFactors(geodeticCoordinates s2.LatLng, loZoneOverride int) (LoCoord, GridFactors, error) {
+	zone, err := l.zone(geodeticCoordinates, loZoneOverride)
+	if err != nil {
+		return LoCoord{}, GridFactors{}, err
+	}
+	mapCoords, factors, err := l.transverseMercatorMap[zone].ConvertFromGeodeticFactors(geodeticCoordinates)
+	if err != nil {
+		return LoCoord{}, GridFactors{}, err
+	}
+	return LoCoord{Zone: zone, Westing: mapCoords.Easting, Southing: mapCoords.Northing}, factors, nil
+}
+
+// ConvertToGeodeticFactors converts Lo grid coordinates to geodetic (latitude
+// and longitude) coordinates and also returns the meridian convergence and
+// point scale factor at the point.
+func (l *LoGrid) ConvertToGeodeticFactors(loCoordinates LoCoord) (s2.LatLng, GridFactors, error) {
+	if !validLoZone(loCoordinates.Zone) {
+		return s2.LatLng{}, GridFactors{}, errors.New("zone out of range")
+	}
+	return l.transverseMercatorMap[loCoordinates.Zone].ConvertToGeodeticFactors(
+		MapCoords{Easting: loCoordinates.Westing, Northing: loCoordinates.Southing})
+}
+
+// zone selects the zone of the given point, the nearest odd meridian, allowing
+// the zone override to move it by up to one zone either way.
+func (l *LoGrid) zone(geodeticCoordinates s2.LatLng, loZoneOverride int) (int, error) {
+	longitude := geodeticCoordinates.Lng.Radians()
+	latitude := geodeticCoordinates.Lat.Radians()
+	if !(latitude >= -math.Pi/2) || !(latitude <= math.Pi/2) {
+		return 0, errors.New("latitude out of range")
+	}
+	if !(longitude >= -math.Pi-epsilonRadians) || !(longitude <= 2*math.Pi+epsilonRadians) {
+		return 0, errors.New("longitude out of range")
+	}
+
+	degrees := math.Remainder(longitude, 2*math.Pi)*180.0/math.Pi + 1.0e-10
+	tempZone := 2*int(math.Floor(degrees/2.0)) + 1
+
+	override := loZoneOverride
+	if override == 0 {
+		override = l.loOverride
+	}
+	if override != 0 {
+		if !validLoZone(override) {
+			return 0, errors.New("zone out of range")
+		}
+		// allow zone override up to +/- one zone of the calculated zone
+		if (override < tempZone-2) || (override > tempZone+2) {
+			return 0, errors.New("zone out of range")
+		}
+		tempZone = override
+	}
+	if !validLoZone(tempZone) {
+		return 0, errors.New("longitude out of range")
+	}
+	return tempZone, nil
+}
+
+// validLoZone returns whether the zone is the odd central meridian of a Lo
+// grid zone.
+func validLoZone(zone int) bool {
+	return (zone >= loMinZone) && (zone <= loMaxZone) && (zone%2 != 0)
+}
@@ -0,0 +1,214 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestTransverseMercatorSouthOrientated(t *testing.T) {
+	// EPSG Guidance Note 7-2, Hartebeesthoek94 / Lo29
+	lo29, err := coordconv.NewTransverseMercatorLo(29)
+	if err != nil {
+		t.Fatalf("error creating transverse mercator converter: %s", err)
+	}
+	geo := s2.LatLngFromDegrees(-(25 + 43.0/60 + 55.302/3600), 28+16.0/60+57.479/3600)
+	expected := coordconv.MapCoords{Easting: 71984.49, Northing: 2847342.74}
+
+	// the example latitude and longitude are rounded to a thousandth of a
+	// second, about 3 centimeters
+	const epsilon = 0.02
+	mc, err := lo29.ConvertFromGeodetic(geo)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(mc.Easting-expected.Easting) > epsilon ||
+		math.Abs(mc.Northing-expected.Northing) > epsilon {
+		t.Errorf("expected %v, got %v", expected, mc)
+	}
+	geo2, err := lo29.ConvertToGeodetic(expected)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > epsilon {
+		t.Errorf("expected %s, got %s", geo, geo2)
+	}
+
+	for _, lo := range []int{13, 16, 35} {
+		if _, err := coordconv.NewTransverseMercatorLo(lo); err == nil {
+			t.Errorf("expected an error for Lo%d", lo)
+		}
+	}
+}
+
+func TestTransverseMercatorSouthOrientatedRoundTrip(t *testing.T) {
+	tmso, err := coordconv.NewTransverseMercatorSouthOrientated(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		25*math.Pi/180, 0, 100000, 200000, 1, "WE")
+	if err != nil {
+		t.Fatalf("error creating transverse mercator converter: %s", err)
+	}
+	tm, err := coordconv.NewTransverseMercator(ellipsoidSemiMajorAxis, ellipsoidFlattening,
+		25*math.Pi/180, 0, 0, 0, 1, "WE")
+	if err != nil {
+		t.Fatalf("error creating transverse mercator converter: %s", err)
+	}
+	for lng := 19.0; lng <= 31; lng += 2 {
+		for lat := -35.0; lat <= 15; lat += 5 {
+			geo := s2.LatLngFromDegrees(lat, lng)
+			mc, factors, err := tmso.ConvertFromGeodeticFactors(geo)
+			if err != nil {
+				t.Fatalf("expected no error at %s, got %s", geo, err)
+			}
+			northUp, err := tm.ConvertFromGeodetic(geo)
+			if err != nil {
+				t.Fatalf("expected no error at %s, got %s", geo, err)
+			}
+			if math.Abs(mc.Easting-(100000-northUp.Easting)) > 1e-6 ||
+				math.Abs(mc.Northing-(200000-northUp.Northing)) > 1e-6 {
+				t.Errorf("at %s expected the negated axes of %v, got %v", geo, northUp, mc)
+			}
+			geo2, _, err := tmso.ConvertToGeodeticFactors(mc)
+			if err != nil {
+				t.Fatalf("expected no error in round trip at %s, got %s", geo, err)
+			}
+			if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > 1e-3 {
+				t.Errorf("expected %s, got %s", geo, geo2)
+			}
+			checkGridFactors(t, "transverse mercator south orientated", tmso.ConvertFromGeodetic,
+				ellipsoidSemiMajorAxis, ellipsoidFlattening, geo, factors)
+		}
+	}
+
+	params := tmso.Parameters()
+	if params.FalseEasting != 100000 || params.FalseNorthing != 200000 {
+		t.Errorf("expected the false westing and southing, got %+v", params)
+	}
+}
+
+func TestLoGrid(t *testing.T) {
+	lo, err := coordconv.NewLoGridEllipsoid(coordconv.EllipsoidWGS84, 0)
+	if err != nil {
+		t.Fatalf("error creating Lo grid converter: %s", err)
+	}
+
+	// EPSG Guidance Note 7-2, Hartebeesthoek94 / Lo29
+	geo := s2.LatLngFromDegrees(-(25 + 43.0/60 + 55.302/3600), 28+16.0/60+57.479/3600)
+	lc, err := lo.ConvertFromGeodetic(geo, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if (lc.Zone != 29) || math.Abs(lc.Westing-71984.49) > 0.02 || math.Abs(lc.Southing-2847342.74) > 0.02 {
+		t.Errorf("expected Lo29 71984.49 2847342.74, got %v", lc)
+	}
+
+	// each zone reaches a degree either side of its central meridian, with
+	// the even meridians between them in the zone to the east
+	for _, tc := range []struct {
+		lng  float64
+		zone int
+	}{
+		{14, 15}, {15.999, 15}, {16, 17}, {17, 17}, {27.999, 27}, {28, 29}, {29.5, 29}, {32, 33}, {33.999, 33},
+	} {
+		geo := s2.LatLngFromDegrees(-30, tc.lng)
+		lc, err := lo.ConvertFromGeodetic(geo, 0)
+		if err != nil {
+			t.Errorf("%f: expected no error, got %s", tc.lng, err)
+			continue
+		}
+		if lc.Zone != tc.zone {
+			t.Errorf("%f: expected Lo%d, got Lo%d", tc.lng, tc.zone, lc.Zone)
+		}
+		geo2, err := lo.ConvertToGeodetic(lc)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > 1e-3 {
+			t.Errorf("expected %s, got %s", geo, geo2)
+		}
+	}
+	for _, lng := range []float64{13.5, 34, 100, -20} {
+		if _, err := lo.ConvertFromGeodetic(s2.LatLngFromDegrees(-30, lng), 0); err == nil {
+			t.Errorf("%f: expected an error outside the Lo zones", lng)
+		}
+	}
+}
+
+func TestLoGridOverride(t *testing.T) {
+	lo, err := coordconv.NewLoGridEllipsoid(coordconv.EllipsoidWGS84, 0)
+	if err != nil {
+		t.Fatalf("error creating Lo grid converter: %s", err)
+	}
+	geo := s2.LatLngFromDegrees(-26.2, 28.05)
+
+	// Johannesburg is mapped in the neighbouring Lo27 as well as Lo29
+	lc, factors, err := lo.ConvertFromGeodeticFactors(geo, 27)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if lc.Zone != 27 {
+		t.Errorf("expected Lo27, got Lo%d", lc.Zone)
+	}
+	lo27, err := coordconv.NewTransverseMercatorLo(27)
+	if err != nil {
+		t.Fatalf("error creating transverse mercator converter: %s", err)
+	}
+	mc, err := lo27.ConvertFromGeodetic(geo)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if (mc.Easting != lc.Westing) || (mc.Northing != lc.Southing) {
+		t.Errorf("expected %v, got %v", mc, lc)
+	}
+	geo2, factors2, err := lo.ConvertToGeodeticFactors(lc)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > 1e-3 {
+		t.Errorf("expected %s, got %s", geo, geo2)
+	}
+	if math.Abs(factors.PointScale-factors2.PointScale) > 1e-9 {
+		t.Errorf("expected scale %f, got %f", factors.PointScale, factors2.PointScale)
+	}
+	for _, override := range []int{25, 33, 28, 35} {
+		if _, err := lo.ConvertFromGeodetic(geo, override); err == nil {
+			t.Errorf("expected an error overriding with Lo%d", override)
+		}
+	}
+
+	// an override reaches just outside the Lo zones
+	if lc, err := lo.ConvertFromGeodetic(s2.LatLngFromDegrees(-30, 13.5), 15); err != nil || lc.Zone != 15 {
+		t.Errorf("expected Lo15, got %v %v", lc, err)
+	}
+
+	// the converter's override applies when none is given
+	lo27s, err := coordconv.NewLoGridEllipsoid(coordconv.EllipsoidWGS84, 27)
+	if err != nil {
+		t.Fatalf("error creating Lo grid converter: %s", err)
+	}
+	lc27, err := lo27s.ConvertFromGeodetic(geo, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if lc27 != lc {
+		t.Errorf("expected %v, got %v", lc, lc27)
+	}
+}
+
+func TestLoGridInvalid(t *testing.T) {
+	for _, override := range []int{13, 16, 35, -1} {
+		if _, err := coordconv.NewLoGridEllipsoid(coordconv.EllipsoidWGS84, override); err == nil {
+			t.Errorf("expected an error for a zone override of %d", override)
+		}
+	}
+	lo, err := coordconv.NewLoGridEllipsoid(coordconv.EllipsoidWGS84, 0)
+	if err != nil {
+		t.Fatalf("error creating Lo grid converter: %s", err)
+	}
+	for _, zone := range []int{0, 14, 35} {
+		if _, err := lo.ConvertToGeodetic(coordconv.LoCoord{Zone: zone}); err == nil {
+			t.Errorf("expected an error for Lo%d", zone)
+		}
+	}
+}
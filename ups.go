@@ -32,6 +32,7 @@ type UPS struct {
 	UPSOriginLatitude      float64
 	polarStereographicMapN *PolarStereographic
 	polarStereographicMapS *PolarStereographic
+	datum                  Datum
 }
 
 const epsilonRadians = 1.75e-7 // approx 1.0e-5 degrees (~1 meter) in radians
@@ -59,6 +60,7 @@ func NewUPS(ellipsoidSemiMajorAxis, ellipsoidFlattening float64) (*UPS, error) {
 
 	u := &UPS{
 		UPSOriginLatitude: upsMaxOriginLat,
+		datum:             ellipsoidDatum(ellipsoidSemiMajorAxis, ellipsoidFlattening, ""),
 	}
 
 	u.semiMajorAxis = ellipsoidSemiMajorAxis
@@ -71,6 +73,33 @@ func NewUPS(ellipsoidSemiMajorAxis, ellipsoidFlattening float64) (*UPS, error) {
 	return u, nil
 }
 
+// NewUPSEllipsoid constructs a UPS converter for the given ellipsoid.
+func NewUPSEllipsoid(ellipsoid Ellipsoid) (*UPS, error) {
+	u, err := NewUPS(ellipsoid.SemiMajorAxis, ellipsoid.Flattening)
+	if err != nil {
+		return nil, err
+	}
+	u.datum.Ellipsoid = ellipsoid
+	return u, nil
+}
+
+// NewUPSDatum constructs a UPS converter for coordinates on the given datum.
+func NewUPSDatum(datum Datum) (*UPS, error) {
+	u, err := NewUPSEllipsoid(datum.Ellipsoid)
+	if err != nil {
+		return nil, err
+	}
+	u.datum = datum
+	return u, nil
+}
+
+// Datum returns the datum of the converter's UPS coordinates.  Converters
+// constructed from ellipsoid parameters alone are treated as having no shift
+// to WGS84.
+func (u *UPS) Datum() Datum {
+	return u.datum
+}
+
 // ConvertFromGeodetic converts a geodetic coordinate to a UPS coordinate.
 func (u *UPS) ConvertFromGeodetic(geodeticCoordinates s2.LatLng) (UPSCoord, error) {
 	longitude := geodeticCoordinates.Lng.Radians()
@@ -164,3 +193,58 @@ func (u *UPS) ConvertToGeodetic(upsCoordinates UPSCoord) (s2.LatLng, error) {
 
 	return geodeticCoordinates, nil
 }
+
+// ConvertFromGeodeticFactors converts a geodetic coordinate to a UPS
+// coordinate and also returns the meridian convergence and point scale factor
+// at the point.
+func (u *UPS) ConvertFromGeodeticFactors(geodeticCoordinates s2.LatLng) (UPSCoord, GridFactors, error) {
+	upsCoordinates, err := u.ConvertFromGeodetic(geodeticCoordinates)
+	if err != nil {
+		return UPSCoord{}, GridFactors{}, err
+	}
+	factors := u.polarStereographic(upsCoordinates.Hemisphere).gridFactors(
+		geodeticCoordinates.Lat.Radians(), geodeticCoordinates.Lng.Radians())
+	return upsCoordinates, factors, nil
+}
+
+// ConvertToGeodeticFactors converts UPS (hemisphere, easting, and northing)
+// coordinates to geodetic (latitude and longitude) coordinates and also
+// returns the meridian convergence and point scale factor at the point.
+func (u *UPS) ConvertToGeodeticFactors(upsCoordinates UPSCoord) (s2.LatLng, GridFactors, error) {
+	geodeticCoordinates, err := u.ConvertToGeodetic(upsCoordinates)
+	if err != nil {
+		return s2.LatLng{}, GridFactors{}, err
+	}
+	factors := u.polarStereographic(upsCoordinates.Hemisphere).gridFactors(
+		geodeticCoordinates.Lat.Radians(), geodeticCoordinates.Lng.Radians())
+	return geodeticCoordinates, factors, nil
+}
+
+// ConvertFromGeodeticDatum converts geodetic coordinates on the given datum
+// to UPS coordinates on the converter's datum.
+func (u *UPS) ConvertFromGeodeticDatum(geodeticCoordinates s2.LatLng, datum Datum) (UPSCoord, error) {
+	geodeticCoordinates, err := shiftDatum(geodeticCoordinates, datum, u.datum)
+	if err != nil {
+		return UPSCoord{}, err
+	}
+	return u.ConvertFromGeodetic(geodeticCoordinates)
+}
+
+// ConvertToGeodeticDatum converts UPS coordinates on the converter's datum to
+// geodetic coordinates on the given datum.
+func (u *UPS) ConvertToGeodeticDatum(upsCoordinates UPSCoord, datum Datum) (s2.LatLng, error) {
+	geodeticCoordinates, err := u.ConvertToGeodetic(upsCoordinates)
+	if err != nil {
+		return s2.LatLng{}, err
+	}
+	return shiftDatum(geodeticCoordinates, u.datum, datum)
+}
+
+// polarStereographic returns the polar stereographic projection used for the
+// given hemisphere.
+func (u *UPS) polarStereographic(hemisphere Hemisphere) *PolarStereographic {
+	if hemisphere == HemisphereNorth {
+		return u.polarStereographicMapN
+	}
+	return u.polarStereographicMapS
+}
@@ -1,6 +1,7 @@
 package coordconv_test
 
 import (
+	"math"
 	"testing"
 
 	"github.com/golang/geo/s2"
@@ -30,3 +31,41 @@ func TestUPSRoundTrip(t *testing.T) {
 		}
 	}
 }
+
+func TestUPSFactors(t *testing.T) {
+	ups, err := coordconv.NewUPS(ellipsoidSemiMajorAxis, ellipsoidFlattening)
+	if err != nil {
+		t.Fatalf("error creating UPS converter: %s", err)
+	}
+	for lng := -180.0; lng < 180; lng += 5 {
+		for _, lat := range []float64{-89.5, -85, -80.5, 84, 86, 89.5} {
+			geo := s2.LatLngFromDegrees(lat, lng)
+			uc, factors, err := ups.ConvertFromGeodeticFactors(geo)
+			if err != nil {
+				t.Fatalf("expected no error at %s, got %s", geo, err)
+			}
+			forward := func(geo s2.LatLng) (coordconv.MapCoords, error) {
+				c, err := ups.ConvertFromGeodetic(geo)
+				return coordconv.MapCoords{Easting: c.Easting, Northing: c.Northing}, err
+			}
+			checkGridFactors(t, "ups", forward, ellipsoidSemiMajorAxis, ellipsoidFlattening, geo, factors)
+
+			_, inverseFactors, err := ups.ConvertToGeodeticFactors(uc)
+			if err != nil {
+				t.Fatalf("expected no error at %s, got %s", geo, err)
+			}
+			if math.Abs(math.Remainder(inverseFactors.Convergence.Radians()-factors.Convergence.Radians(), 2*math.Pi)) > 1e-9 ||
+				math.Abs(inverseFactors.PointScale-factors.PointScale) > 1e-9 {
+				t.Errorf("expected inverse factors %v at %s, got %v", factors, geo, inverseFactors)
+			}
+		}
+	}
+
+	_, factors, err := ups.ConvertFromGeodeticFactors(s2.LatLngFromDegrees(90, 0))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(factors.PointScale-0.994) > 1e-12 {
+		t.Errorf("expected a point scale of 0.994 at the pole, got %f", factors.PointScale)
+	}
+}
@@ -0,0 +1,152 @@
+package coordconv
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/golang/geo/s2"
+)
+
+// USNGGrouping selects how a US National Grid reference is written.
+type USNGGrouping int
+
+// USNGGrouping constants
+const (
+	// USNGSpaced separates the grid zone designation, the 100,000 meter square
+	// and the easting and northing with spaces, e.g. "18S UJ 23480 06470".
+	USNGSpaced USNGGrouping = iota
+	// USNGCompact writes the reference without spaces, e.g.
+	// "18SUJ2348006470".
+	USNGCompact
+	// USNGSquare truncates the reference to the 100,000 meter square and the
+	// easting and northing, e.g. "UJ 23480 06470".
+	USNGSquare
+	// USNGDigits truncates the reference to the easting and northing, e.g.
+	// "23480 06470".
+	USNGDigits
+)
+
+// USNG returns the US National Grid reference string with the given grouping,
+// or an empty string if the letters aren't valid.
+func (c MGRSCoord) USNG(grouping USNGGrouping) string {
+	usngString, err := c.usng(grouping)
+	if err != nil {
+		return ""
+	}
+	return usngString
+}
+
+// usng returns the US National Grid reference string with the given grouping,
+// or an error if the coordinates aren't valid.
+func (c MGRSCoord) usng(grouping USNGGrouping) (string, error) {
+	mgrsString, err := c.format()
+	if err != nil {
+		return "", err
+	}
+	n := len(mgrsString) - 2*c.Precision
+	gzd := mgrsString[:n-2]
+	square := mgrsString[n-2 : n]
+	east := mgrsString[n : n+c.Precision]
+	north := mgrsString[n+c.Precision:]
+
+	var groups []string
+	switch grouping {
+	case USNGCompact:
+		return mgrsString, nil
+	case USNGSquare:
+		groups = []string{square, east, north}
+	case USNGDigits:
+		groups = []string{east, north}
+	default:
+		groups = []string{gzd, square, east, north}
+	}
+	return strings.TrimSpace(strings.Join(groups, " ")), nil
+}
+
+// ParseUSNG breaks down a US National Grid reference string, spaced as in
+// "18S UJ 23480 06470" or compact, into its component parts.  A truncated
+// reference, which omits the grid zone designation or both it and the
+// 100,000 meter square, takes the missing parts from the reference
+// coordinate; the zero MGRSCoord allows only complete references.
+func ParseUSNG(usngString string, reference MGRSCoord) (MGRSCoord, error) {
+	fields := strings.Fields(usngString)
+	if len(fields) == 0 {
+		return MGRSCoord{}, errors.New("empty USNG string")
+	}
+	// The easting and northing, when separated, must have the same precision
+	if last := len(fields) - 1; last > 0 && isDigits(fields[last]) && isDigits(fields[last-1]) &&
+		len(fields[last]) != len(fields[last-1]) {
+		return MGRSCoord{}, errors.New("easting and northing precision differ")
+	}
+	compact := strings.Join(fields, "")
+
+	i := 0
+	for i < len(compact) && isdigit(compact[i]) {
+		i++
+	}
+	numZoneDigits := i
+	for i < len(compact) && isalpha(compact[i]) {
+		i++
+	}
+	numLetters := i - numZoneDigits
+
+	// Truncated references take the grid zone designation, and the 100,000
+	// meter square if that is also missing, from the reference
+	var prefix string
+	if (numZoneDigits == 0 && numLetters == 2) || (numZoneDigits > 0 && numLetters == 0) {
+		if reference.Band == 0 {
+			return MGRSCoord{}, errors.New("truncated USNG string without a reference")
+		}
+		referenceString := reference.String()
+		if referenceString == "" {
+			return MGRSCoord{}, errors.New("invalid reference")
+		}
+		prefix = referenceString[:len(referenceString)-2*reference.Precision]
+		if numLetters == 2 {
+			prefix = prefix[:len(prefix)-2]
+		}
+	}
+	return ParseMGRS(prefix + compact)
+}
+
+// ConvertFromGeodeticUSNG converts Geodetic (latitude and longitude)
+// coordinates to a US National Grid reference string with the given grouping.
+// USNG letters the 100,000 meter squares with the pattern of the NAD83 and
+// WGS84 ellipsoids, whatever the ellipsoid of the converter.
+func (m *MGRS) ConvertFromGeodeticUSNG(geodeticCoordinates s2.LatLng, precision int, grouping USNGGrouping) (string, error) {
+	usngCoordinates, err := m.usng().ConvertFromGeodeticCoord(geodeticCoordinates, precision)
+	if err != nil {
+		return "", err
+	}
+	return usngCoordinates.usng(grouping)
+}
+
+// ConvertToGeodeticUSNG converts a US National Grid reference string to
+// Geodetic (latitude and longitude) coordinates, using the NAD83 and WGS84
+// 100,000 meter square pattern.  A truncated reference takes its missing parts
+// from the reference coordinate, as in ParseUSNG.
+func (m *MGRS) ConvertToGeodeticUSNG(usngString string, reference MGRSCoord) (s2.LatLng, error) {
+	usngCoordinates, err := ParseUSNG(usngString, reference)
+	if err != nil {
+		return s2.LatLng{}, err
+	}
+	return m.usng().ConvertToGeodeticCoord(usngCoordinates)
+}
+
+// usng returns a converter for the current ellipsoid that letters the
+// 100,000 meter squares as USNG does.
+func (m *MGRS) usng() *MGRS {
+	u := *m
+	u.usngPattern = true
+	return &u
+}
+
+// isDigits returns true if the string is made up of ASCII digits.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isdigit(s[i]) {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,135 @@
+package coordconv_test
+
+import (
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestUSNGFormat(t *testing.T) {
+	c, err := coordconv.ParseMGRS("18SUJ2348006470")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	for _, tc := range []struct {
+		grouping coordconv.USNGGrouping
+		expected string
+	}{
+		{coordconv.USNGSpaced, "18S UJ 23480 06470"},
+		{coordconv.USNGCompact, "18SUJ2348006470"},
+		{coordconv.USNGSquare, "UJ 23480 06470"},
+		{coordconv.USNGDigits, "23480 06470"},
+	} {
+		if got := c.USNG(tc.grouping); got != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, got)
+		}
+	}
+
+	c.Precision = 2
+	if got := c.USNG(coordconv.USNGSpaced); got != "18S UJ 23 06" {
+		t.Errorf("expected 18S UJ 23 06, got %s", got)
+	}
+	c = coordconv.MGRSCoord{Band: 'Z', Column: 'G', Row: 'C', Easting: 12000, Northing: 34000, Precision: 3}
+	if got := c.USNG(coordconv.USNGSpaced); got != "Z GC 120 340" {
+		t.Errorf("expected Z GC 120 340, got %s", got)
+	}
+	c.Band = 'I'
+	if got := c.USNG(coordconv.USNGSpaced); got != "" {
+		t.Errorf("expected an empty string, got %s", got)
+	}
+}
+
+func TestParseUSNG(t *testing.T) {
+	expected := coordconv.MGRSCoord{Zone: 18, Band: 'S', Column: 'U', Row: 'J', Easting: 23480, Northing: 6470, Precision: 5}
+	reference := coordconv.MGRSCoord{Zone: 18, Band: 'S', Column: 'U', Row: 'J', Easting: 20000, Northing: 0, Precision: 1}
+	for _, s := range []string{"18S UJ 23480 06470", "18SUJ2348006470", " 18S  UJ\t2348006470 ", "18s uj 23480 06470"} {
+		got, err := coordconv.ParseUSNG(s, coordconv.MGRSCoord{})
+		if err != nil {
+			t.Fatalf("%q: expected no error, got %s", s, err)
+		}
+		if got != expected {
+			t.Errorf("%q: expected %+v, got %+v", s, expected, got)
+		}
+	}
+	for _, s := range []string{"UJ 23480 06470", "23480 06470", "2348006470"} {
+		got, err := coordconv.ParseUSNG(s, reference)
+		if err != nil {
+			t.Fatalf("%q: expected no error, got %s", s, err)
+		}
+		if got != expected {
+			t.Errorf("%q: expected %+v, got %+v", s, expected, got)
+		}
+		if _, err := coordconv.ParseUSNG(s, coordconv.MGRSCoord{}); err == nil {
+			t.Errorf("%q: expected an error without a reference", s)
+		}
+	}
+
+	polar := coordconv.MGRSCoord{Band: 'Z', Column: 'G', Row: 'C'}
+	got, err := coordconv.ParseUSNG("GD 12 34", polar)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if got.String() != "ZGD1234" {
+		t.Errorf("expected ZGD1234, got %s", got.String())
+	}
+
+	for _, s := range []string{"", "  ", "18S UJ 2348 06470", "18S UJ 23480 0647", "18S-UJ 23480 06470",
+		"18S UJ 23480 06470 ZZZ", "18SUJ2348006470ZZZ"} {
+		if _, err := coordconv.ParseUSNG(s, reference); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
+
+func TestUSNGConversion(t *testing.T) {
+	geo := s2.LatLngFromDegrees(38.8895, -77.0352)
+	usng, err := coordconv.DefaultMGRSConverter.ConvertFromGeodeticUSNG(geo, 5, coordconv.USNGSpaced)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if usng != "18S UJ 23486 06483" {
+		t.Errorf("expected 18S UJ 23486 06483, got %s", usng)
+	}
+	geo2, err := coordconv.DefaultMGRSConverter.ConvertToGeodeticUSNG(usng, coordconv.MGRSCoord{})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if d := geo.Distance(geo2).Radians() * ellipsoidSemiMajorAxis; d > 1.5 {
+		t.Errorf("expected %s, got %s", geo, geo2)
+	}
+
+	// USNG letters the squares with the AA pattern even on Clarke 1866,
+	// where MGRS uses the AL pattern
+	clarke1866, _ := coordconv.EllipsoidByCode("CC")
+	clarke, err := coordconv.NewMGRSEllipsoid(clarke1866)
+	if err != nil {
+		t.Fatalf("error creating MGRS converter: %s", err)
+	}
+	mgrs, err := clarke.ConvertFromGeodetic(geo, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if mgrs != "18SUU" {
+		t.Errorf("expected 18SUU, got %s", mgrs)
+	}
+	usng, err = clarke.ConvertFromGeodeticUSNG(geo, 0, coordconv.USNGSpaced)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if usng != "18S UJ" {
+		t.Errorf("expected 18S UJ, got %s", usng)
+	}
+
+	reference, err := coordconv.DefaultMGRSConverter.ConvertFromGeodeticCoord(geo, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	geo3, err := coordconv.DefaultMGRSConverter.ConvertToGeodeticUSNG("23486 06483", reference)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if geo3 != geo2 {
+		t.Errorf("expected %s, got %s", geo2, geo3)
+	}
+}
@@ -23,6 +23,7 @@ type UTM struct {
 	ellipsCode            string
 	utmOverride           int
 	transverseMercatorMap [61]*TransverseMercator
+	datum                 Datum
 }
 
 const utmMinLat = ((-80.5 * math.Pi) / 180.0) // -80.5 degrees in radians
@@ -36,6 +37,7 @@ const utmMaxNorthing = 10000000.0
 func NewUTM() (*UTM, error) {
 	u := &UTM{
 		ellipsCode: "WE",
+		datum:      DatumWGS84,
 	}
 
 	u.semiMajorAxis = 6378137.0
@@ -72,6 +74,7 @@ func NewUTM2(ellipsoidSemiMajorAxis, ellipsoidFlattening float64,
 	ellipsoidCode string, override int) (*UTM, error) {
 	u := &UTM{
 		ellipsCode: ellipsoidCode,
+		datum:      ellipsoidDatum(ellipsoidSemiMajorAxis, ellipsoidFlattening, ellipsoidCode),
 	}
 	invF := 1 / ellipsoidFlattening
 
@@ -116,6 +119,35 @@ func NewUTM2(ellipsoidSemiMajorAxis, ellipsoidFlattening float64,
 	return u, nil
 }
 
+// NewUTMEllipsoid constructs a UTM converter for the given ellipsoid.
+// override is the UTM override zone, 0 indicates no override.
+func NewUTMEllipsoid(ellipsoid Ellipsoid, override int) (*UTM, error) {
+	u, err := NewUTM2(ellipsoid.SemiMajorAxis, ellipsoid.Flattening, ellipsoid.Code, override)
+	if err != nil {
+		return nil, err
+	}
+	u.datum.Ellipsoid = ellipsoid
+	return u, nil
+}
+
+// NewUTMDatum constructs a UTM converter for coordinates on the given datum.
+// override is the UTM override zone, 0 indicates no override.
+func NewUTMDatum(datum Datum, override int) (*UTM, error) {
+	u, err := NewUTMEllipsoid(datum.Ellipsoid, override)
+	if err != nil {
+		return nil, err
+	}
+	u.datum = datum
+	return u, nil
+}
+
+// Datum returns the datum of the converter's UTM coordinates.  Converters
+// constructed from ellipsoid parameters alone are treated as having no shift
+// to WGS84.
+func (u *UTM) Datum() Datum {
+	return u.datum
+}
+
 // ConvertFromGeodetic converts geodetic (latitude and longitude) coordinates
 // to UTM projection (zone, hemisphere, easting and northing) coordinates
 // according to the current ellipsoid and UTM zone override parameters.
@@ -212,7 +244,7 @@ func (u *UTM) ConvertFromGeodetic(geodeticCoordinates s2.LatLng, utmZoneOverride
 		hemisphere = HemisphereNorth
 	}
 	tempGeodeticCoordinates := s2.LatLng{Lng: s1.Angle(longitude), Lat: s1.Angle(latitude)}
-	transverseMercatorCoordinates, err := transverseMercator.convertFromGeodetic(tempGeodeticCoordinates)
+	transverseMercatorCoordinates, err := transverseMercator.ConvertFromGeodetic(tempGeodeticCoordinates)
 	if err != nil {
 		return UTMCoord{}, err
 	}
@@ -264,7 +296,7 @@ func (u *UTM) ConvertToGeodetic(utmCoordinates UTMCoord) (s2.LatLng, error) {
 	}
 
 	transverseMercatorCoordinates := MapCoords{Easting: easting, Northing: northing - FalseNorthing}
-	geodeticCoordinates, err := transverseMercator.convertToGeodetic(transverseMercatorCoordinates)
+	geodeticCoordinates, err := transverseMercator.ConvertToGeodetic(transverseMercatorCoordinates)
 	if err != nil {
 		return s2.LatLng{}, err
 	}
@@ -277,3 +309,71 @@ func (u *UTM) ConvertToGeodetic(utmCoordinates UTMCoord) (s2.LatLng, error) {
 
 	return geodeticCoordinates, nil
 }
+
+// ConvertFromGeodeticFactors converts geodetic (latitude and longitude)
+// coordinates to UTM projection (zone, hemisphere, easting and northing)
+// coordinates and also returns the meridian convergence and point scale
+// factor at the point.
+func (u *UTM) ConvertFromGeodeticFactors(geodeticCoordinates s2.LatLng, utmZoneOverride int) (UTMCoord, GridFactors, error) {
+	utmCoordinates, err := u.ConvertFromGeodetic(geodeticCoordinates, utmZoneOverride)
+	if err != nil {
+		return UTMCoord{}, GridFactors{}, err
+	}
+	transverseMercator := u.transverseMercatorMap[utmCoordinates.Zone]
+	factors := transverseMercator.gridFactors(geodeticCoordinates.Lat.Radians(), geodeticCoordinates.Lng.Radians())
+	return utmCoordinates, factors, nil
+}
+
+// ConvertToGeodeticFactors converts UTM projection (zone, hemisphere, easting
+// and northing) coordinates to geodetic (latitude and longitude) coordinates
+// and also returns the meridian convergence and point scale factor at the
+// point.
+func (u *UTM) ConvertToGeodeticFactors(utmCoordinates UTMCoord) (s2.LatLng, GridFactors, error) {
+	geodeticCoordinates, err := u.ConvertToGeodetic(utmCoordinates)
+	if err != nil {
+		return s2.LatLng{}, GridFactors{}, err
+	}
+	transverseMercator := u.transverseMercatorMap[utmCoordinates.Zone]
+	factors := transverseMercator.gridFactors(geodeticCoordinates.Lat.Radians(), geodeticCoordinates.Lng.Radians())
+	return geodeticCoordinates, factors, nil
+}
+
+// ConvertFromGeodeticDatum converts geodetic coordinates on the given datum
+// to UTM coordinates on the converter's datum.
+func (u *UTM) ConvertFromGeodeticDatum(geodeticCoordinates s2.LatLng, datum Datum, utmZoneOverride int) (UTMCoord, error) {
+	geodeticCoordinates, err := shiftDatum(geodeticCoordinates, datum, u.datum)
+	if err != nil {
+		return UTMCoord{}, err
+	}
+	return u.ConvertFromGeodetic(geodeticCoordinates, utmZoneOverride)
+}
+
+// ConvertToGeodeticDatum converts UTM coordinates on the converter's datum to
+// geodetic coordinates on the given datum.
+func (u *UTM) ConvertToGeodeticDatum(utmCoordinates UTMCoord, datum Datum) (s2.LatLng, error) {
+	geodeticCoordinates, err := u.ConvertToGeodetic(utmCoordinates)
+	if err != nil {
+		return s2.LatLng{}, err
+	}
+	return shiftDatum(geodeticCoordinates, u.datum, datum)
+}
+
+// ConvertFromGeodeticGridShift converts geodetic coordinates on the grid
+// shift's source datum to UTM coordinates on its target datum.
+func (u *UTM) ConvertFromGeodeticGridShift(geodeticCoordinates s2.LatLng, gridShift *GridShift, utmZoneOverride int) (UTMCoord, error) {
+	geodeticCoordinates, err := gridShift.Forward(geodeticCoordinates)
+	if err != nil {
+		return UTMCoord{}, err
+	}
+	return u.ConvertFromGeodetic(geodeticCoordinates, utmZoneOverride)
+}
+
+// ConvertToGeodeticGridShift converts UTM coordinates on the grid shift's
+// target datum to geodetic coordinates on its source datum.
+func (u *UTM) ConvertToGeodeticGridShift(utmCoordinates UTMCoord, gridShift *GridShift) (s2.LatLng, error) {
+	geodeticCoordinates, err := u.ConvertToGeodetic(utmCoordinates)
+	if err != nil {
+		return s2.LatLng{}, err
+	}
+	return gridShift.Inverse(geodeticCoordinates)
+}
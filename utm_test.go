@@ -1,6 +1,7 @@
 package coordconv_test
 
 import (
+	"math"
 	"testing"
 
 	"github.com/golang/geo/s2"
@@ -30,3 +31,36 @@ func TestUTMRoundTrip(t *testing.T) {
 		}
 	}
 }
+
+func TestUTMFactors(t *testing.T) {
+	utm, err := coordconv.NewUTM()
+	if err != nil {
+		t.Fatalf("error creating UTM converter: %s", err)
+	}
+	for lng := -180.0; lng < 180; lng += 1.25 {
+		for lat := -79.0; lat < 84; lat += 2.5 {
+			geo := s2.LatLngFromDegrees(lat, lng)
+			uc, factors, err := utm.ConvertFromGeodeticFactors(geo, 0)
+			if err != nil {
+				t.Fatalf("expected no error at %s, got %s", geo, err)
+			}
+			forward := func(geo s2.LatLng) (coordconv.MapCoords, error) {
+				c, err := utm.ConvertFromGeodetic(geo, uc.Zone)
+				return coordconv.MapCoords{Easting: c.Easting, Northing: c.Northing}, err
+			}
+			checkGridFactors(t, "utm", forward, ellipsoidSemiMajorAxis, ellipsoidFlattening, geo, factors)
+			if factors.PointScale < 0.9996-1e-12 || factors.PointScale > 1.0011 {
+				t.Errorf("expected point scale within the zone limits at %s, got %f", geo, factors.PointScale)
+			}
+
+			_, inverseFactors, err := utm.ConvertToGeodeticFactors(uc)
+			if err != nil {
+				t.Fatalf("expected no error at %s, got %s", geo, err)
+			}
+			if math.Abs(math.Remainder(inverseFactors.Convergence.Radians()-factors.Convergence.Radians(), 2*math.Pi)) > 1e-9 ||
+				math.Abs(inverseFactors.PointScale-factors.PointScale) > 1e-9 {
+				t.Errorf("expected inverse factors %v at %s, got %v", factors, geo, inverseFactors)
+			}
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package coordconv
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// WebMercatorTileSize is the width and height of a slippy map tile in pixels.
+const WebMercatorTileSize = 256
+
+// WebMercatorMaxZoom is the deepest supported slippy map zoom level.
+const WebMercatorMaxZoom = 30
+
+// webMercatorRadius is the radius of the sphere used by Web Mercator, the
+// WGS84 semi-major axis.
+const webMercatorRadius = 6378137.0
+
+// WebMercatorMaxLatitude is the latitude, in degrees, of the north and south
+// edges of the square slippy map.
+const WebMercatorMaxLatitude = 85.05112877980659
+
+// WebMercator provides conversions between WGS84 geodetic coordinates
+// (latitude and longitude) and Web Mercator (EPSG:3857, Popular Visualisation
+// Pseudo Mercator) coordinates, the spherical Mercator projection used by web
+// maps.  It also addresses the slippy map tiles of those web maps.
+type WebMercator struct {
+	semiMajorAxis float64
+}
+
+// NewWebMercator constructs a Web Mercator converter.
+func NewWebMercator() *WebMercator {
+	return &WebMercator{semiMajorAxis: webMercatorRadius}
+}
+
+// Tile is a slippy map tile.  X increases eastward from the antimeridian and Y
+// increases southward from the north edge of the map, each from 0 to
+// 2^Zoom - 1.
+type Tile struct {
+	Zoom int
+	X    int
+	Y    int
+}
+
+// TileCoord is a position on a slippy map, as a tile and the offset in pixels
+// from the north west corner of the tile.
+type TileCoord struct {
+	Tile   Tile
+	PixelX float64
+	PixelY float64
+}
+
+// ConvertFromGeodetic converts geodetic coordinates (latitude and longitude) to
+// Web Mercator coordinates (easting and northing).
+func (w *WebMercator) ConvertFromGeodetic(geodeticCoordinates s2.LatLng) (MapCoords, error) {
+	longitude := geodeticCoordinates.Lng.Radians()
+	latitude := geodeticCoordinates.Lat.Radians()
+
+	if (latitude < -mercatorMaxLat) || (latitude > mercatorMaxLat) {
+		return MapCoords{}, errors.New("latitude out of range")
+	}
+	if (longitude < -math.Pi) || (longitude > 2*math.Pi) {
+		return MapCoords{}, errors.New("longitude out of range")
+	}
+	if longitude > math.Pi {
+		longitude -= 2 * math.Pi
+	}
+	return MapCoords{
+		Easting:  w.semiMajorAxis * longitude,
+		Northing: w.semiMajorAxis * math.Log(math.Tan(math.Pi/4+latitude/2)),
+	}, nil
+}
+
+// ConvertToGeodetic converts Web Mercator coordinates (easting and northing) to
+// geodetic coordinates (latitude and longitude).
+func (w *WebMercator) ConvertToGeodetic(mapProjectionCoordinates MapCoords) (s2.LatLng, error) {
+	easting := mapProjectionCoordinates.Easting
+	northing := mapProjectionCoordinates.Northing
+
+	if math.Abs(easting) > math.Pi*w.semiMajorAxis {
+		return s2.LatLng{}, errors.New("easting out of range")
+	}
+	if math.IsNaN(northing) || math.IsInf(northing, 0) {
+		return s2.LatLng{}, errors.New("northing out of range")
+	}
+	latitude := math.Pi/2 - 2*math.Atan(math.Exp(-northing/w.semiMajorAxis))
+	longitude := easting / w.semiMajorAxis
+	return s2.LatLng{Lat: s1.Angle(latitude), Lng: s1.Angle(longitude)}, nil
+}
+
+// ConvertToTileCoord converts Web Mercator coordinates (easting and northing)
+// to a position on the slippy map at the given zoom level.  Points on the east
+// and south edges of the map are placed in the last tile.
+func (w *WebMercator) ConvertToTileCoord(mapProjectionCoordinates MapCoords, zoom int) (TileCoord, error) {
+	if (zoom < 0) || (zoom > WebMercatorMaxZoom) {
+		return TileCoord{}, errors.New("zoom level out of range")
+	}
+	halfWidth := math.Pi * w.semiMajorAxis
+	easting := mapProjectionCoordinates.Easting
+	northing := mapProjectionCoordinates.Northing
+	if !(math.Abs(easting) <= halfWidth) {
+		return TileCoord{}, errors.New("easting out of range")
+	}
+	if !(math.Abs(northing) <= halfWidth) {
+		return TileCoord{}, errors.New("northing out of range")
+	}
+
+	tiles := 1 << uint(zoom)
+	size := float64(tiles * WebMercatorTileSize)
+	x := (easting + halfWidth) / (2 * halfWidth) * size
+	y := (halfWidth - northing) / (2 * halfWidth) * size
+	tile := Tile{
+		Zoom: zoom,
+		X:    int(math.Min(math.Floor(x/WebMercatorTileSize), float64(tiles-1))),
+		Y:    int(math.Min(math.Floor(y/WebMercatorTileSize), float64(tiles-1))),
+	}
+	return TileCoord{
+		Tile:   tile,
+		PixelX: x - float64(tile.X*WebMercatorTileSize),
+		PixelY: y - float64(tile.Y*WebMercatorTileSize),
+	}, nil
+}
+
+// ConvertFromTileCoord converts a position on the slippy map to Web Mercator
+// coordinates (easting and northing).
+func (w *WebMercator) ConvertFromTileCoord(tileCoordinates TileCoord) (MapCoords, error) {
+	tile := tileCoordinates.Tile
+	if err := tile.check(); err != nil {
+		return MapCoords{}, err
+	}
+	halfWidth := math.Pi * w.semiMajorAxis
+	size := float64(int(1<<uint(tile.Zoom)) * WebMercatorTileSize)
+	x := float64(tile.X*WebMercatorTileSize) + tileCoordinates.PixelX
+	y := float64(tile.Y*WebMercatorTileSize) + tileCoordinates.PixelY
+	if (x < 0) || (x > size) || (y < 0) || (y > size) {
+		return MapCoords{}, errors.New("pixel out of range")
+	}
+	return MapCoords{
+		Easting:  x/size*2*halfWidth - halfWidth,
+		Northing: halfWidth - y/size*2*halfWidth,
+	}, nil
+}
+
+// ConvertFromGeodeticTile converts geodetic coordinates (latitude and
+// longitude) to a position on the slippy map at the given zoom level.
+func (w *WebMercator) ConvertFromGeodeticTile(geodeticCoordinates s2.LatLng, zoom int) (TileCoord, error) {
+	mapCoords, err := w.ConvertFromGeodetic(geodeticCoordinates)
+	if err != nil {
+		return TileCoord{}, err
+	}
+	return w.ConvertToTileCoord(mapCoords, zoom)
+}
+
+// ConvertToGeodeticTile converts a position on the slippy map to geodetic
+// coordinates (latitude and longitude).
+func (w *WebMercator) ConvertToGeodeticTile(tileCoordinates TileCoord) (s2.LatLng, error) {
+	mapCoords, err := w.ConvertFromTileCoord(tileCoordinates)
+	if err != nil {
+		return s2.LatLng{}, err
+	}
+	return w.ConvertToGeodetic(mapCoords)
+}
+
+// TileBounds returns the Web Mercator coordinates of the south west and north
+// east corners of a tile.
+func (w *WebMercator) TileBounds(tile Tile) (MapCoords, MapCoords, error) {
+	southWest, err := w.ConvertFromTileCoord(TileCoord{Tile: tile, PixelY: WebMercatorTileSize})
+	if err != nil {
+		return MapCoords{}, MapCoords{}, err
+	}
+	northEast, err := w.ConvertFromTileCoord(TileCoord{Tile: tile, PixelX: WebMercatorTileSize})
+	if err != nil {
+		return MapCoords{}, MapCoords{}, err
+	}
+	return southWest, northEast, nil
+}
+
+// Parameters returns the sphere and Web Mercator projection parameters.
+func (w *WebMercator) Parameters() ProjectionParameters {
+	return ProjectionParameters{
+		SemiMajorAxis: w.semiMajorAxis,
+		ScaleFactor:   1.0,
+	}
+}
+
+// QuadKey returns the Bing Maps quadkey of the tile, a string of one base 4
+// digit per zoom level.  The quadkey of the zoom 0 tile is empty.
+func (t Tile) QuadKey() string {
+	var sb strings.Builder
+	for i := t.Zoom; i > 0; i-- {
+		digit := byte('0')
+		mask := 1 << uint(i-1)
+		if t.X&mask != 0 {
+			digit++
+		}
+		if t.Y&mask != 0 {
+			digit += 2
+		}
+		sb.WriteByte(digit)
+	}
+	return sb.String()
+}
+
+// TileFromQuadKey returns the tile addressed by a Bing Maps quadkey.
+func TileFromQuadKey(quadKey string) (Tile, error) {
+	if len(quadKey) > WebMercatorMaxZoom {
+		return Tile{}, errors.New("zoom level out of range")
+	}
+	tile := Tile{Zoom: len(quadKey)}
+	for i := 0; i < len(quadKey); i++ {
+		mask := 1 << uint(len(quadKey)-i-1)
+		switch quadKey[i] {
+		case '0':
+		case '1':
+			tile.X |= mask
+		case '2':
+			tile.Y |= mask
+		case '3':
+			tile.X |= mask
+			tile.Y |= mask
+		default:
+			return Tile{}, errors.New("invalid quadkey digit")
+		}
+	}
+	return tile, nil
+}
+
+// check returns an error if the tile isn't on the slippy map.
+func (t Tile) check() error {
+	if (t.Zoom < 0) || (t.Zoom > WebMercatorMaxZoom) {
+		return errors.New("zoom level out of range")
+	}
+	tiles := 1 << uint(t.Zoom)
+	if (t.X < 0) || (t.X >= tiles) || (t.Y < 0) || (t.Y >= tiles) {
+		return errors.New("tile out of range")
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package coordconv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/tzneal/coordconv"
+)
+
+func TestWebMercator(t *testing.T) {
+	wm := coordconv.NewWebMercator()
+
+	// EPSG Guidance Note 7-2, WGS 84 / Pseudo-Mercator
+	geo := s2.LatLngFromDegrees(24+22.0/60+54.433/3600, -100-20.0/60)
+	expected := coordconv.MapCoords{Easting: -11169055.58, Northing: 2800000.00}
+	mc, err := wm.ConvertFromGeodetic(geo)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(mc.Easting-expected.Easting) > 0.01 || math.Abs(mc.Northing-expected.Northing) > 0.01 {
+		t.Errorf("expected %v, got %v", expected, mc)
+	}
+	geo2, err := wm.ConvertToGeodetic(expected)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if geo.Distance(geo2).Radians()*ellipsoidSemiMajorAxis > 0.01 {
+		t.Errorf("expected %s, got %s", geo, geo2)
+	}
+
+	// the map is square
+	corner, err := wm.ConvertFromGeodetic(s2.LatLngFromDegrees(coordconv.WebMercatorMaxLatitude, 180))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if math.Abs(corner.Easting-corner.Northing) > 1e-6 {
+		t.Errorf("expected the north east corner on the diagonal, got %v", corner)
+	}
+}
+
+func TestWebMercatorTiles(t *testing.T) {
+	wm := coordconv.NewWebMercator()
+
+	testCases := []struct {
+		geo      s2.LatLng
+		zoom     int
+		expected coordconv.Tile
+		quadKey  string
+	}{
+		{s2.LatLngFromDegrees(0, 0), 0, coordconv.Tile{Zoom: 0, X: 0, Y: 0}, ""},
+		{s2.LatLngFromDegrees(51.5074, -0.1278), 10, coordconv.Tile{Zoom: 10, X: 511, Y: 340}, "0313131311"},
+		{s2.LatLngFromDegrees(-33.8688, 151.2093), 12, coordconv.Tile{Zoom: 12, X: 3768, Y: 2457}, "311230133002"},
+		{s2.LatLngFromDegrees(coordconv.WebMercatorMaxLatitude, 180), 4, coordconv.Tile{Zoom: 4, X: 15, Y: 0}, "1111"},
+		{s2.LatLngFromDegrees(-coordconv.WebMercatorMaxLatitude, -180), 4, coordconv.Tile{Zoom: 4, X: 0, Y: 15}, "2222"},
+	}
+
+	for _, tc := range testCases {
+		tileCoord, err := wm.ConvertFromGeodeticTile(tc.geo, tc.zoom)
+		if err != nil {
+			t.Fatalf("expected no error at %s, got %s", tc.geo, err)
+		}
+		if tileCoord.Tile != tc.expected {
+			t.Errorf("expected tile %v at %s, got %v", tc.expected, tc.geo, tileCoord.Tile)
+		}
+		if tileCoord.PixelX < 0 || tileCoord.PixelX > coordconv.WebMercatorTileSize ||
+			tileCoord.PixelY < 0 || tileCoord.PixelY > coordconv.WebMercatorTileSize {
+			t.Errorf("expected a pixel within the tile, got %v", tileCoord)
+		}
+		if quadKey := tileCoord.Tile.QuadKey(); quadKey != tc.quadKey {
+			t.Errorf("expected quadkey %q for %v, got %q", tc.quadKey, tileCoord.Tile, quadKey)
+		}
+		tile, err := coordconv.TileFromQuadKey(tc.quadKey)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if tile != tc.expected {
+			t.Errorf("expected tile %v from quadkey %q, got %v", tc.expected, tc.quadKey, tile)
+		}
+
+		geo, err := wm.ConvertToGeodeticTile(tileCoord)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if geo.Distance(tc.geo).Radians()*ellipsoidSemiMajorAxis > 1e-6 {
+			t.Errorf("expected %s, got %s", tc.geo, geo)
+		}
+
+		southWest, northEast, err := wm.TileBounds(tileCoord.Tile)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		mc, err := wm.ConvertFromGeodetic(tc.geo)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if mc.Easting < southWest.Easting-1e-6 || mc.Easting > northEast.Easting+1e-6 ||
+			mc.Northing < southWest.Northing-1e-6 || mc.Northing > northEast.Northing+1e-6 {
+			t.Errorf("expected %v within the tile bounds %v %v", mc, southWest, northEast)
+		}
+	}
+
+	// Bing Maps Tile System example
+	if quadKey := (coordconv.Tile{Zoom: 3, X: 3, Y: 5}).QuadKey(); quadKey != "213" {
+		t.Errorf("expected quadkey 213, got %s", quadKey)
+	}
+
+	if _, err := coordconv.TileFromQuadKey("0124"); err == nil {
+		t.Errorf("expected an error for an invalid quadkey")
+	}
+	if _, err := wm.ConvertFromGeodeticTile(s2.LatLngFromDegrees(86, 0), 3); err == nil {
+		t.Errorf("expected an error north of the map")
+	}
+	if _, err := wm.ConvertFromGeodeticTile(s2.LatLngFromDegrees(0, 0), -1); err == nil {
+		t.Errorf("expected an error for a negative zoom level")
+	}
+	if _, err := wm.ConvertToGeodeticTile(coordconv.TileCoord{Tile: coordconv.Tile{Zoom: 2, X: 4, Y: 0}}); err == nil {
+		t.Errorf("expected an error for a tile off the map")
+	}
+}
@@ -12,10 +12,8 @@ var DefaultUTMConverter *UTM
 var DefaultUPSConverter *UPS
 
 func init() {
-	const semiMajorAxis = 6378137
-	const flattening = 1 / 298.257223563
 	var err error
-	DefaultMGRSConverter, err = NewMGRS(semiMajorAxis, flattening, "WE")
+	DefaultMGRSConverter, err = NewMGRSDatum(DatumWGS84)
 	if err != nil {
 		panic(fmt.Sprintf("error constructing WGS84 MGRS converter: %s", err))
 	}
@@ -23,7 +21,7 @@ func init() {
 	if err != nil {
 		panic(fmt.Sprintf("error constructing WGS84 UTM converter: %s", err))
 	}
-	DefaultUPSConverter, err = NewUPS(semiMajorAxis, flattening)
+	DefaultUPSConverter, err = NewUPSDatum(DatumWGS84)
 	if err != nil {
 		panic(fmt.Sprintf("error constructing WGS84 UPS converter: %s", err))
 	}